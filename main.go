@@ -13,8 +13,10 @@ import (
 	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	source "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	sourcev1beta2 "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta2"
 	"github.com/harvester/vm-import-controller/pkg/controllers"
 	"github.com/harvester/vm-import-controller/pkg/server"
+	"github.com/harvester/vm-import-controller/pkg/webhook"
 )
 
 func init() {
@@ -23,6 +25,9 @@ func init() {
 	if err = source.AddToScheme(scheme); err != nil {
 		log.Fatalf("failed to add source scheme, %v", err)
 	}
+	if err = sourcev1beta2.AddToScheme(scheme); err != nil {
+		log.Fatalf("failed to add source v1beta2 scheme, %v", err)
+	}
 	if err = harvesterv1beta1.AddToScheme(scheme); err != nil {
 		log.Fatalf("failed to add harvesterv1beta1 scheme, %v", err)
 	}
@@ -57,6 +62,14 @@ func main() {
 		return server.NewServer(egctx)
 	})
 
+	eg.Go(func() error {
+		return server.NewMetricsServer(egctx)
+	})
+
+	eg.Go(func() error {
+		return webhook.Start(egctx, config)
+	})
+
 	err = eg.Wait()
 	if err != nil {
 		log.Fatal(err)
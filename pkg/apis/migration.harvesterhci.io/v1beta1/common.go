@@ -14,6 +14,14 @@ const (
 	ClusterErrorCondition condition.Cond = "ClusterError"
 )
 
+// The reasons that are reported by the `ClusterReady` condition of a source.
+const (
+	ReasonSourceVerified           = "SourceVerified"
+	ReasonCredentialsNotFound      = "CredentialsNotFound"
+	ReasonConnectionFailed         = "ConnectionFailed"
+	ReasonSourceVerificationFailed = "SourceVerificationFailed"
+)
+
 const (
 	KindVmwareSource    string = "vmwaresource"
 	KindOvaSource       string = "ovasource"
@@ -33,4 +41,104 @@ type SourceInterface interface {
 
 	// GetOptions returns the additional configuration options of the Source.
 	GetOptions() interface{}
+
+	// GetNetworks returns the networks that are available in the Source.
+	GetNetworks() []SourceNetwork
+
+	// GetStorageClassMapping returns the rules that map the disks of the
+	// Source to storage classes.
+	GetStorageClassMapping() []StorageClassMapping
+
+	// GetResourcePolicy returns the resource policy of the VMs that are
+	// imported from the Source, or nil if there is none.
+	GetResourcePolicy() *ResourcePolicy
 }
+
+// ResourcePolicy configures the resource requests, the memory overhead and
+// the hugepages of imported VMs. The limits of an imported VM are derived
+// from the size of the source VM.
+type ResourcePolicy struct {
+	// +optional
+	// CPURequestPercentage is the CPU request of the VM as a percentage of
+	// its CPU limit. Defaults to the CPU ratio of the `overcommit-config`
+	// setting of Harvester.
+	CPURequestPercentage *int32 `json:"cpuRequestPercentage,omitempty" wrangler:"min=1,max=100"`
+
+	// +optional
+	// MemoryRequestPercentage is the memory request of the VM as a
+	// percentage of its memory limit. Defaults to the memory ratio of the
+	// `overcommit-config` setting of Harvester.
+	MemoryRequestPercentage *int32 `json:"memoryRequestPercentage,omitempty" wrangler:"min=1,max=100"`
+
+	// +optional
+	// ReservedMemory is the memory that is reserved for the virtualization
+	// overhead, e.g. "256Mi". It is added to the memory limit of the VM, so
+	// the guest keeps the memory of the source VM. If empty, Harvester
+	// reserves its default overhead from the memory of the guest.
+	ReservedMemory string `json:"reservedMemory,omitempty"`
+
+	// +optional
+	// HugepageSize backs the memory of the VM with hugepages of the given
+	// size.
+	HugepageSize *string `json:"hugepageSize,omitempty" wrangler:"type=string,options=2Mi|1Gi"`
+}
+
+// StorageClassMapping maps the disks of a source that are matched by the
+// datastore (VMware) or Cinder volume type (OpenStack) pattern to a storage
+// class. The patterns use the shell glob syntax.
+type StorageClassMapping struct {
+	// +optional
+	// Datastore is the pattern that is matched against the datastore of
+	// a VMware disk.
+	Datastore string `json:"datastore,omitempty"`
+
+	// +optional
+	// VolumeType is the pattern that is matched against the Cinder volume
+	// type of an OpenStack disk.
+	VolumeType string `json:"volumeType,omitempty"`
+
+	StorageClass string `json:"storageClass" wrangler:"required,minLength=1,maxLength=253,validChars=a-z0-9.-"`
+}
+
+// SourceNetwork describes a network that is available in a source. Its name
+// can be used as `sourceNetwork` of a network mapping.
+type SourceNetwork struct {
+	Name string `json:"name"`
+
+	// +optional
+	// ID is the unique identifier of the network in the source, e.g. the
+	// managed object ID in VMware or the network ID in OpenStack.
+	ID string `json:"id,omitempty"`
+
+	// +optional
+	// Type is the type of the network. In VMware this is `Network` for
+	// standard port groups, `DistributedVirtualPortgroup` for distributed
+	// port groups and `OpaqueNetwork` for NSX segments. In OpenStack this is
+	// the provider network type, e.g. `vlan`, `vxlan` or `flat`.
+	Type string `json:"type,omitempty"`
+
+	// +optional
+	// VlanID is the VLAN ID of the network if it is known and the network
+	// is a VLAN network.
+	VlanID int32 `json:"vlanID,omitempty"`
+
+	// +optional
+	// VlanType is the VLAN configuration of a VMware network: `none` for
+	// untagged networks, `vlan` for a single VLAN, `trunk` for VLAN trunks
+	// or guest tagging, `pvlan` for private VLANs and `unknown` if it
+	// cannot be determined. The VLAN ID is only set for `vlan`.
+	VlanType string `json:"vlanType,omitempty"`
+
+	// +optional
+	// SegmentationID is the segmentation ID of an OpenStack network, e.g.
+	// the VLAN ID or the VXLAN VNI.
+	SegmentationID int32 `json:"segmentationID,omitempty"`
+}
+
+const (
+	VlanTypeNone    = "none"
+	VlanTypeVlan    = "vlan"
+	VlanTypeTrunk   = "trunk"
+	VlanTypePrivate = "pvlan"
+	VlanTypeUnknown = "unknown"
+)
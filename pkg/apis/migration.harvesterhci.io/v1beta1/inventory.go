@@ -0,0 +1,143 @@
+package v1beta1
+
+import (
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+)
+
+const (
+	InventoryReadyCondition condition.Cond = "InventoryReady"
+
+	// The reasons that are reported by the `InventoryReady` condition.
+	ReasonInventoryRefreshed     = "InventoryRefreshed"
+	ReasonSourceNotReady         = "SourceNotReady"
+	ReasonInventoryRefreshFailed = "InventoryRefreshFailed"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceInventory lists the virtual machines that can be imported from a
+// source. It is created and refreshed periodically by the controller for
+// every source that is ready.
+type SourceInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              SourceInventorySpec   `json:"spec"`
+	Status            SourceInventoryStatus `json:"status,omitempty"`
+}
+
+type SourceInventorySpec struct {
+	// SourceCluster is the source whose virtual machines are listed.
+	SourceCluster corev1.ObjectReference `json:"sourceCluster" wrangler:"required"`
+}
+
+type SourceInventoryStatus struct {
+	// +optional
+	Conditions []common.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	// ObservedSourceGeneration is the generation of the source spec the
+	// inventory has been last refreshed for.
+	ObservedSourceGeneration int64 `json:"observedSourceGeneration,omitempty"`
+
+	// +optional
+	// ObservedSecretVersion is the resource version of the credentials
+	// secret the inventory has been last refreshed with.
+	ObservedSecretVersion string `json:"observedSecretVersion,omitempty"`
+
+	// +optional
+	// LastRefreshTime is the time of the last successful refresh.
+	LastRefreshTime string `json:"lastRefreshTime,omitempty"`
+
+	// +optional
+	// VirtualMachineCount is the number of virtual machines found in the
+	// source. It can be larger than the number of listed virtual machines if
+	// the inventory has been truncated.
+	VirtualMachineCount int32 `json:"virtualMachineCount,omitempty"`
+
+	// +optional
+	// Truncated is set if not all virtual machines of the source are listed
+	// to keep the size of the object within the limits of the API server.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// +optional
+	VirtualMachines []InventoryVirtualMachine `json:"virtualMachines,omitempty"`
+}
+
+// InventoryVirtualMachine describes a virtual machine of a source. The value
+// of `name` can be used as `virtualMachineName` and the value of `folder` as
+// `folder` of a `VirtualMachineImport`.
+type InventoryVirtualMachine struct {
+	Name string `json:"name"`
+
+	// +optional
+	// ID is the unique identifier of the VM in the source, e.g. the
+	// instance UUID in VMware or the server ID in OpenStack.
+	ID string `json:"id,omitempty"`
+
+	// +optional
+	// Folder is the VMware folder or the OpenStack project of the VM.
+	Folder string `json:"folder,omitempty"`
+
+	// +optional
+	// PowerState is the power state of the VM as reported by the source,
+	// e.g. `poweredOn` in VMware or `ACTIVE` in OpenStack.
+	PowerState string `json:"powerState,omitempty"`
+
+	// +optional
+	CPUs int32 `json:"cpus,omitempty"`
+
+	// +optional
+	MemoryMiB int64 `json:"memoryMiB,omitempty"`
+
+	// +optional
+	// Firmware is either `bios` or `efi`.
+	Firmware string `json:"firmware,omitempty"`
+
+	// +optional
+	GuestOS string `json:"guestOS,omitempty"`
+
+	// +optional
+	Disks []InventoryDisk `json:"disks,omitempty"`
+
+	// +optional
+	NetworkInterfaces []InventoryNetworkInterface `json:"networkInterfaces,omitempty"`
+}
+
+type InventoryDisk struct {
+	Name string `json:"name"`
+
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+}
+
+type InventoryNetworkInterface struct {
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+
+	// +optional
+	// Network is the name of the source network, which can be used as
+	// `sourceNetwork` of a network mapping.
+	Network string `json:"network,omitempty"`
+
+	// +optional
+	Model string `json:"model,omitempty"`
+}
+
+// The firmware types of an inventory VM.
+const (
+	FirmwareBIOS = "bios"
+	FirmwareEFI  = "efi"
+)
+
+func (i *SourceInventory) NamespacedName() string {
+	return types.NamespacedName{
+		Namespace: i.Namespace,
+		Name:      i.Name,
+	}.String()
+}
@@ -24,25 +24,63 @@ type OpenstackSource struct {
 }
 
 type OpenstackSourceSpec struct {
-	EndpointAddress        string                 `json:"endpoint"`
-	Region                 string                 `json:"region"`
-	Credentials            corev1.SecretReference `json:"credentials"`
+	EndpointAddress        string                 `json:"endpoint" wrangler:"required,minLength=1"`
+	Region                 string                 `json:"region" wrangler:"required,minLength=1"`
+	Credentials            corev1.SecretReference `json:"credentials" wrangler:"required"`
 	OpenstackSourceOptions `json:",inline"`
+
+	// +optional
+	// StorageClassMapping maps the disks to storage classes by their
+	// Cinder volume type. The first matching rule is used for each disk.
+	StorageClassMapping []StorageClassMapping `json:"storageClassMapping,omitempty"`
+
+	// +optional
+	// ResourcePolicy configures the resources of the VMs that are imported
+	// from this source. The resource policy of a VirtualMachineImport takes
+	// precedence.
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
 }
 
 type OpenstackSourceStatus struct {
 	Status ClusterStatus `json:"status,omitempty"`
 	// +optional
 	Conditions []common.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	// ObservedGeneration is the generation of the spec that has been
+	// verified last.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	// ObservedSecretVersion is the resource version of the credentials
+	// secret that has been verified last.
+	ObservedSecretVersion string `json:"observedSecretVersion,omitempty"`
+
+	// +optional
+	// LastVerifiedTime is the time of the last verification of the source.
+	LastVerifiedTime string `json:"lastVerifiedTime,omitempty"`
+
+	// +optional
+	// VerificationLatency is the time the last verification took.
+	VerificationLatency *metav1.Duration `json:"verificationLatency,omitempty"`
+
+	// +optional
+	// Version is the version of the Keystone API reported by the source.
+	Version string `json:"version,omitempty"`
+
+	// +optional
+	// Networks are the networks available in the source. They are updated
+	// with every successful verification.
+	Networks []SourceNetwork `json:"networks,omitempty"`
 }
 
 type OpenstackSourceOptions struct {
 	// +optional
 	// The number of max. retries for uploading an image.
-	UploadImageRetryCount int `json:"uploadImageRetryCount,omitempty"`
+	UploadImageRetryCount int `json:"uploadImageRetryCount,omitempty" wrangler:"min=0,max=100"`
 	// +optional
 	// The upload retry delay in seconds.
-	UploadImageRetryDelay int `json:"uploadImageRetryDelay,omitempty"`
+	UploadImageRetryDelay int `json:"uploadImageRetryDelay,omitempty" wrangler:"min=0,max=3600"`
 }
 
 func (s *OpenstackSource) NamespacedName() string {
@@ -68,6 +106,18 @@ func (s *OpenstackSource) GetKind() string {
 	return KindOpenstackSource
 }
 
+func (s *OpenstackSource) GetNetworks() []SourceNetwork {
+	return s.Status.Networks
+}
+
+func (s *OpenstackSource) GetStorageClassMapping() []StorageClassMapping {
+	return s.Spec.StorageClassMapping
+}
+
+func (s *OpenstackSource) GetResourcePolicy() *ResourcePolicy {
+	return s.Spec.ResourcePolicy
+}
+
 func (s *OpenstackSource) GetConnectionInfo() (string, string) {
 	return s.Spec.EndpointAddress, s.Spec.Region
 }
@@ -26,7 +26,7 @@ type OvaSource struct {
 }
 
 type OvaSourceSpec struct {
-	Url              string `json:"url"`
+	Url              string `json:"url" wrangler:"required,minLength=1"`
 	OvaSourceOptions `json:",inline"`
 
 	// The referenced `Secret` should contain the following keys:
@@ -35,12 +35,36 @@ type OvaSourceSpec struct {
 	// - ca.crt: (optional) The CA certificate to verify the identity of the specified server.
 	// +optional
 	Credentials *corev1.SecretReference `json:"credentials,omitempty"`
+
+	// +optional
+	// ResourcePolicy configures the resources of the VMs that are imported
+	// from this source. The resource policy of a VirtualMachineImport takes
+	// precedence.
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
 }
 
 type OvaSourceStatus struct {
 	Status ClusterStatus `json:"status,omitempty"`
 	// +optional
 	Conditions []common.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	// ObservedGeneration is the generation of the spec that has been
+	// verified last.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	// ObservedSecretVersion is the resource version of the credentials
+	// secret that has been verified last.
+	ObservedSecretVersion string `json:"observedSecretVersion,omitempty"`
+
+	// +optional
+	// LastVerifiedTime is the time of the last verification of the source.
+	LastVerifiedTime string `json:"lastVerifiedTime,omitempty"`
+
+	// +optional
+	// VerificationLatency is the time the last verification took.
+	VerificationLatency *metav1.Duration `json:"verificationLatency,omitempty"`
 }
 
 type OvaSourceOptions struct {
@@ -49,7 +73,7 @@ type OvaSourceOptions struct {
 	// The timeout includes connection time, any redirects, and reading the
 	// response body. A timeout of zero means no timeout.
 	// Defaults to 10 minutes.
-	HttpTimeoutSeconds *int `json:"httpTimeoutSeconds,omitempty"`
+	HttpTimeoutSeconds *int `json:"httpTimeoutSeconds,omitempty" wrangler:"min=0"`
 }
 
 func (s *OvaSource) NamespacedName() string {
@@ -75,6 +99,21 @@ func (s *OvaSource) GetKind() string {
 	return KindOvaSource
 }
 
+// GetNetworks returns nil as the networks of an OVA file are not listed.
+func (s *OvaSource) GetNetworks() []SourceNetwork {
+	return nil
+}
+
+// GetStorageClassMapping returns nil as the disks of an OVA file have
+// neither a datastore nor a volume type.
+func (s *OvaSource) GetStorageClassMapping() []StorageClassMapping {
+	return nil
+}
+
+func (s *OvaSource) GetResourcePolicy() *ResourcePolicy {
+	return s.Spec.ResourcePolicy
+}
+
 func (s *OvaSource) GetConnectionInfo() (string, string) {
 	return s.Spec.Url, ""
 }
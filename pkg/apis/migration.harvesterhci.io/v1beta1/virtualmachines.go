@@ -23,32 +23,78 @@ type VirtualMachineImport struct {
 
 // VirtualMachineImportSpec is used to create kubevirt VirtualMachines by exporting VM's from migration clusters.
 type VirtualMachineImportSpec struct {
-	SourceCluster corev1.ObjectReference `json:"sourceCluster"`
+	SourceCluster corev1.ObjectReference `json:"sourceCluster" wrangler:"required"`
 
 	// VirtualMachineName is the name of the virtual machine that will be
 	// imported. It contains the name or ID of the source virtual machine.
 	// Note that these names may not be DNS1123 compliant and will therefore
 	// be sanitized later.
 	// Examples: "vm-1234", "my-VM" or "5649cac7-3871-4bb5-aab6-c72b8c18d0a2"
-	VirtualMachineName string `json:"virtualMachineName"`
+	VirtualMachineName string `json:"virtualMachineName" wrangler:"required,minLength=1"`
 
 	Folder string `json:"folder,omitempty"`
 
 	// If empty, new VirtualMachineImport will be mapped to Management Network.
 	Mapping []NetworkMapping `json:"networkMapping,omitempty"`
+
+	// +optional
+	// AutoCreateNetworks enables the creation of VLAN networks for source
+	// networks that are not mapped but have a known VLAN ID.
+	AutoCreateNetworks *NetworkAutoCreation `json:"autoCreateNetworks,omitempty"`
+
+	// +optional
+	// DefaultDestinationNetwork is the network that all network interfaces
+	// are attached to that are not matched by a network mapping.
+	DefaultDestinationNetwork string `json:"defaultDestinationNetwork,omitempty" wrangler:"maxLength=127,validChars=a-z0-9/-"`
+
+	// +optional
+	// OnUnmapped defines what happens to network interfaces that are neither
+	// matched by a network mapping nor by the default destination network:
+	// - drop: The network interface is not added to the imported VM.
+	// - pod-network: The first of these interfaces is attached to the pod
+	//   network, the others are dropped.
+	// - fail: The preflight checks fail.
+	// Defaults to "drop".
+	OnUnmapped *string `json:"onUnmapped,omitempty" wrangler:"type=string,options=drop|pod-network|fail"`
 	// The default network interface model. This is always used when:
 	// - Auto-detection fails (OpenStack source client does not have auto-detection, therefore this field is used for every network interface).
 	// - No network mapping is provided and a "pod-network" is auto-created.
 	// Defaults to "virtio".
 	DefaultNetworkInterfaceModel *string `json:"defaultNetworkInterfaceModel,omitempty" wrangler:"type=string,options=e1000|e1000e|ne2k_pci|pcnet|rtl8139|virtio"`
 
-	StorageClass string `json:"storageClass,omitempty"`
+	StorageClass string `json:"storageClass,omitempty" wrangler:"maxLength=253,validChars=a-z0-9.-"`
+
+	// +optional
+	// DiskMapping overrides the storage settings of individual disks. The
+	// first matching entry is used for each disk, disks that are not matched
+	// use the storage class of the import.
+	DiskMapping []DiskMapping `json:"diskMapping,omitempty"`
+
+	// +optional
+	// IncludeDisks lists the disks of the source VM that are imported. If
+	// it is empty, all disks are imported.
+	IncludeDisks []DiskSelector `json:"includeDisks,omitempty"`
+
+	// +optional
+	// ExcludeDisks lists the disks of the source VM that are not imported.
+	// It takes precedence over IncludeDisks.
+	ExcludeDisks []DiskSelector `json:"excludeDisks,omitempty"`
+
+	// +optional
+	// CDROMImport defines how the CD-ROM drives of the source VM are
+	// imported:
+	// - none: The CD-ROM drives are not added to the imported VM.
+	// - empty: The CD-ROM drives are added without media.
+	// - image: Attached ISO images are imported as read-only CD-ROMs, the
+	//   other CD-ROM drives are added without media.
+	// Defaults to "none".
+	CDROMImport *string `json:"cdromImport,omitempty" wrangler:"type=string,options=none|empty|image"`
 
 	// The bus type that is used for imported disks if auto-detection fails.
 	// Note, the OpenStack source client does not support auto-detection,
 	// therefore, it always makes use of this field.
 	// Defaults to "virtio".
-	DefaultDiskBusType *kubevirtv1.DiskBus `json:"defaultDiskBusType,omitempty"`
+	DefaultDiskBusType *kubevirtv1.DiskBus `json:"defaultDiskBusType,omitempty" wrangler:"type=string,options=virtio|sata|scsi|usb"`
 
 	// +optional
 	// ForcePowerOff is a flag to indicate whether the VM should be powered
@@ -62,12 +108,98 @@ type VirtualMachineImportSpec struct {
 	// to be shutdown gracefully before a hard power off is triggered.
 	// Defaults to 60 seconds.
 	// Please note that this field only applies to VMware imports.
-	GracefulShutdownTimeoutSeconds int32 `json:"gracefulShutdownTimeoutSeconds,omitempty"`
+	GracefulShutdownTimeoutSeconds int32 `json:"gracefulShutdownTimeoutSeconds,omitempty" wrangler:"min=0,max=3600"`
 
 	// +optional
 	// SkipPreflightChecks allows you to forcefully skip the preflight checks.
 	// Defaults to false.
 	SkipPreflightChecks *bool `json:"skipPreflightChecks"`
+
+	// +optional
+	// CPU configures the CPU model of the imported VM and the CPU settings
+	// that are carried over from the source VM.
+	CPU *CPUOptions `json:"cpu,omitempty"`
+
+	// +optional
+	// ResourcePolicy configures the resource requests, the memory overhead
+	// and the hugepages of the imported VM. Fields that are not set are
+	// taken from the resource policy of the source.
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
+
+	// +optional
+	// MetadataMapping selects the metadata of the source VM, e.g. VMware
+	// tags or OpenStack server metadata, that is added to the imported VM
+	// as labels or annotations. The first matching rule is used for each
+	// key, keys that are not matched are dropped. If empty, all metadata
+	// is added as annotations.
+	MetadataMapping []MetadataMapping `json:"metadataMapping,omitempty"`
+
+	// +optional
+	// Schedule restricts the time at which the source VM may be shut down
+	// and exported. If empty, the import starts immediately.
+	Schedule *ImportSchedule `json:"schedule,omitempty"`
+}
+
+// CPUOptions configures the CPU of the imported VM.
+type CPUOptions struct {
+	// +optional
+	// Model is the CPU model of the imported VM, e.g. "host-passthrough",
+	// "host-model" or a named model like "Skylake-Server". If empty, the
+	// default CPU model of the cluster is used.
+	Model string `json:"model,omitempty" wrangler:"maxLength=63,validChars=a-zA-Z0-9._-"`
+
+	// +optional
+	// PreserveFeatures carries over the CPU features that are hidden or
+	// forced by the source VM (VMware CPUID masks).
+	// Defaults to false.
+	PreserveFeatures *bool `json:"preserveFeatures,omitempty"`
+
+	// +optional
+	// PreservePlacement carries over dedicated CPUs and the guest NUMA
+	// topology of the source VM (VMware latency sensitivity and vNUMA,
+	// OpenStack `hw:cpu_policy`, `hw:numa_nodes` and `hw:mem_page_size`).
+	// The nodes of the cluster must provide the CPU manager and hugepages.
+	// Defaults to false.
+	PreservePlacement *bool `json:"preservePlacement,omitempty"`
+
+	// +optional
+	// PreserveReservations sets the CPU and memory requests of the imported
+	// VM from the reservations of the source VM and its CPU limit from the
+	// CPU limit of the source VM (VMware).
+	// Defaults to false.
+	PreserveReservations *bool `json:"preserveReservations,omitempty"`
+}
+
+// ImportSchedule defines when the disruptive part of an import, i.e. the
+// shutdown of the source VM, is allowed to start.
+// If both fields are set, the import starts as soon as `startAfter` has passed
+// and a window is open, which may be a window that opened before `startAfter`.
+type ImportSchedule struct {
+	// +optional
+	// StartAfter is the earliest point in time the import may start.
+	StartAfter *metav1.Time `json:"startAfter,omitempty"`
+
+	// +optional
+	// Window is a recurring maintenance window in which the import may start.
+	Window *MaintenanceWindow `json:"window,omitempty"`
+}
+
+// MaintenanceWindow describes a recurring time window.
+type MaintenanceWindow struct {
+	// Cron is a standard 5-field cron expression (UTC) that defines when
+	// the window opens, e.g. "0 22 * * SAT".
+	Cron string `json:"cron" wrangler:"required,minLength=1"`
+
+	// Duration defines how long the window stays open, e.g. "4h".
+	Duration metav1.Duration `json:"duration" wrangler:"required"`
+}
+
+// NetworkAutoCreation configures the creation of Harvester VLAN networks
+// for the unmapped source networks of an import.
+type NetworkAutoCreation struct {
+	// ClusterNetwork is the Harvester cluster network the VLAN networks are
+	// created on, e.g. "mgmt".
+	ClusterNetwork string `json:"clusterNetwork" wrangler:"required,minLength=1,maxLength=12,validChars=a-z0-9-"`
 }
 
 // VirtualMachineImportStatus tracks the status of the VirtualMachineImport export from migration and import into the Harvester cluster
@@ -81,6 +213,88 @@ type VirtualMachineImportStatus struct {
 	// target virtual machine that will be created in the Harvester cluster.
 	// The name is DNS1123 compliant.
 	ImportedVirtualMachineName string `json:"importedVirtualMachineName,omitempty"`
+
+	// PhaseHistory records when the import entered and left each phase and
+	// each step of the phases that cover more than one step.
+	// Only the last 20 entries are kept.
+	PhaseHistory []PhaseTiming `json:"phaseHistory,omitempty"`
+
+	// ElapsedTime is the time spent on the import up to the last phase
+	// transition. It is not updated while the import stays in a phase.
+	ElapsedTime *metav1.Duration `json:"elapsedTime,omitempty"`
+
+	// SourceDowntime is the time between the shutdown of the source VM and
+	// the start of the imported VM.
+	SourceDowntime *metav1.Duration `json:"sourceDowntime,omitempty"`
+
+	// DiskCount is the number of disks that are imported.
+	DiskCount int32 `json:"diskCount,omitempty"`
+
+	// Progress is the estimated progress of the import in percent.
+	Progress int32 `json:"progress,omitempty" wrangler:"min=0,max=100"`
+
+	// GeneratedNetworkMapping contains the network mappings that were added
+	// by the controller for the networks it created, see `autoCreateNetworks`.
+	GeneratedNetworkMapping []NetworkMapping `json:"generatedNetworkMapping,omitempty"`
+
+	// NetworkInterfaces lists the network interfaces of the source VM and
+	// the networks they are attached to.
+	NetworkInterfaces []NetworkInterfaceMapping `json:"networkInterfaces,omitempty"`
+
+	// EmptyCDROMDrives lists the CD-ROM drives of the source VM that are
+	// added to the imported VM without media.
+	EmptyCDROMDrives []CDROMDrive `json:"emptyCDROMDrives,omitempty"`
+
+	// SourceVirtualMachine identifies the source VM. It is recorded during
+	// the preflight checks.
+	SourceVirtualMachine *SourceVirtualMachineIdentity `json:"sourceVirtualMachine,omitempty"`
+
+	// ImportTime is the time of the import that is recorded in the
+	// provenance annotations of all imported objects. It is recorded during
+	// the preflight checks.
+	ImportTime string `json:"importTime,omitempty"`
+
+	// StorageClassMapping is the storage class mapping of the source at
+	// the time of the preflight checks. It is used to resolve the storage
+	// class of the disks, so that later changes of the source do not affect
+	// the import.
+	StorageClassMapping []StorageClassMapping `json:"storageClassMapping,omitempty"`
+}
+
+// SourceVirtualMachineIdentity identifies a VM in its source.
+type SourceVirtualMachineIdentity struct {
+	// ID is the unique identifier of the VM in the source, e.g. the
+	// instance UUID in VMware, the server ID in OpenStack or the ID of the
+	// virtual system in the OVF descriptor.
+	ID string `json:"id,omitempty"`
+	// UUID is the BIOS UUID of the VM (VMware).
+	UUID string `json:"uuid,omitempty"`
+	// Reference is the managed object reference of the VM, e.g. "vm-42"
+	// (VMware).
+	Reference string `json:"reference,omitempty"`
+}
+
+// CDROMDrive describes a CD-ROM drive of the source VM.
+type CDROMDrive struct {
+	// DeviceID identifies the drive in the source.
+	DeviceID string             `json:"deviceID,omitempty"`
+	BusType  kubevirtv1.DiskBus `json:"busType" wrangler:"type=string,options=sata|scsi"`
+}
+
+// PhaseTiming contains the entry and exit timestamps of an import phase.
+type PhaseTiming struct {
+	// Phase is the status of the import during that phase. An empty phase
+	// denotes the preflight checks.
+	Phase ImportStatus `json:"phase"`
+	// +optional
+	// Step names the step of the import within the phase, if the phase
+	// covers more than one step, e.g. `Preflight`, `ShutdownWait` or
+	// `Export`.
+	Step string `json:"step,omitempty"`
+	// The time the import entered the phase.
+	EnteredTime string `json:"enteredTime"`
+	// The time the import left the phase. Empty for the current phase.
+	ExitedTime string `json:"exitedTime,omitempty"`
 }
 
 // DiskInfo contains the information about associated Disk in the Import migration.
@@ -99,17 +313,153 @@ type DiskInfo struct {
 	DiskRoute           string             `json:"diskRoute,omitempty"`
 	VirtualMachineImage string             `json:"VirtualMachineImage,omitempty"`
 	DiskConditions      []common.Condition `json:"diskConditions,omitempty"`
-	BusType             kubevirtv1.DiskBus `json:"busType" default:"virtio"`
+	BusType             kubevirtv1.DiskBus `json:"busType" default:"virtio" wrangler:"type=string,options=virtio|sata|scsi|usb"`
+	// Index is the position of the disk in the source VM, starting at 0.
+	Index int32 `json:"index"`
+	// DeviceID identifies the disk in the source. For VMware this is the
+	// type name of the controller followed by its bus number and the unit
+	// number of the disk, e.g. `VirtualLsiLogicController0:1`, which is the
+	// last element of the device ID in the export lease. For OpenStack it
+	// is the device name of the attachment, e.g. `/dev/vdb`, and for OVA
+	// the disk ID of the OVF descriptor. Disk selectors and disk mappings
+	// match against this value.
+	DeviceID string `json:"deviceID,omitempty"`
+	// FileName is the name of the file backing the disk (VMware, OVA).
+	FileName string `json:"fileName,omitempty"`
+	// VolumeID is the ID of the Cinder volume of the disk (OpenStack).
+	VolumeID string `json:"volumeID,omitempty"`
+	// Datastore is the datastore the disk is stored on (VMware).
+	Datastore string `json:"datastore,omitempty"`
+	// VolumeType is the Cinder volume type of the disk (OpenStack).
+	VolumeType string `json:"volumeType,omitempty"`
+	// BootDisk is true for the disk the source VM boots from.
+	BootDisk bool `json:"bootDisk,omitempty"`
+	// BootOrder is the position of the disk in the boot order of the
+	// source VM, starting at 1. It is 0 if the source VM does not list the
+	// disk in its boot order.
+	BootOrder int32 `json:"bootOrder,omitempty" wrangler:"min=0"`
+	// CDROM is true for ISO images that are imported as read-only CD-ROM.
+	CDROM bool `json:"cdrom,omitempty"`
+	// StorageClass is the storage class of the image and the volume of the
+	// disk, empty for the default storage class. It is resolved once the
+	// disk has been exported.
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// MetadataMapping defines how the metadata of the source VM that is
+// matched by the source key is added to the imported VM.
+type MetadataMapping struct {
+	// SourceKey is a shell pattern that is matched against the keys of the
+	// metadata of the source VM, e.g. "tag/*" or "metadata/owner".
+	SourceKey string `json:"sourceKey" wrangler:"required,minLength=1"`
+
+	// +optional
+	// Target defines how the matched metadata is added to the VM:
+	// - annotation: As annotation.
+	// - label: As label. Values are sanitized to the label syntax.
+	// - drop: Not at all.
+	// Defaults to "annotation".
+	Target *string `json:"target,omitempty" wrangler:"type=string,options=annotation|label|drop"`
+}
+
+// DiskSelector selects disks of the source VM. All fields that are set must
+// match. At least one field is required.
+type DiskSelector struct {
+	// +optional
+	// Index matches the disk at the given position in the source VM,
+	// starting at 0.
+	Index *int32 `json:"index,omitempty" wrangler:"min=0"`
+
+	// +optional
+	// DeviceID matches the disk with the given device ID, see the
+	// `deviceID` in the disk status.
+	DeviceID string `json:"deviceID,omitempty"`
+
+	// +optional
+	// FileName matches the VMware and OVA disks backed by the given file.
+	FileName string `json:"fileName,omitempty"`
+
+	// +optional
+	// VolumeID matches the OpenStack disk of the given Cinder volume.
+	VolumeID string `json:"volumeID,omitempty"`
+}
+
+// DiskMapping overrides the storage settings of the disks it matches. All
+// selectors that are set must match. At least one selector is required.
+type DiskMapping struct {
+	// +optional
+	// Index matches the disk at the given position in the source VM,
+	// starting at 0.
+	Index *int32 `json:"index,omitempty" wrangler:"min=0"`
+
+	// +optional
+	// DeviceID matches the disk with the given device ID, see the
+	// `deviceID` in the disk status.
+	DeviceID string `json:"deviceID,omitempty"`
+
+	// +optional
+	// Datastore matches the VMware disks that are stored on the given
+	// datastore.
+	Datastore string `json:"datastore,omitempty"`
+
+	// +optional
+	// VolumeType matches the OpenStack disks of the given Cinder volume
+	// type.
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// +optional
+	// StorageClass is the storage class of the matched disks. Defaults to
+	// the storage class of the import.
+	StorageClass string `json:"storageClass,omitempty" wrangler:"maxLength=253,validChars=a-z0-9.-"`
+
+	// +optional
+	// VolumeMode is the volume mode of the PVCs of the matched disks.
+	// Defaults to "Block".
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty" wrangler:"type=string,options=Block|Filesystem"`
+
+	// +optional
+	// AccessMode is the access mode of the PVCs of the matched disks.
+	// Defaults to "ReadWriteMany".
+	AccessMode *corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty" wrangler:"type=string,options=ReadWriteOnce|ReadOnlyMany|ReadWriteMany|ReadWriteOncePod"`
+
+	// +optional
+	// Skip excludes the matched disks from the import.
+	Skip bool `json:"skip,omitempty"`
 }
 
 type NetworkMapping struct {
-	SourceNetwork      string `json:"sourceNetwork"`
-	DestinationNetwork string `json:"destinationNetwork"`
+	SourceNetwork      string `json:"sourceNetwork" wrangler:"required,minLength=1"`
+	DestinationNetwork string `json:"destinationNetwork" wrangler:"required,minLength=1,maxLength=127,validChars=a-z0-9/-"`
+	// MatchType defines how the source network is matched against the
+	// network names of the source VM:
+	// - exact: The name must be equal to the source network.
+	// - glob: The source network is a shell pattern, e.g. "vlan-*".
+	// - regex: The source network is a regular expression that must match
+	//   the whole name.
+	// Defaults to "exact".
+	MatchType *string `json:"matchType,omitempty" wrangler:"type=string,options=exact|glob|regex"`
 	// Override the network interface model that is auto-detected (VMware)
 	// or defaulted (OpenStack).
 	NetworkInterfaceModel *string `json:"networkInterfaceModel,omitempty" wrangler:"type=string,options=e1000|e1000e|ne2k_pci|pcnet|rtl8139|virtio"`
 }
 
+// NetworkInterfaceMapping describes to which network a network interface of
+// the source VM is attached.
+type NetworkInterfaceMapping struct {
+	MACAddress    string `json:"macAddress,omitempty"`
+	SourceNetwork string `json:"sourceNetwork"`
+
+	// DestinationNetwork is the network the interface is attached to, or
+	// "pod-network" for the pod network. It is empty if the network
+	// interface is dropped.
+	DestinationNetwork string `json:"destinationNetwork,omitempty"`
+
+	// BootOrder is the position of the network interface in the boot order
+	// of the source VM (PXE boot), starting at 1. It is 0 if the source VM
+	// does not boot from the network interface.
+	BootOrder int32 `json:"bootOrder,omitempty" wrangler:"min=0"`
+}
+
 type ImportStatus string
 
 const (
@@ -122,6 +472,7 @@ const (
 	VirtualMachineRunning         ImportStatus   = "virtualMachineRunning"
 	VirtualMachineImportValid     ImportStatus   = "virtualMachineImportValid"
 	VirtualMachineImportInvalid   ImportStatus   = "virtualMachineImportInvalid"
+	VirtualMachineImportScheduled ImportStatus   = "virtualMachineImportScheduled"
 	VirtualMachineShutdownGuest   condition.Cond = "VMShutdownGuest"
 	VirtualMachinePoweringOff     condition.Cond = "VMPoweringOff"
 	VirtualMachinePoweredOff      condition.Cond = "VMPoweredOff"
@@ -131,6 +482,32 @@ const (
 	VirtualMachineImageFailed     condition.Cond = "VirtualMachineImageFailed"
 	VirtualMachineExportFailed    condition.Cond = "VMExportFailed"
 	VirtualMachineMigrationFailed ImportStatus   = "VMMigrationFailed"
+	VirtualMachineImportFailed    condition.Cond = "VMImportFailed"
+	VirtualMachineImported        condition.Cond = "VMImported"
+)
+
+// The reasons that are reported by the `VMImportFailed` and `VMImported`
+// conditions when an import reaches a terminal state.
+const (
+	ReasonInvalidSourceCluster       = "InvalidSourceCluster"
+	ReasonSourceNotFound             = "SourceNotFound"
+	ReasonSourceNetworkNotFound      = "SourceNetworkNotFound"
+	ReasonDestinationNetworkNotFound = "DestinationNetworkNotFound"
+	ReasonInvalidNetworkMapping      = "InvalidNetworkMapping"
+	ReasonStorageClassNotFound       = "StorageClassNotFound"
+	ReasonVirtualMachineNotFound     = "VirtualMachineNotFound"
+	ReasonToolsNotRunning            = "ToolsNotRunning"
+	ReasonInvalidSchedule            = "InvalidSchedule"
+	ReasonNetworkCreationFailed      = "NetworkCreationFailed"
+	ReasonInvalidDiskMapping         = "InvalidDiskMapping"
+	ReasonBootDiskExcluded           = "BootDiskExcluded"
+	ReasonFeatureGateDisabled        = "FeatureGateDisabled"
+	ReasonInvalidVirtualMachineName  = "InvalidVirtualMachineName"
+	ReasonNoDisks                    = "NoDisks"
+	ReasonDiskExportFailed           = "DiskExportFailed"
+	ReasonPreflightChecksFailed      = "PreflightChecksFailed"
+	ReasonSanitizeFailed             = "SanitizeFailed"
+	ReasonVirtualMachineRunning      = "VirtualMachineRunning"
 )
 
 // The supported network interface models.
@@ -145,6 +522,46 @@ const (
 	NetworkInterfaceModelVirtio  = "virtio"
 )
 
+// The ways a network mapping can match the source network.
+const (
+	NetworkMatchTypeExact = "exact"
+	NetworkMatchTypeGlob  = "glob"
+	NetworkMatchTypeRegex = "regex"
+)
+
+// The policies for network interfaces that are not mapped.
+const (
+	UnmappedNetworkDrop       = "drop"
+	UnmappedNetworkPodNetwork = "pod-network"
+	UnmappedNetworkFail       = "fail"
+)
+
+// The ways the metadata of the source VM can be added to the imported VM.
+const (
+	MetadataTargetAnnotation = "annotation"
+	MetadataTargetLabel      = "label"
+	MetadataTargetDrop       = "drop"
+)
+
+// The policies for the CD-ROM drives of the source VM.
+const (
+	CDROMImportNone  = "none"
+	CDROMImportEmpty = "empty"
+	CDROMImportImage = "image"
+)
+
+// The steps of an import that are recorded in the phase history.
+const (
+	// ImportStepPreflight covers the preflight checks.
+	ImportStepPreflight = "Preflight"
+	// ImportStepShutdownWait covers the shutdown of the source VM until it
+	// is powered off.
+	ImportStepShutdownWait = "ShutdownWait"
+	// ImportStepExport covers the export of the disks of the source VM,
+	// including their conversion into raw images.
+	ImportStepExport = "Export"
+)
+
 const (
 	DefaultGracefulShutdownTimeoutSeconds = 60
 )
@@ -161,6 +578,33 @@ func (in *VirtualMachineImport) GetForcePowerOff() bool {
 	return ptr.Deref(in.Spec.ForcePowerOff, false)
 }
 
+func (in *VirtualMachineImport) GetOnUnmapped() string {
+	return ptr.Deref(in.Spec.OnUnmapped, UnmappedNetworkDrop)
+}
+
+func (in *VirtualMachineImport) GetCDROMImport() string {
+	return ptr.Deref(in.Spec.CDROMImport, CDROMImportNone)
+}
+
+func (in *VirtualMachineImport) GetCPUModel() string {
+	if in.Spec.CPU == nil {
+		return ""
+	}
+	return in.Spec.CPU.Model
+}
+
+func (in *VirtualMachineImport) PreserveCPUFeatures() bool {
+	return in.Spec.CPU != nil && ptr.Deref(in.Spec.CPU.PreserveFeatures, false)
+}
+
+func (in *VirtualMachineImport) PreserveCPUPlacement() bool {
+	return in.Spec.CPU != nil && ptr.Deref(in.Spec.CPU.PreservePlacement, false)
+}
+
+func (in *VirtualMachineImport) PreserveCPUReservations() bool {
+	return in.Spec.CPU != nil && ptr.Deref(in.Spec.CPU.PreserveReservations, false)
+}
+
 func (in *VirtualMachineImport) GetGracefulShutdownTimeoutSeconds() int32 {
 	timeout := in.Spec.GracefulShutdownTimeoutSeconds
 	if timeout <= 0 {
@@ -169,6 +613,17 @@ func (in *VirtualMachineImport) GetGracefulShutdownTimeoutSeconds() int32 {
 	return timeout
 }
 
+// GetNetworkMapping returns the network mappings of the spec followed by
+// the mappings that were generated for auto-created networks.
+func (in *VirtualMachineImport) GetNetworkMapping() []NetworkMapping {
+	if len(in.Status.GeneratedNetworkMapping) == 0 {
+		return in.Spec.Mapping
+	}
+	result := make([]NetworkMapping, 0, len(in.Spec.Mapping)+len(in.Status.GeneratedNetworkMapping))
+	result = append(result, in.Spec.Mapping...)
+	return append(result, in.Status.GeneratedNetworkMapping...)
+}
+
 func (in *VirtualMachineImport) NamespacedName() string {
 	return types.NamespacedName{
 		Namespace: in.Namespace,
@@ -183,3 +638,11 @@ func (in *VirtualMachineImport) SkipPreflightChecks() bool {
 func (in *NetworkMapping) GetNetworkInterfaceModel() string {
 	return ptr.Deref(in.NetworkInterfaceModel, NetworkInterfaceModelVirtio)
 }
+
+func (in *NetworkMapping) GetMatchType() string {
+	return ptr.Deref(in.MatchType, NetworkMatchTypeExact)
+}
+
+func (in *MetadataMapping) GetTarget() string {
+	return ptr.Deref(in.Target, MetadataTargetAnnotation)
+}
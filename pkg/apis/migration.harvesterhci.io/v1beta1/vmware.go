@@ -19,15 +19,53 @@ type VmwareSource struct {
 }
 
 type VmwareSourceSpec struct {
-	EndpointAddress string                 `json:"endpoint"`
-	Datacenter      string                 `json:"dc"`
-	Credentials     corev1.SecretReference `json:"credentials"`
+	EndpointAddress string                 `json:"endpoint" wrangler:"required,minLength=1"`
+	Datacenter      string                 `json:"dc" wrangler:"required,minLength=1"`
+	Credentials     corev1.SecretReference `json:"credentials" wrangler:"required"`
+
+	// +optional
+	// StorageClassMapping maps the disks to storage classes by their
+	// datastore. The first matching rule is used for each disk.
+	StorageClassMapping []StorageClassMapping `json:"storageClassMapping,omitempty"`
+
+	// +optional
+	// ResourcePolicy configures the resources of the VMs that are imported
+	// from this source. The resource policy of a VirtualMachineImport takes
+	// precedence.
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
 }
 
 type VmwareSourceStatus struct {
 	Status ClusterStatus `json:"status,omitempty"`
 	// +optional
 	Conditions []common.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	// ObservedGeneration is the generation of the spec that has been
+	// verified last.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	// ObservedSecretVersion is the resource version of the credentials
+	// secret that has been verified last.
+	ObservedSecretVersion string `json:"observedSecretVersion,omitempty"`
+
+	// +optional
+	// LastVerifiedTime is the time of the last verification of the source.
+	LastVerifiedTime string `json:"lastVerifiedTime,omitempty"`
+
+	// +optional
+	// VerificationLatency is the time the last verification took.
+	VerificationLatency *metav1.Duration `json:"verificationLatency,omitempty"`
+
+	// +optional
+	// Version is the version of the vCenter API reported by the source.
+	Version string `json:"version,omitempty"`
+
+	// +optional
+	// Networks are the networks available in the source. They are updated
+	// with every successful verification.
+	Networks []SourceNetwork `json:"networks,omitempty"`
 }
 
 func (s *VmwareSource) NamespacedName() string {
@@ -53,6 +91,18 @@ func (s *VmwareSource) GetKind() string {
 	return KindVmwareSource
 }
 
+func (s *VmwareSource) GetNetworks() []SourceNetwork {
+	return s.Status.Networks
+}
+
+func (s *VmwareSource) GetStorageClassMapping() []StorageClassMapping {
+	return s.Spec.StorageClassMapping
+}
+
+func (s *VmwareSource) GetResourcePolicy() *ResourcePolicy {
+	return s.Spec.ResourcePolicy
+}
+
 func (s *VmwareSource) GetConnectionInfo() (string, string) {
 	return s.Spec.EndpointAddress, s.Spec.Datacenter
 }
@@ -24,10 +24,58 @@ package v1beta1
 import (
 	common "github.com/harvester/vm-import-controller/pkg/apis/common"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	corev1 "kubevirt.io/api/core/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CDROMDrive) DeepCopyInto(out *CDROMDrive) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CDROMDrive.
+func (in *CDROMDrive) DeepCopy() *CDROMDrive {
+	if in == nil {
+		return nil
+	}
+	out := new(CDROMDrive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOptions) DeepCopyInto(out *CPUOptions) {
+	*out = *in
+	if in.PreserveFeatures != nil {
+		in, out := &in.PreserveFeatures, &out.PreserveFeatures
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreservePlacement != nil {
+		in, out := &in.PreservePlacement, &out.PreservePlacement
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreserveReservations != nil {
+		in, out := &in.PreserveReservations, &out.PreserveReservations
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOptions.
+func (in *CPUOptions) DeepCopy() *CPUOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskInfo) DeepCopyInto(out *DiskInfo) {
 	*out = *in
@@ -49,9 +97,219 @@ func (in *DiskInfo) DeepCopy() *DiskInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskMapping) DeepCopyInto(out *DiskMapping) {
+	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VolumeMode != nil {
+		in, out := &in.VolumeMode, &out.VolumeMode
+		*out = new(v1.PersistentVolumeMode)
+		**out = **in
+	}
+	if in.AccessMode != nil {
+		in, out := &in.AccessMode, &out.AccessMode
+		*out = new(v1.PersistentVolumeAccessMode)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskMapping.
+func (in *DiskMapping) DeepCopy() *DiskMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSelector) DeepCopyInto(out *DiskSelector) {
+	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSelector.
+func (in *DiskSelector) DeepCopy() *DiskSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportSchedule) DeepCopyInto(out *ImportSchedule) {
+	*out = *in
+	if in.StartAfter != nil {
+		in, out := &in.StartAfter, &out.StartAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSchedule.
+func (in *ImportSchedule) DeepCopy() *ImportSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryDisk) DeepCopyInto(out *InventoryDisk) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryDisk.
+func (in *InventoryDisk) DeepCopy() *InventoryDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryNetworkInterface) DeepCopyInto(out *InventoryNetworkInterface) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryNetworkInterface.
+func (in *InventoryNetworkInterface) DeepCopy() *InventoryNetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryNetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryVirtualMachine) DeepCopyInto(out *InventoryVirtualMachine) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]InventoryDisk, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]InventoryNetworkInterface, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryVirtualMachine.
+func (in *InventoryVirtualMachine) DeepCopy() *InventoryVirtualMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryVirtualMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataMapping) DeepCopyInto(out *MetadataMapping) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataMapping.
+func (in *MetadataMapping) DeepCopy() *MetadataMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAutoCreation) DeepCopyInto(out *NetworkAutoCreation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAutoCreation.
+func (in *NetworkAutoCreation) DeepCopy() *NetworkAutoCreation {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAutoCreation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceMapping) DeepCopyInto(out *NetworkInterfaceMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceMapping.
+func (in *NetworkInterfaceMapping) DeepCopy() *NetworkInterfaceMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkMapping) DeepCopyInto(out *NetworkMapping) {
 	*out = *in
+	if in.MatchType != nil {
+		in, out := &in.MatchType, &out.MatchType
+		*out = new(string)
+		**out = **in
+	}
 	if in.NetworkInterfaceModel != nil {
 		in, out := &in.NetworkInterfaceModel, &out.NetworkInterfaceModel
 		*out = new(string)
@@ -75,7 +333,7 @@ func (in *OpenstackSource) DeepCopyInto(out *OpenstackSource) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -152,6 +410,16 @@ func (in *OpenstackSourceSpec) DeepCopyInto(out *OpenstackSourceSpec) {
 	*out = *in
 	out.Credentials = in.Credentials
 	out.OpenstackSourceOptions = in.OpenstackSourceOptions
+	if in.StorageClassMapping != nil {
+		in, out := &in.StorageClassMapping, &out.StorageClassMapping
+		*out = make([]StorageClassMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourcePolicy != nil {
+		in, out := &in.ResourcePolicy, &out.ResourcePolicy
+		*out = new(ResourcePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -173,6 +441,16 @@ func (in *OpenstackSourceStatus) DeepCopyInto(out *OpenstackSourceStatus) {
 		*out = make([]common.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.VerificationLatency != nil {
+		in, out := &in.VerificationLatency, &out.VerificationLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]SourceNetwork, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -277,6 +555,11 @@ func (in *OvaSourceSpec) DeepCopyInto(out *OvaSourceSpec) {
 		*out = new(v1.SecretReference)
 		**out = **in
 	}
+	if in.ResourcePolicy != nil {
+		in, out := &in.ResourcePolicy, &out.ResourcePolicy
+		*out = new(ResourcePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -298,6 +581,11 @@ func (in *OvaSourceStatus) DeepCopyInto(out *OvaSourceStatus) {
 		*out = make([]common.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.VerificationLatency != nil {
+		in, out := &in.VerificationLatency, &out.VerificationLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -311,6 +599,207 @@ func (in *OvaSourceStatus) DeepCopy() *OvaSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTiming) DeepCopyInto(out *PhaseTiming) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTiming.
+func (in *PhaseTiming) DeepCopy() *PhaseTiming {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTiming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePolicy) DeepCopyInto(out *ResourcePolicy) {
+	*out = *in
+	if in.CPURequestPercentage != nil {
+		in, out := &in.CPURequestPercentage, &out.CPURequestPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MemoryRequestPercentage != nil {
+		in, out := &in.MemoryRequestPercentage, &out.MemoryRequestPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HugepageSize != nil {
+		in, out := &in.HugepageSize, &out.HugepageSize
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePolicy.
+func (in *ResourcePolicy) DeepCopy() *ResourcePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInventory) DeepCopyInto(out *SourceInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInventory.
+func (in *SourceInventory) DeepCopy() *SourceInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInventoryList) DeepCopyInto(out *SourceInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SourceInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInventoryList.
+func (in *SourceInventoryList) DeepCopy() *SourceInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInventorySpec) DeepCopyInto(out *SourceInventorySpec) {
+	*out = *in
+	out.SourceCluster = in.SourceCluster
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInventorySpec.
+func (in *SourceInventorySpec) DeepCopy() *SourceInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInventoryStatus) DeepCopyInto(out *SourceInventoryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]common.Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = make([]InventoryVirtualMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInventoryStatus.
+func (in *SourceInventoryStatus) DeepCopy() *SourceInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceNetwork) DeepCopyInto(out *SourceNetwork) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceNetwork.
+func (in *SourceNetwork) DeepCopy() *SourceNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceVirtualMachineIdentity) DeepCopyInto(out *SourceVirtualMachineIdentity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceVirtualMachineIdentity.
+func (in *SourceVirtualMachineIdentity) DeepCopy() *SourceVirtualMachineIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceVirtualMachineIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClassMapping) DeepCopyInto(out *StorageClassMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassMapping.
+func (in *StorageClassMapping) DeepCopy() *StorageClassMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImport) DeepCopyInto(out *VirtualMachineImport) {
 	*out = *in
@@ -383,11 +872,47 @@ func (in *VirtualMachineImportSpec) DeepCopyInto(out *VirtualMachineImportSpec)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AutoCreateNetworks != nil {
+		in, out := &in.AutoCreateNetworks, &out.AutoCreateNetworks
+		*out = new(NetworkAutoCreation)
+		**out = **in
+	}
+	if in.OnUnmapped != nil {
+		in, out := &in.OnUnmapped, &out.OnUnmapped
+		*out = new(string)
+		**out = **in
+	}
 	if in.DefaultNetworkInterfaceModel != nil {
 		in, out := &in.DefaultNetworkInterfaceModel, &out.DefaultNetworkInterfaceModel
 		*out = new(string)
 		**out = **in
 	}
+	if in.DiskMapping != nil {
+		in, out := &in.DiskMapping, &out.DiskMapping
+		*out = make([]DiskMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IncludeDisks != nil {
+		in, out := &in.IncludeDisks, &out.IncludeDisks
+		*out = make([]DiskSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludeDisks != nil {
+		in, out := &in.ExcludeDisks, &out.ExcludeDisks
+		*out = make([]DiskSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CDROMImport != nil {
+		in, out := &in.CDROMImport, &out.CDROMImport
+		*out = new(string)
+		**out = **in
+	}
 	if in.DefaultDiskBusType != nil {
 		in, out := &in.DefaultDiskBusType, &out.DefaultDiskBusType
 		*out = new(corev1.DiskBus)
@@ -403,6 +928,28 @@ func (in *VirtualMachineImportSpec) DeepCopyInto(out *VirtualMachineImportSpec)
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(CPUOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourcePolicy != nil {
+		in, out := &in.ResourcePolicy, &out.ResourcePolicy
+		*out = new(ResourcePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataMapping != nil {
+		in, out := &in.MetadataMapping, &out.MetadataMapping
+		*out = make([]MetadataMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ImportSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -431,6 +978,48 @@ func (in *VirtualMachineImportStatus) DeepCopyInto(out *VirtualMachineImportStat
 		*out = make([]common.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.PhaseHistory != nil {
+		in, out := &in.PhaseHistory, &out.PhaseHistory
+		*out = make([]PhaseTiming, len(*in))
+		copy(*out, *in)
+	}
+	if in.ElapsedTime != nil {
+		in, out := &in.ElapsedTime, &out.ElapsedTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SourceDowntime != nil {
+		in, out := &in.SourceDowntime, &out.SourceDowntime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GeneratedNetworkMapping != nil {
+		in, out := &in.GeneratedNetworkMapping, &out.GeneratedNetworkMapping
+		*out = make([]NetworkMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]NetworkInterfaceMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmptyCDROMDrives != nil {
+		in, out := &in.EmptyCDROMDrives, &out.EmptyCDROMDrives
+		*out = make([]CDROMDrive, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceVirtualMachine != nil {
+		in, out := &in.SourceVirtualMachine, &out.SourceVirtualMachine
+		*out = new(SourceVirtualMachineIdentity)
+		**out = **in
+	}
+	if in.StorageClassMapping != nil {
+		in, out := &in.StorageClassMapping, &out.StorageClassMapping
+		*out = make([]StorageClassMapping, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -449,7 +1038,7 @@ func (in *VmwareSource) DeepCopyInto(out *VmwareSource) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -509,6 +1098,16 @@ func (in *VmwareSourceList) DeepCopyObject() runtime.Object {
 func (in *VmwareSourceSpec) DeepCopyInto(out *VmwareSourceSpec) {
 	*out = *in
 	out.Credentials = in.Credentials
+	if in.StorageClassMapping != nil {
+		in, out := &in.StorageClassMapping, &out.StorageClassMapping
+		*out = make([]StorageClassMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourcePolicy != nil {
+		in, out := &in.ResourcePolicy, &out.ResourcePolicy
+		*out = new(ResourcePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -530,6 +1129,16 @@ func (in *VmwareSourceStatus) DeepCopyInto(out *VmwareSourceStatus) {
 		*out = make([]common.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.VerificationLatency != nil {
+		in, out := &in.VerificationLatency, &out.VerificationLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]SourceNetwork, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
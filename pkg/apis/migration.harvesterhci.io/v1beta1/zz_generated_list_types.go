@@ -60,6 +60,23 @@ func NewOvaSource(namespace, name string, obj OvaSource) *OvaSource {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// SourceInventoryList is a list of SourceInventory resources
+type SourceInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SourceInventory `json:"items"`
+}
+
+func NewSourceInventory(namespace, name string, obj SourceInventory) *SourceInventory {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("SourceInventory").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // VirtualMachineImportList is a list of VirtualMachineImport resources
 type VirtualMachineImportList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -30,6 +30,7 @@ import (
 var (
 	OpenstackSourceResourceName      = "openstacksources"
 	OvaSourceResourceName            = "ovasources"
+	SourceInventoryResourceName      = "sourceinventories"
 	VirtualMachineImportResourceName = "virtualmachineimports"
 	VmwareSourceResourceName         = "vmwaresources"
 )
@@ -59,6 +60,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&OpenstackSourceList{},
 		&OvaSource{},
 		&OvaSourceList{},
+		&SourceInventory{},
+		&SourceInventoryList{},
 		&VirtualMachineImport{},
 		&VirtualMachineImportList{},
 		&VmwareSource{},
@@ -0,0 +1,349 @@
+package v1beta2
+
+import (
+	"github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// importPhases maps the statuses of the v1beta1 API to the phases of this
+// API version.
+var importPhases = map[v1beta1.ImportStatus]ImportPhase{
+	"":                                    ImportPhasePending,
+	v1beta1.VirtualMachineImportValid:     ImportPhaseValid,
+	v1beta1.VirtualMachineImportInvalid:   ImportPhaseInvalid,
+	v1beta1.VirtualMachineImportScheduled: ImportPhaseScheduled,
+	v1beta1.SourceReady:                   ImportPhaseSourceReady,
+	v1beta1.DisksExported:                 ImportPhaseDisksExported,
+	v1beta1.DiskImagesSubmitted:           ImportPhaseDiskImagesSubmitted,
+	v1beta1.DiskImagesReady:               ImportPhaseDiskImagesReady,
+	v1beta1.DiskImagesFailed:              ImportPhaseDiskImagesFailed,
+	v1beta1.VirtualMachineCreated:         ImportPhaseVirtualMachineCreated,
+	v1beta1.VirtualMachineRunning:         ImportPhaseRunning,
+	v1beta1.VirtualMachineMigrationFailed: ImportPhaseFailed,
+}
+
+// ImportPhaseFromV1beta1 returns the phase that corresponds to the given
+// v1beta1 status. Unknown statuses are passed through unchanged.
+func ImportPhaseFromV1beta1(status v1beta1.ImportStatus) ImportPhase {
+	if phase, ok := importPhases[status]; ok {
+		return phase
+	}
+	return ImportPhase(status)
+}
+
+// ImportPhaseToV1beta1 returns the v1beta1 status that corresponds to the
+// given phase. Unknown phases are passed through unchanged.
+func ImportPhaseToV1beta1(phase ImportPhase) v1beta1.ImportStatus {
+	if phase == "" {
+		return ""
+	}
+	for status, p := range importPhases {
+		if p == phase {
+			return status
+		}
+	}
+	return v1beta1.ImportStatus(phase)
+}
+
+// ConvertFromV1beta1 converts a v1beta1 `VirtualMachineImport` into this
+// API version.
+func ConvertFromV1beta1(in *v1beta1.VirtualMachineImport) *VirtualMachineImport {
+	out := &VirtualMachineImport{
+		TypeMeta: in.TypeMeta,
+		Spec: VirtualMachineImportSpec{
+			SourceCluster:                in.Spec.SourceCluster,
+			VirtualMachineName:           in.Spec.VirtualMachineName,
+			DefaultNetworkInterfaceModel: in.Spec.DefaultNetworkInterfaceModel,
+			StorageClass:                 in.Spec.StorageClass,
+			DefaultDiskBusType:           in.Spec.DefaultDiskBusType,
+			SkipPreflightChecks:          in.Spec.SkipPreflightChecks,
+			DefaultDestinationNetwork:    in.Spec.DefaultDestinationNetwork,
+			OnUnmapped:                   in.Spec.OnUnmapped,
+			CDROMImport:                  in.Spec.CDROMImport,
+		},
+		Status: VirtualMachineImportStatus{
+			Phase:                      ImportPhaseFromV1beta1(in.Status.Status),
+			Conditions:                 in.Status.ImportConditions,
+			NewVirtualMachine:          in.Status.NewVirtualMachine,
+			ImportedVirtualMachineName: in.Status.ImportedVirtualMachineName,
+			ElapsedTime:                in.Status.ElapsedTime,
+			SourceDowntime:             in.Status.SourceDowntime,
+			DiskCount:                  in.Status.DiskCount,
+			Progress:                   in.Status.Progress,
+			ImportTime:                 in.Status.ImportTime,
+		},
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.APIVersion = SchemeGroupVersion.String()
+
+	for _, nm := range in.Spec.Mapping {
+		out.Spec.NetworkMapping = append(out.Spec.NetworkMapping, NetworkMapping(nm))
+	}
+
+	for _, dm := range in.Spec.DiskMapping {
+		out.Spec.DiskMapping = append(out.Spec.DiskMapping, DiskMapping(dm))
+	}
+
+	for _, ds := range in.Spec.IncludeDisks {
+		out.Spec.IncludeDisks = append(out.Spec.IncludeDisks, DiskSelector(ds))
+	}
+
+	for _, ds := range in.Spec.ExcludeDisks {
+		out.Spec.ExcludeDisks = append(out.Spec.ExcludeDisks, DiskSelector(ds))
+	}
+
+	for _, mm := range in.Spec.MetadataMapping {
+		out.Spec.MetadataMapping = append(out.Spec.MetadataMapping, MetadataMapping(mm))
+	}
+
+	if in.Spec.AutoCreateNetworks != nil {
+		out.Spec.AutoCreateNetworks = &NetworkAutoCreation{
+			ClusterNetwork: in.Spec.AutoCreateNetworks.ClusterNetwork,
+		}
+	}
+
+	if in.Spec.ResourcePolicy != nil {
+		out.Spec.ResourcePolicy = &ResourcePolicy{
+			CPURequestPercentage:    in.Spec.ResourcePolicy.CPURequestPercentage,
+			MemoryRequestPercentage: in.Spec.ResourcePolicy.MemoryRequestPercentage,
+			ReservedMemory:          in.Spec.ResourcePolicy.ReservedMemory,
+			HugepageSize:            in.Spec.ResourcePolicy.HugepageSize,
+		}
+	}
+
+	if in.Spec.CPU != nil {
+		out.Spec.CPU = &CPUOptions{
+			Model:                in.Spec.CPU.Model,
+			PreserveFeatures:     in.Spec.CPU.PreserveFeatures,
+			PreservePlacement:    in.Spec.CPU.PreservePlacement,
+			PreserveReservations: in.Spec.CPU.PreserveReservations,
+		}
+	}
+
+	for _, nm := range in.Status.GeneratedNetworkMapping {
+		out.Status.GeneratedNetworkMapping = append(out.Status.GeneratedNetworkMapping, NetworkMapping(nm))
+	}
+
+	for _, ni := range in.Status.NetworkInterfaces {
+		out.Status.NetworkInterfaces = append(out.Status.NetworkInterfaces, NetworkInterfaceMapping(ni))
+	}
+
+	for _, cd := range in.Status.EmptyCDROMDrives {
+		out.Status.EmptyCDROMDrives = append(out.Status.EmptyCDROMDrives, CDROMDrive(cd))
+	}
+
+	if in.Status.SourceVirtualMachine != nil {
+		out.Status.SourceVirtualMachine = &SourceVirtualMachineIdentity{
+			ID:        in.Status.SourceVirtualMachine.ID,
+			UUID:      in.Status.SourceVirtualMachine.UUID,
+			Reference: in.Status.SourceVirtualMachine.Reference,
+		}
+	}
+
+	if in.Spec.Schedule != nil {
+		out.Spec.Schedule = &ImportSchedule{
+			StartAfter: in.Spec.Schedule.StartAfter,
+		}
+		if in.Spec.Schedule.Window != nil {
+			out.Spec.Schedule.Window = &MaintenanceWindow{
+				Cron:     in.Spec.Schedule.Window.Cron,
+				Duration: in.Spec.Schedule.Window.Duration,
+			}
+		}
+	}
+
+	if in.Spec.Folder != "" || in.Spec.ForcePowerOff != nil || in.Spec.GracefulShutdownTimeoutSeconds != 0 {
+		out.Spec.Vmware = &VmwareImportOptions{
+			Folder:                         in.Spec.Folder,
+			ForcePowerOff:                  in.Spec.ForcePowerOff,
+			GracefulShutdownTimeoutSeconds: in.Spec.GracefulShutdownTimeoutSeconds,
+		}
+	}
+
+	for _, d := range in.Status.DiskImportStatus {
+		out.Status.Disks = append(out.Status.Disks, DiskStatus{
+			Name:                d.Name,
+			Size:                d.DiskSize,
+			LocalPath:           d.DiskLocalPath,
+			Route:               d.DiskRoute,
+			VirtualMachineImage: d.VirtualMachineImage,
+			Conditions:          d.DiskConditions,
+			BusType:             d.BusType,
+			Index:               d.Index,
+			DeviceID:            d.DeviceID,
+			FileName:            d.FileName,
+			VolumeID:            d.VolumeID,
+			Datastore:           d.Datastore,
+			VolumeType:          d.VolumeType,
+			BootDisk:            d.BootDisk,
+			BootOrder:           d.BootOrder,
+			CDROM:               d.CDROM,
+			StorageClass:        d.StorageClass,
+		})
+	}
+
+	for _, m := range in.Status.StorageClassMapping {
+		out.Status.StorageClassMapping = append(out.Status.StorageClassMapping, StorageClassMapping(m))
+	}
+
+	for _, t := range in.Status.PhaseHistory {
+		out.Status.PhaseHistory = append(out.Status.PhaseHistory, PhaseTiming{
+			Phase:       ImportPhaseFromV1beta1(t.Phase),
+			Step:        t.Step,
+			EnteredTime: t.EnteredTime,
+			ExitedTime:  t.ExitedTime,
+		})
+	}
+
+	return out
+}
+
+// ConvertToV1beta1 converts a `VirtualMachineImport` of this API version
+// into v1beta1.
+func ConvertToV1beta1(in *VirtualMachineImport) *v1beta1.VirtualMachineImport {
+	out := &v1beta1.VirtualMachineImport{
+		TypeMeta: in.TypeMeta,
+		Spec: v1beta1.VirtualMachineImportSpec{
+			SourceCluster:                in.Spec.SourceCluster,
+			VirtualMachineName:           in.Spec.VirtualMachineName,
+			DefaultNetworkInterfaceModel: in.Spec.DefaultNetworkInterfaceModel,
+			StorageClass:                 in.Spec.StorageClass,
+			DefaultDiskBusType:           in.Spec.DefaultDiskBusType,
+			SkipPreflightChecks:          in.Spec.SkipPreflightChecks,
+			DefaultDestinationNetwork:    in.Spec.DefaultDestinationNetwork,
+			OnUnmapped:                   in.Spec.OnUnmapped,
+			CDROMImport:                  in.Spec.CDROMImport,
+		},
+		Status: v1beta1.VirtualMachineImportStatus{
+			Status:                     ImportPhaseToV1beta1(in.Status.Phase),
+			ImportConditions:           in.Status.Conditions,
+			NewVirtualMachine:          in.Status.NewVirtualMachine,
+			ImportedVirtualMachineName: in.Status.ImportedVirtualMachineName,
+			ElapsedTime:                in.Status.ElapsedTime,
+			SourceDowntime:             in.Status.SourceDowntime,
+			DiskCount:                  in.Status.DiskCount,
+			Progress:                   in.Status.Progress,
+			ImportTime:                 in.Status.ImportTime,
+		},
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.APIVersion = v1beta1.SchemeGroupVersion.String()
+
+	for _, nm := range in.Spec.NetworkMapping {
+		out.Spec.Mapping = append(out.Spec.Mapping, v1beta1.NetworkMapping(nm))
+	}
+
+	for _, dm := range in.Spec.DiskMapping {
+		out.Spec.DiskMapping = append(out.Spec.DiskMapping, v1beta1.DiskMapping(dm))
+	}
+
+	for _, ds := range in.Spec.IncludeDisks {
+		out.Spec.IncludeDisks = append(out.Spec.IncludeDisks, v1beta1.DiskSelector(ds))
+	}
+
+	for _, ds := range in.Spec.ExcludeDisks {
+		out.Spec.ExcludeDisks = append(out.Spec.ExcludeDisks, v1beta1.DiskSelector(ds))
+	}
+
+	for _, mm := range in.Spec.MetadataMapping {
+		out.Spec.MetadataMapping = append(out.Spec.MetadataMapping, v1beta1.MetadataMapping(mm))
+	}
+
+	if in.Spec.AutoCreateNetworks != nil {
+		out.Spec.AutoCreateNetworks = &v1beta1.NetworkAutoCreation{
+			ClusterNetwork: in.Spec.AutoCreateNetworks.ClusterNetwork,
+		}
+	}
+
+	if in.Spec.ResourcePolicy != nil {
+		out.Spec.ResourcePolicy = &v1beta1.ResourcePolicy{
+			CPURequestPercentage:    in.Spec.ResourcePolicy.CPURequestPercentage,
+			MemoryRequestPercentage: in.Spec.ResourcePolicy.MemoryRequestPercentage,
+			ReservedMemory:          in.Spec.ResourcePolicy.ReservedMemory,
+			HugepageSize:            in.Spec.ResourcePolicy.HugepageSize,
+		}
+	}
+
+	if in.Spec.CPU != nil {
+		out.Spec.CPU = &v1beta1.CPUOptions{
+			Model:                in.Spec.CPU.Model,
+			PreserveFeatures:     in.Spec.CPU.PreserveFeatures,
+			PreservePlacement:    in.Spec.CPU.PreservePlacement,
+			PreserveReservations: in.Spec.CPU.PreserveReservations,
+		}
+	}
+
+	for _, nm := range in.Status.GeneratedNetworkMapping {
+		out.Status.GeneratedNetworkMapping = append(out.Status.GeneratedNetworkMapping, v1beta1.NetworkMapping(nm))
+	}
+
+	for _, ni := range in.Status.NetworkInterfaces {
+		out.Status.NetworkInterfaces = append(out.Status.NetworkInterfaces, v1beta1.NetworkInterfaceMapping(ni))
+	}
+
+	for _, cd := range in.Status.EmptyCDROMDrives {
+		out.Status.EmptyCDROMDrives = append(out.Status.EmptyCDROMDrives, v1beta1.CDROMDrive(cd))
+	}
+
+	if in.Status.SourceVirtualMachine != nil {
+		out.Status.SourceVirtualMachine = &v1beta1.SourceVirtualMachineIdentity{
+			ID:        in.Status.SourceVirtualMachine.ID,
+			UUID:      in.Status.SourceVirtualMachine.UUID,
+			Reference: in.Status.SourceVirtualMachine.Reference,
+		}
+	}
+
+	if in.Spec.Schedule != nil {
+		out.Spec.Schedule = &v1beta1.ImportSchedule{
+			StartAfter: in.Spec.Schedule.StartAfter,
+		}
+		if in.Spec.Schedule.Window != nil {
+			out.Spec.Schedule.Window = &v1beta1.MaintenanceWindow{
+				Cron:     in.Spec.Schedule.Window.Cron,
+				Duration: in.Spec.Schedule.Window.Duration,
+			}
+		}
+	}
+
+	if in.Spec.Vmware != nil {
+		out.Spec.Folder = in.Spec.Vmware.Folder
+		out.Spec.ForcePowerOff = in.Spec.Vmware.ForcePowerOff
+		out.Spec.GracefulShutdownTimeoutSeconds = in.Spec.Vmware.GracefulShutdownTimeoutSeconds
+	}
+
+	for _, d := range in.Status.Disks {
+		out.Status.DiskImportStatus = append(out.Status.DiskImportStatus, v1beta1.DiskInfo{
+			Name:                d.Name,
+			DiskSize:            d.Size,
+			DiskLocalPath:       d.LocalPath,
+			DiskRoute:           d.Route,
+			VirtualMachineImage: d.VirtualMachineImage,
+			DiskConditions:      d.Conditions,
+			BusType:             d.BusType,
+			Index:               d.Index,
+			DeviceID:            d.DeviceID,
+			FileName:            d.FileName,
+			VolumeID:            d.VolumeID,
+			Datastore:           d.Datastore,
+			VolumeType:          d.VolumeType,
+			BootDisk:            d.BootDisk,
+			BootOrder:           d.BootOrder,
+			CDROM:               d.CDROM,
+			StorageClass:        d.StorageClass,
+		})
+	}
+
+	for _, m := range in.Status.StorageClassMapping {
+		out.Status.StorageClassMapping = append(out.Status.StorageClassMapping, v1beta1.StorageClassMapping(m))
+	}
+
+	for _, t := range in.Status.PhaseHistory {
+		out.Status.PhaseHistory = append(out.Status.PhaseHistory, v1beta1.PhaseTiming{
+			Phase:       ImportPhaseToV1beta1(t.Phase),
+			Step:        t.Step,
+			EnteredTime: t.EnteredTime,
+			ExitedTime:  t.ExitedTime,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,558 @@
+package v1beta2
+
+import (
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type VirtualMachineImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              VirtualMachineImportSpec   `json:"spec"`
+	Status            VirtualMachineImportStatus `json:"status,omitempty"`
+}
+
+// VirtualMachineImportSpec is used to create kubevirt VirtualMachines by exporting VM's from migration clusters.
+type VirtualMachineImportSpec struct {
+	SourceCluster corev1.ObjectReference `json:"sourceCluster" wrangler:"required"`
+
+	// VirtualMachineName is the name of the virtual machine that will be
+	// imported. It contains the name or ID of the source virtual machine.
+	// Note that these names may not be DNS1123 compliant and will therefore
+	// be sanitized later.
+	// Examples: "vm-1234", "my-VM" or "5649cac7-3871-4bb5-aab6-c72b8c18d0a2"
+	VirtualMachineName string `json:"virtualMachineName" wrangler:"required,minLength=1"`
+
+	// +optional
+	// If empty, new VirtualMachineImport will be mapped to Management Network.
+	NetworkMapping []NetworkMapping `json:"networkMapping,omitempty"`
+
+	// +optional
+	// AutoCreateNetworks enables the creation of VLAN networks for source
+	// networks that are not mapped but have a known VLAN ID.
+	AutoCreateNetworks *NetworkAutoCreation `json:"autoCreateNetworks,omitempty"`
+
+	// +optional
+	// DefaultDestinationNetwork is the network that all network interfaces
+	// are attached to that are not matched by a network mapping.
+	DefaultDestinationNetwork string `json:"defaultDestinationNetwork,omitempty" wrangler:"maxLength=127,validChars=a-z0-9/-"`
+
+	// +optional
+	// OnUnmapped defines what happens to network interfaces that are neither
+	// matched by a network mapping nor by the default destination network:
+	// - drop: The network interface is not added to the imported VM.
+	// - pod-network: The first of these interfaces is attached to the pod
+	//   network, the others are dropped.
+	// - fail: The preflight checks fail.
+	// Defaults to "drop".
+	OnUnmapped *string `json:"onUnmapped,omitempty" wrangler:"type=string,options=drop|pod-network|fail"`
+
+	// +optional
+	// The default network interface model. This is always used when:
+	// - Auto-detection fails (OpenStack source client does not have auto-detection, therefore this field is used for every network interface).
+	// - No network mapping is provided and a "pod-network" is auto-created.
+	// Defaults to "virtio".
+	DefaultNetworkInterfaceModel *string `json:"defaultNetworkInterfaceModel,omitempty" wrangler:"type=string,options=e1000|e1000e|ne2k_pci|pcnet|rtl8139|virtio"`
+
+	// +optional
+	StorageClass string `json:"storageClass,omitempty" wrangler:"maxLength=253,validChars=a-z0-9.-"`
+
+	// +optional
+	// DiskMapping overrides the storage settings of individual disks. The
+	// first matching entry is used for each disk, disks that are not matched
+	// use the storage class of the import.
+	DiskMapping []DiskMapping `json:"diskMapping,omitempty"`
+
+	// +optional
+	// IncludeDisks lists the disks of the source VM that are imported. If
+	// it is empty, all disks are imported.
+	IncludeDisks []DiskSelector `json:"includeDisks,omitempty"`
+
+	// +optional
+	// ExcludeDisks lists the disks of the source VM that are not imported.
+	// It takes precedence over IncludeDisks.
+	ExcludeDisks []DiskSelector `json:"excludeDisks,omitempty"`
+
+	// +optional
+	// CDROMImport defines how the CD-ROM drives of the source VM are
+	// imported:
+	// - none: The CD-ROM drives are not added to the imported VM.
+	// - empty: The CD-ROM drives are added without media.
+	// - image: Attached ISO images are imported as read-only CD-ROMs, the
+	//   other CD-ROM drives are added without media.
+	// Defaults to "none".
+	CDROMImport *string `json:"cdromImport,omitempty" wrangler:"type=string,options=none|empty|image"`
+
+	// +optional
+	// The bus type that is used for imported disks if auto-detection fails.
+	// Defaults to "virtio".
+	DefaultDiskBusType *kubevirtv1.DiskBus `json:"defaultDiskBusType,omitempty" wrangler:"type=string,options=virtio|sata|scsi|usb"`
+
+	// +optional
+	// SkipPreflightChecks allows you to forcefully skip the preflight checks.
+	// Defaults to false.
+	SkipPreflightChecks *bool `json:"skipPreflightChecks,omitempty"`
+
+	// +optional
+	// CPU configures the CPU model of the imported VM and the CPU settings
+	// that are carried over from the source VM.
+	CPU *CPUOptions `json:"cpu,omitempty"`
+
+	// +optional
+	// ResourcePolicy configures the resource requests, the memory overhead
+	// and the hugepages of the imported VM. Fields that are not set are
+	// taken from the resource policy of the source.
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
+
+	// +optional
+	// MetadataMapping selects the metadata of the source VM, e.g. VMware
+	// tags or OpenStack server metadata, that is added to the imported VM
+	// as labels or annotations. The first matching rule is used for each
+	// key, keys that are not matched are dropped. If empty, all metadata
+	// is added as annotations.
+	MetadataMapping []MetadataMapping `json:"metadataMapping,omitempty"`
+
+	// +optional
+	// Schedule restricts the time at which the source VM may be shut down
+	// and exported. If empty, the import starts immediately.
+	Schedule *ImportSchedule `json:"schedule,omitempty"`
+
+	// +optional
+	// Vmware contains the options that only apply to VMware imports.
+	Vmware *VmwareImportOptions `json:"vmware,omitempty"`
+}
+
+// VmwareImportOptions contains the options of an import from a VMware source.
+type VmwareImportOptions struct {
+	// +optional
+	// Folder is the folder of the source VM.
+	Folder string `json:"folder,omitempty"`
+
+	// +optional
+	// ForcePowerOff is a flag to indicate whether the VM should be powered
+	// off forcefully before the export is started. By default, the VM import
+	// controller will try to perform a graceful shutdown of the VM guest OS.
+	// Defaults to false.
+	ForcePowerOff *bool `json:"forcePowerOff,omitempty"`
+
+	// +optional
+	// GracefulShutdownTimeoutSeconds is the time to wait for the VM guest OS
+	// to be shutdown gracefully before a hard power off is triggered.
+	// Defaults to 60 seconds.
+	GracefulShutdownTimeoutSeconds int32 `json:"gracefulShutdownTimeoutSeconds,omitempty" wrangler:"min=0,max=3600"`
+}
+
+// CPUOptions configures the CPU of the imported VM.
+type CPUOptions struct {
+	// +optional
+	// Model is the CPU model of the imported VM, e.g. "host-passthrough",
+	// "host-model" or a named model like "Skylake-Server". If empty, the
+	// default CPU model of the cluster is used.
+	Model string `json:"model,omitempty" wrangler:"maxLength=63,validChars=a-zA-Z0-9._-"`
+
+	// +optional
+	// PreserveFeatures carries over the CPU features that are hidden or
+	// forced by the source VM (VMware CPUID masks).
+	// Defaults to false.
+	PreserveFeatures *bool `json:"preserveFeatures,omitempty"`
+
+	// +optional
+	// PreservePlacement carries over dedicated CPUs and the guest NUMA
+	// topology of the source VM (VMware latency sensitivity and vNUMA,
+	// OpenStack `hw:cpu_policy`, `hw:numa_nodes` and `hw:mem_page_size`).
+	// The nodes of the cluster must provide the CPU manager and hugepages.
+	// Defaults to false.
+	PreservePlacement *bool `json:"preservePlacement,omitempty"`
+
+	// +optional
+	// PreserveReservations sets the CPU and memory requests of the imported
+	// VM from the reservations of the source VM and its CPU limit from the
+	// CPU limit of the source VM (VMware).
+	// Defaults to false.
+	PreserveReservations *bool `json:"preserveReservations,omitempty"`
+}
+
+// ResourcePolicy configures the resource requests, the memory overhead and
+// the hugepages of imported VMs. The limits of an imported VM are derived
+// from the size of the source VM.
+type ResourcePolicy struct {
+	// +optional
+	// CPURequestPercentage is the CPU request of the VM as a percentage of
+	// its CPU limit. Defaults to the CPU ratio of the `overcommit-config`
+	// setting of Harvester.
+	CPURequestPercentage *int32 `json:"cpuRequestPercentage,omitempty" wrangler:"min=1,max=100"`
+
+	// +optional
+	// MemoryRequestPercentage is the memory request of the VM as a
+	// percentage of its memory limit. Defaults to the memory ratio of the
+	// `overcommit-config` setting of Harvester.
+	MemoryRequestPercentage *int32 `json:"memoryRequestPercentage,omitempty" wrangler:"min=1,max=100"`
+
+	// +optional
+	// ReservedMemory is the memory that is reserved for the virtualization
+	// overhead, e.g. "256Mi". It is added to the memory limit of the VM, so
+	// the guest keeps the memory of the source VM. If empty, Harvester
+	// reserves its default overhead from the memory of the guest.
+	ReservedMemory string `json:"reservedMemory,omitempty"`
+
+	// +optional
+	// HugepageSize backs the memory of the VM with hugepages of the given
+	// size.
+	HugepageSize *string `json:"hugepageSize,omitempty" wrangler:"type=string,options=2Mi|1Gi"`
+}
+
+// ImportSchedule defines when the disruptive part of an import, i.e. the
+// shutdown of the source VM, is allowed to start.
+type ImportSchedule struct {
+	// +optional
+	// StartAfter is the earliest point in time the import may start.
+	StartAfter *metav1.Time `json:"startAfter,omitempty"`
+
+	// +optional
+	// Window is a recurring maintenance window in which the import may start.
+	Window *MaintenanceWindow `json:"window,omitempty"`
+}
+
+// MaintenanceWindow describes a recurring time window.
+type MaintenanceWindow struct {
+	// Cron is a standard 5-field cron expression (UTC) that defines when
+	// the window opens, e.g. "0 22 * * SAT".
+	Cron string `json:"cron" wrangler:"required,minLength=1"`
+
+	// Duration defines how long the window stays open, e.g. "4h".
+	Duration metav1.Duration `json:"duration" wrangler:"required"`
+}
+
+// DiskMapping overrides the storage settings of the disks it matches. All
+// selectors that are set must match. At least one selector is required.
+type DiskMapping struct {
+	// +optional
+	// Index matches the disk at the given position in the source VM,
+	// starting at 0.
+	Index *int32 `json:"index,omitempty" wrangler:"min=0"`
+
+	// +optional
+	// DeviceID matches the disk with the given device ID, see the
+	// `deviceID` in the disk status.
+	DeviceID string `json:"deviceID,omitempty"`
+
+	// +optional
+	// Datastore matches the VMware disks that are stored on the given
+	// datastore.
+	Datastore string `json:"datastore,omitempty"`
+
+	// +optional
+	// VolumeType matches the OpenStack disks of the given Cinder volume
+	// type.
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// +optional
+	// StorageClass is the storage class of the matched disks. Defaults to
+	// the storage class of the import.
+	StorageClass string `json:"storageClass,omitempty" wrangler:"maxLength=253,validChars=a-z0-9.-"`
+
+	// +optional
+	// VolumeMode is the volume mode of the PVCs of the matched disks.
+	// Defaults to "Block".
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty" wrangler:"type=string,options=Block|Filesystem"`
+
+	// +optional
+	// AccessMode is the access mode of the PVCs of the matched disks.
+	// Defaults to "ReadWriteMany".
+	AccessMode *corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty" wrangler:"type=string,options=ReadWriteOnce|ReadOnlyMany|ReadWriteMany|ReadWriteOncePod"`
+
+	// +optional
+	// Skip excludes the matched disks from the import.
+	Skip bool `json:"skip,omitempty"`
+}
+
+type NetworkMapping struct {
+	SourceNetwork      string `json:"sourceNetwork" wrangler:"required,minLength=1"`
+	DestinationNetwork string `json:"destinationNetwork" wrangler:"required,minLength=1,maxLength=127,validChars=a-z0-9/-"`
+	// +optional
+	// MatchType defines how the source network is matched against the
+	// network names of the source VM:
+	// - exact: The name must be equal to the source network.
+	// - glob: The source network is a shell pattern, e.g. "vlan-*".
+	// - regex: The source network is a regular expression that must match
+	//   the whole name.
+	// Defaults to "exact".
+	MatchType *string `json:"matchType,omitempty" wrangler:"type=string,options=exact|glob|regex"`
+	// +optional
+	// Override the network interface model that is auto-detected (VMware)
+	// or defaulted (OpenStack).
+	NetworkInterfaceModel *string `json:"networkInterfaceModel,omitempty" wrangler:"type=string,options=e1000|e1000e|ne2k_pci|pcnet|rtl8139|virtio"`
+}
+
+// NetworkInterfaceMapping describes to which network a network interface of
+// the source VM is attached.
+type NetworkInterfaceMapping struct {
+	MACAddress    string `json:"macAddress,omitempty"`
+	SourceNetwork string `json:"sourceNetwork"`
+
+	// DestinationNetwork is the network the interface is attached to, or
+	// "pod-network" for the pod network. It is empty if the network
+	// interface is dropped.
+	DestinationNetwork string `json:"destinationNetwork,omitempty"`
+
+	// BootOrder is the position of the network interface in the boot order
+	// of the source VM (PXE boot), starting at 1. It is 0 if the source VM
+	// does not boot from the network interface.
+	BootOrder int32 `json:"bootOrder,omitempty" wrangler:"min=0"`
+}
+
+// NetworkAutoCreation configures the creation of Harvester VLAN networks
+// for the unmapped source networks of an import.
+type NetworkAutoCreation struct {
+	// ClusterNetwork is the Harvester cluster network the VLAN networks are
+	// created on, e.g. "mgmt".
+	ClusterNetwork string `json:"clusterNetwork" wrangler:"required,minLength=1,maxLength=12,validChars=a-z0-9-"`
+}
+
+// VirtualMachineImportStatus tracks the status of the VirtualMachineImport export from migration and import into the Harvester cluster
+type VirtualMachineImportStatus struct {
+	// Phase is the current phase of the import.
+	Phase ImportPhase `json:"phase,omitempty"`
+
+	// +optional
+	Conditions []common.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Disks []DiskStatus `json:"disks,omitempty"`
+
+	// +optional
+	// NewVirtualMachine is the UID of the created virtual machine.
+	NewVirtualMachine string `json:"newVirtualMachine,omitempty"`
+
+	// +optional
+	// ImportedVirtualMachineName is the sanitized and definite name of the
+	// target virtual machine that will be created in the Harvester cluster.
+	// The name is DNS1123 compliant.
+	ImportedVirtualMachineName string `json:"importedVirtualMachineName,omitempty"`
+
+	// +optional
+	// PhaseHistory records when the import entered and left each phase and
+	// each step of the phases that cover more than one step.
+	// Only the last 20 entries are kept.
+	PhaseHistory []PhaseTiming `json:"phaseHistory,omitempty"`
+
+	// +optional
+	// ElapsedTime is the time spent on the import up to the last phase
+	// transition. It is not updated while the import stays in a phase.
+	ElapsedTime *metav1.Duration `json:"elapsedTime,omitempty"`
+
+	// +optional
+	// SourceDowntime is the time between the shutdown of the source VM and
+	// the start of the imported VM.
+	SourceDowntime *metav1.Duration `json:"sourceDowntime,omitempty"`
+
+	// +optional
+	// DiskCount is the number of disks that are imported.
+	DiskCount int32 `json:"diskCount,omitempty"`
+
+	// +optional
+	// Progress is the estimated progress of the import in percent.
+	Progress int32 `json:"progress,omitempty" wrangler:"min=0,max=100"`
+
+	// +optional
+	// GeneratedNetworkMapping contains the network mappings that were added
+	// by the controller for the networks it created, see `autoCreateNetworks`.
+	GeneratedNetworkMapping []NetworkMapping `json:"generatedNetworkMapping,omitempty"`
+
+	// +optional
+	// NetworkInterfaces lists the network interfaces of the source VM and
+	// the networks they are attached to.
+	NetworkInterfaces []NetworkInterfaceMapping `json:"networkInterfaces,omitempty"`
+
+	// +optional
+	// EmptyCDROMDrives lists the CD-ROM drives of the source VM that are
+	// added to the imported VM without media.
+	EmptyCDROMDrives []CDROMDrive `json:"emptyCDROMDrives,omitempty"`
+
+	// +optional
+	// SourceVirtualMachine identifies the source VM. It is recorded during
+	// the preflight checks.
+	SourceVirtualMachine *SourceVirtualMachineIdentity `json:"sourceVirtualMachine,omitempty"`
+
+	// +optional
+	// ImportTime is the time of the import that is recorded in the
+	// provenance annotations of all imported objects. It is recorded during
+	// the preflight checks.
+	ImportTime string `json:"importTime,omitempty"`
+
+	// +optional
+	// StorageClassMapping is the storage class mapping of the source at
+	// the time of the preflight checks. It is used to resolve the storage
+	// class of the disks, so that later changes of the source do not affect
+	// the import.
+	StorageClassMapping []StorageClassMapping `json:"storageClassMapping,omitempty"`
+}
+
+// StorageClassMapping maps the disks of a source that are matched by the
+// datastore (VMware) or Cinder volume type (OpenStack) pattern to a storage
+// class. The patterns use the shell glob syntax.
+type StorageClassMapping struct {
+	// +optional
+	// Datastore is the pattern that is matched against the datastore of
+	// a VMware disk.
+	Datastore string `json:"datastore,omitempty"`
+
+	// +optional
+	// VolumeType is the pattern that is matched against the Cinder volume
+	// type of an OpenStack disk.
+	VolumeType string `json:"volumeType,omitempty"`
+
+	StorageClass string `json:"storageClass" wrangler:"required,minLength=1,maxLength=253,validChars=a-z0-9.-"`
+}
+
+// SourceVirtualMachineIdentity identifies a VM in its source.
+type SourceVirtualMachineIdentity struct {
+	// ID is the unique identifier of the VM in the source, e.g. the
+	// instance UUID in VMware, the server ID in OpenStack or the ID of the
+	// virtual system in the OVF descriptor.
+	ID string `json:"id,omitempty"`
+	// UUID is the BIOS UUID of the VM (VMware).
+	UUID string `json:"uuid,omitempty"`
+	// Reference is the managed object reference of the VM, e.g. "vm-42"
+	// (VMware).
+	Reference string `json:"reference,omitempty"`
+}
+
+// CDROMDrive describes a CD-ROM drive of the source VM.
+type CDROMDrive struct {
+	// DeviceID identifies the drive in the source.
+	DeviceID string             `json:"deviceID,omitempty"`
+	BusType  kubevirtv1.DiskBus `json:"busType" wrangler:"type=string,options=sata|scsi"`
+}
+
+// DiskStatus contains the information about a disk of the imported VM.
+type DiskStatus struct {
+	Name                string             `json:"name"`
+	Size                int64              `json:"size"`
+	LocalPath           string             `json:"localPath,omitempty"`
+	Route               string             `json:"route,omitempty"`
+	VirtualMachineImage string             `json:"virtualMachineImage,omitempty"`
+	Conditions          []common.Condition `json:"conditions,omitempty"`
+	BusType             kubevirtv1.DiskBus `json:"busType,omitempty" wrangler:"type=string,options=virtio|sata|scsi|usb"`
+	// Index is the position of the disk in the source VM, starting at 0.
+	Index int32 `json:"index"`
+	// DeviceID identifies the disk in the source. For VMware this is the
+	// type name of the controller followed by its bus number and the unit
+	// number of the disk, e.g. `VirtualLsiLogicController0:1`, which is the
+	// last element of the device ID in the export lease. For OpenStack it
+	// is the device name of the attachment, e.g. `/dev/vdb`, and for OVA
+	// the disk ID of the OVF descriptor. Disk selectors and disk mappings
+	// match against this value.
+	DeviceID string `json:"deviceID,omitempty"`
+	// FileName is the name of the file backing the disk (VMware, OVA).
+	FileName string `json:"fileName,omitempty"`
+	// VolumeID is the ID of the Cinder volume of the disk (OpenStack).
+	VolumeID string `json:"volumeID,omitempty"`
+	// Datastore is the datastore the disk is stored on (VMware).
+	Datastore string `json:"datastore,omitempty"`
+	// VolumeType is the Cinder volume type of the disk (OpenStack).
+	VolumeType string `json:"volumeType,omitempty"`
+	// BootDisk is true for the disk the source VM boots from.
+	BootDisk bool `json:"bootDisk,omitempty"`
+	// BootOrder is the position of the disk in the boot order of the
+	// source VM, starting at 1. It is 0 if the source VM does not list the
+	// disk in its boot order.
+	BootOrder int32 `json:"bootOrder,omitempty" wrangler:"min=0"`
+	// CDROM is true for ISO images that are imported as read-only CD-ROM.
+	CDROM bool `json:"cdrom,omitempty"`
+	// StorageClass is the storage class of the image and the volume of the
+	// disk, empty for the default storage class. It is resolved once the
+	// disk has been exported.
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// MetadataMapping defines how the metadata of the source VM that is
+// matched by the source key is added to the imported VM.
+type MetadataMapping struct {
+	// SourceKey is a shell pattern that is matched against the keys of the
+	// metadata of the source VM, e.g. "tag/*" or "metadata/owner".
+	SourceKey string `json:"sourceKey" wrangler:"required,minLength=1"`
+
+	// +optional
+	// Target defines how the matched metadata is added to the VM:
+	// - annotation: As annotation.
+	// - label: As label. Values are sanitized to the label syntax.
+	// - drop: Not at all.
+	// Defaults to "annotation".
+	Target *string `json:"target,omitempty" wrangler:"type=string,options=annotation|label|drop"`
+}
+
+// DiskSelector selects disks of the source VM. All fields that are set must
+// match. At least one field is required.
+type DiskSelector struct {
+	// +optional
+	// Index matches the disk at the given position in the source VM,
+	// starting at 0.
+	Index *int32 `json:"index,omitempty" wrangler:"min=0"`
+
+	// +optional
+	// DeviceID matches the disk with the given device ID, see the
+	// `deviceID` in the disk status.
+	DeviceID string `json:"deviceID,omitempty"`
+
+	// +optional
+	// FileName matches the VMware and OVA disks backed by the given file.
+	FileName string `json:"fileName,omitempty"`
+
+	// +optional
+	// VolumeID matches the OpenStack disk of the given Cinder volume.
+	VolumeID string `json:"volumeID,omitempty"`
+}
+
+// PhaseTiming contains the entry and exit timestamps of an import phase.
+type PhaseTiming struct {
+	Phase ImportPhase `json:"phase"`
+	// +optional
+	// Step names the step of the import within the phase, if the phase
+	// covers more than one step, e.g. `Preflight`, `ShutdownWait` or
+	// `Export`.
+	Step string `json:"step,omitempty"`
+	// The time the import entered the phase.
+	EnteredTime string `json:"enteredTime"`
+	// The time the import left the phase. Empty for the current phase.
+	ExitedTime string `json:"exitedTime,omitempty"`
+}
+
+type ImportPhase string
+
+// The phases of an import.
+const (
+	ImportPhasePending               ImportPhase = "Pending"
+	ImportPhaseValid                 ImportPhase = "Valid"
+	ImportPhaseInvalid               ImportPhase = "Invalid"
+	ImportPhaseScheduled             ImportPhase = "Scheduled"
+	ImportPhaseSourceReady           ImportPhase = "SourceReady"
+	ImportPhaseDisksExported         ImportPhase = "DisksExported"
+	ImportPhaseDiskImagesSubmitted   ImportPhase = "DiskImagesSubmitted"
+	ImportPhaseDiskImagesReady       ImportPhase = "DiskImagesReady"
+	ImportPhaseDiskImagesFailed      ImportPhase = "DiskImagesFailed"
+	ImportPhaseVirtualMachineCreated ImportPhase = "VirtualMachineCreated"
+	ImportPhaseRunning               ImportPhase = "Running"
+	ImportPhaseFailed                ImportPhase = "Failed"
+)
+
+// The conditions of an import.
+const (
+	ConditionShutdownGuest                condition.Cond = "VMShutdownGuest"
+	ConditionPoweringOff                  condition.Cond = "VMPoweringOff"
+	ConditionPoweredOff                   condition.Cond = "VMPoweredOff"
+	ConditionExported                     condition.Cond = "VMExported"
+	ConditionExportFailed                 condition.Cond = "VMExportFailed"
+	ConditionImportFailed                 condition.Cond = "VMImportFailed"
+	ConditionImported                     condition.Cond = "VMImported"
+	ConditionVirtualMachineImageSubmitted condition.Cond = "VirtualMachineImageSubmitted"
+	ConditionVirtualMachineImageReady     condition.Cond = "VirtualMachineImageReady"
+	ConditionVirtualMachineImageFailed    condition.Cond = "VirtualMachineImageFailed"
+)
@@ -21,6 +21,7 @@ func main() {
 			"migration.harvesterhci.io": {
 				Types: []interface{}{
 					"./pkg/apis/migration.harvesterhci.io/v1beta1",
+					"./pkg/apis/migration.harvesterhci.io/v1beta2",
 				},
 				GenerateTypes: true,
 			},
@@ -13,14 +13,24 @@ import (
 	"github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
 	"github.com/rancher/wrangler/v3/pkg/generated/controllers/storage"
 	"github.com/rancher/wrangler/v3/pkg/start"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
+	migrationv1beta1 "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	sc "github.com/harvester/vm-import-controller/pkg/controllers/migration"
 	"github.com/harvester/vm-import-controller/pkg/crd"
 	"github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io"
 )
 
+const (
+	eventComponentName = "vm-import-controller"
+)
+
 func Start(ctx context.Context, restConfig *rest.Config) error {
 	if err := crd.Create(ctx, restConfig); err != nil {
 		return err
@@ -94,13 +104,41 @@ func Register(ctx context.Context, restConfig *rest.Config) error {
 		return err
 	}
 
-	sc.RegisterVmwareController(ctx, migrationFactory.Migration().V1beta1().VmwareSource(), coreFactory.Core().V1().Secret())
-	sc.RegisterOvaController(ctx, migrationFactory.Migration().V1beta1().OvaSource(), coreFactory.Core().V1().Secret())
-	sc.RegisterOpenstackController(ctx, migrationFactory.Migration().V1beta1().OpenstackSource(), coreFactory.Core().V1().Secret())
+	recorder, err := newEventRecorder(ctx, restConfig)
+	if err != nil {
+		return err
+	}
+
+	sc.RegisterVmwareController(ctx, migrationFactory.Migration().V1beta1().VmwareSource(), coreFactory.Core().V1().Secret(), recorder)
+	sc.RegisterOvaController(ctx, migrationFactory.Migration().V1beta1().OvaSource(), coreFactory.Core().V1().Secret(), recorder)
+	sc.RegisterOpenstackController(ctx, migrationFactory.Migration().V1beta1().OpenstackSource(), coreFactory.Core().V1().Secret(), recorder)
+	sc.RegisterInventoryController(ctx, migrationFactory.Migration().V1beta1().SourceInventory(), migrationFactory.Migration().V1beta1().VmwareSource(),
+		migrationFactory.Migration().V1beta1().OvaSource(), migrationFactory.Migration().V1beta1().OpenstackSource(), coreFactory.Core().V1().Secret())
 	sc.RegisterVMImportController(ctx, migrationFactory.Migration().V1beta1().VmwareSource(), migrationFactory.Migration().V1beta1().OpenstackSource(),
 		migrationFactory.Migration().V1beta1().OvaSource(), coreFactory.Core().V1().Secret(), migrationFactory.Migration().V1beta1().VirtualMachineImport(),
-		harvesterFactory.Harvesterhci().V1beta1().VirtualMachineImage(), kubevirtFactory.Kubevirt().V1().VirtualMachine(),
-		coreFactory.Core().V1().PersistentVolumeClaim(), scCache, cniFactory.K8s().V1().NetworkAttachmentDefinition().Cache())
+		harvesterFactory.Harvesterhci().V1beta1().VirtualMachineImage(), kubevirtFactory.Kubevirt().V1().VirtualMachine(), kubevirtFactory.Kubevirt().V1().KubeVirt().Cache(),
+		coreFactory.Core().V1().PersistentVolumeClaim(), scCache, harvesterFactory.Harvesterhci().V1beta1().Setting().Cache(), cniFactory.K8s().V1().NetworkAttachmentDefinition(), recorder)
 
 	return start.All(ctx, 1, migrationFactory, coreFactory, harvesterFactory, kubevirtFactory, storageFactory, cniFactory)
 }
+
+// newEventRecorder creates a recorder that emits Kubernetes events for the
+// objects managed by the controllers.
+func newEventRecorder(ctx context.Context, restConfig *rest.Config) (record.EventRecorder, error) {
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := migrationv1beta1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	broadcaster := record.NewBroadcaster(record.WithContext(ctx))
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: client.CoreV1().Events(""),
+	})
+
+	return broadcaster.NewRecorder(scheme, corev1.EventSource{Component: eventComponentName}), nil
+}
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"fmt"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+// checkBootDisk makes sure that the boot disk of the source VM is not
+// excluded from the import. The disks of the source VM are only queried if
+// the import excludes any disks.
+func checkBootDisk(vm *migration.VirtualMachineImport, vmo VirtualMachineOperations) error {
+	if !hasDiskExclusions(vm) {
+		return nil
+	}
+
+	diskInfos, err := vmo.GetDiskInfos(vm)
+	if err != nil {
+		return fmt.Errorf("failed to get the disks of the source VM: %w", err)
+	}
+
+	for _, d := range diskInfos {
+		if d.BootDisk && util.IsDiskSkipped(vm, d) {
+			return fmt.Errorf("%w: disk %d (%s) is the boot disk of the source VM", util.ErrBootDiskExcluded, d.Index, d.DeviceID)
+		}
+	}
+
+	return nil
+}
+
+// hasDiskExclusions reports whether the import may exclude any disks of the
+// source VM.
+func hasDiskExclusions(vm *migration.VirtualMachineImport) bool {
+	if len(vm.Spec.IncludeDisks) > 0 || len(vm.Spec.ExcludeDisks) > 0 {
+		return true
+	}
+	for _, dm := range vm.Spec.DiskMapping {
+		if dm.Skip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package migration
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/metrics"
+)
+
+// Reasons of the events that are emitted for `VirtualMachineImport` and
+// `Source` objects.
+const (
+	eventReasonImportStatusChanged      = "ImportStatusChanged"
+	eventReasonImportReconcileFailed    = "ReconcileFailed"
+	eventReasonPreflightChecksFailed    = "PreflightChecksFailed"
+	eventReasonNetworkCreated           = "NetworkCreated"
+	eventReasonSanitizeFailed           = "SanitizeFailed"
+	eventReasonExportFailed             = "ExportFailed"
+	eventReasonImportFailed             = "ImportFailed"
+	eventReasonEmptyCDROMDrivesDropped  = "EmptyCDROMDrivesDropped"
+	eventReasonSourceReady              = "SourceReady"
+	eventReasonSourceVerificationFailed = "SourceVerificationFailed"
+)
+
+// importStatusEventType returns the event type that is used when a
+// `VirtualMachineImport` transitions into the given status.
+func importStatusEventType(status migration.ImportStatus) string {
+	switch status {
+	case migration.VirtualMachineImportInvalid, migration.VirtualMachineMigrationFailed, migration.DiskImagesFailed:
+		return corev1.EventTypeWarning
+	default:
+		return corev1.EventTypeNormal
+	}
+}
+
+// recordImportStatusTransition emits an event if the status of the given
+// `VirtualMachineImport` differs from the previous one.
+func recordImportStatusTransition(recorder record.EventRecorder, vm *migration.VirtualMachineImport, oldStatus migration.ImportStatus) {
+	if vm == nil || vm.Status.Status == oldStatus {
+		return
+	}
+
+	from := oldStatus
+	if from == "" {
+		from = "new"
+	}
+
+	recorder.Eventf(vm, importStatusEventType(vm.Status.Status), eventReasonImportStatusChanged,
+		"Import status changed from %s to %s", from, vm.Status.Status)
+}
+
+// recordReconcileFailure emits an event if reconciling the given
+// `VirtualMachineImport` failed the import. Transient errors are only retried,
+// as they would otherwise emit an event for each retry.
+func recordReconcileFailure(recorder record.EventRecorder, vm *migration.VirtualMachineImport, oldStatus migration.ImportStatus, err error) {
+	if vm == nil || vm.Status.Status == oldStatus || importStatusEventType(vm.Status.Status) != corev1.EventTypeWarning {
+		return
+	}
+
+	recorder.Eventf(vm, corev1.EventTypeWarning, eventReasonImportReconcileFailed,
+		"Import failed in status %s: %v", vm.Status.Status, err)
+}
+
+// recordSourceVerification emits an event about the result of a source
+// verification. Failures are counted in the metrics as well.
+func recordSourceVerification(recorder record.EventRecorder, obj runtime.Object, err error) {
+	if err != nil {
+		if source, ok := obj.(migration.SourceInterface); ok {
+			metrics.IncSourceVerificationFailures(source.GetKind())
+		}
+		recorder.Event(obj, corev1.EventTypeWarning, eventReasonSourceVerificationFailed,
+			fmt.Sprintf("Failed to verify source: %v", err))
+		return
+	}
+
+	recorder.Event(obj, corev1.EventTypeNormal, eventReasonSourceReady, "Source verified successfully")
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_recordReconcileFailure(t *testing.T) {
+	assert := require.New(t)
+	err := errors.New("connection refused")
+	testCases := []struct {
+		desc      string
+		vm        *migration.VirtualMachineImport
+		oldStatus migration.ImportStatus
+		expected  []string
+	}{
+		{
+			desc:      "Transient error is retried",
+			vm:        &migration.VirtualMachineImport{Status: migration.VirtualMachineImportStatus{Status: migration.SourceReady}},
+			oldStatus: migration.SourceReady,
+		},
+		{
+			desc:      "Transient error during a status transition",
+			vm:        &migration.VirtualMachineImport{Status: migration.VirtualMachineImportStatus{Status: migration.DisksExported}},
+			oldStatus: migration.SourceReady,
+		},
+		{
+			desc:      "Failed import",
+			vm:        &migration.VirtualMachineImport{Status: migration.VirtualMachineImportStatus{Status: migration.VirtualMachineMigrationFailed}},
+			oldStatus: migration.SourceReady,
+			expected:  []string{"Warning ReconcileFailed Import failed in status VMMigrationFailed: connection refused"},
+		},
+		{
+			desc:      "Import that has already failed",
+			vm:        &migration.VirtualMachineImport{Status: migration.VirtualMachineImportStatus{Status: migration.VirtualMachineMigrationFailed}},
+			oldStatus: migration.VirtualMachineMigrationFailed,
+		},
+		{
+			desc:      "No import returned",
+			oldStatus: migration.SourceReady,
+		},
+	}
+
+	for _, tc := range testCases {
+		recorder := record.NewFakeRecorder(10)
+		recordReconcileFailure(recorder, tc.vm, tc.oldStatus, err)
+		close(recorder.Events)
+
+		var events []string
+		for e := range recorder.Events {
+			events = append(events, e)
+		}
+		assert.Equal(tc.expected, events, tc.desc)
+	}
+}
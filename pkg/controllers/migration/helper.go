@@ -2,12 +2,16 @@ package migration
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	"github.com/harvester/vm-import-controller/pkg/util"
 )
@@ -53,10 +57,12 @@ func (h *virtualMachineHandler) reconcileDiskImageStatus(vm *migration.VirtualMa
 			"name":      vm.Name,
 			"namespace": vm.Namespace,
 		}).Error("The imported VM has no disks, being marked as invalid and will be ignored")
+		h.recorder.Event(vm, corev1.EventTypeWarning, eventReasonImportFailed, "The imported VM has no disks")
 
 		vm.Status.Status = migration.VirtualMachineImportInvalid
+		setImportFailedCondition(vm, migration.ReasonNoDisks, "the imported VM has no disks")
 
-		return h.importVM.UpdateStatus(vm)
+		return h.updateStatus(vm)
 	}
 
 	err := h.createVirtualMachineImages(vm)
@@ -65,7 +71,7 @@ func (h *virtualMachineHandler) reconcileDiskImageStatus(vm *migration.VirtualMa
 		var newVM *migration.VirtualMachineImport
 		var newErr error
 		if !reflect.DeepEqual(orgStatus.DiskImportStatus, vm.Status.DiskImportStatus) {
-			newVM, newErr = h.importVM.UpdateStatus(vm)
+			newVM, newErr = h.updateStatus(vm)
 		}
 
 		if newErr != nil {
@@ -83,7 +89,7 @@ func (h *virtualMachineHandler) reconcileDiskImageStatus(vm *migration.VirtualMa
 		vm.Status.Status = migration.DiskImagesSubmitted
 	}
 
-	return h.importVM.UpdateStatus(vm)
+	return h.updateStatus(vm)
 }
 
 func (h *virtualMachineHandler) reconcileVirtualMachineStatus(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
@@ -100,8 +106,9 @@ func (h *virtualMachineHandler) reconcileVirtualMachineStatus(vm *migration.Virt
 	}
 
 	vm.Status.Status = migration.VirtualMachineRunning
+	setImportedCondition(vm)
 
-	return h.importVM.UpdateStatus(vm)
+	return h.updateStatus(vm)
 }
 
 func (h *virtualMachineHandler) reconcilePreFlightChecks(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
@@ -118,19 +125,26 @@ func (h *virtualMachineHandler) reconcilePreFlightChecks(vm *migration.VirtualMa
 			"spec.sourcecluster.kind": vm.Spec.SourceCluster.Kind,
 			"spec.virtualMachineName": vm.Spec.VirtualMachineName,
 		}).Errorf("Failed to perform source cluster specific preflight checks: %v", err)
+		h.recorder.Eventf(vm, corev1.EventTypeWarning, eventReasonPreflightChecksFailed, "Preflight checks failed: %v", err)
 
 		// Stop the reconciling for good as the checks failed.
 		vm.Status.Status = migration.VirtualMachineImportInvalid
+		setImportFailedCondition(vm, importFailureReason(err, migration.ReasonPreflightChecksFailed), err.Error())
 	} else {
 		vm.Status.Status = migration.VirtualMachineImportValid
 	}
 
-	return h.importVM.UpdateStatus(vm)
+	return h.updateStatus(vm)
 }
 
 func (h *virtualMachineHandler) runVirtualMachineExport(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
 	err := h.triggerExport(vm)
 	if err != nil {
+		if errors.Is(err, util.ErrScheduleWindowClosed) {
+			// Hold the import until the next schedule window opens.
+			vm.Status.Status = migration.VirtualMachineImportScheduled
+			return h.updateStatus(vm)
+		}
 		return vm, err
 	}
 
@@ -138,11 +152,34 @@ func (h *virtualMachineHandler) runVirtualMachineExport(vm *migration.VirtualMac
 		vm.Status.Status = migration.DisksExported
 	}
 
-	if util.ConditionExists(vm.Status.ImportConditions, migration.VirtualMachineExportFailed, corev1.ConditionTrue) {
+	if cond := util.GetCondition(vm.Status.ImportConditions, migration.VirtualMachineExportFailed, corev1.ConditionTrue); cond != nil {
 		vm.Status.Status = migration.VirtualMachineMigrationFailed
+		setImportFailedCondition(vm, migration.ReasonDiskExportFailed, cond.Message)
 	}
 
-	return h.importVM.UpdateStatus(vm)
+	return h.updateStatus(vm)
+}
+
+func (h *virtualMachineHandler) reconcileSchedule(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
+	open, wait, err := util.EvaluateImportSchedule(vm.Spec.Schedule, time.Now())
+	if err != nil {
+		return vm, err
+	}
+
+	if !open {
+		logrus.WithFields(logrus.Fields{
+			"name":      vm.Name,
+			"namespace": vm.Namespace,
+			"opensIn":   wait.String(),
+		}).Info("The import schedule window is closed, requeue")
+
+		h.importVM.EnqueueAfter(vm.Namespace, vm.Name, wait)
+		return vm, nil
+	}
+
+	vm.Status.Status = migration.SourceReady
+
+	return h.updateStatus(vm)
 }
 
 func (h *virtualMachineHandler) triggerResubmit(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
@@ -154,7 +191,7 @@ func (h *virtualMachineHandler) triggerResubmit(vm *migration.VirtualMachineImpo
 
 	vm.Status.Status = migration.SourceReady
 
-	return h.importVM.UpdateStatus(vm)
+	return h.updateStatus(vm)
 }
 
 // abortMigrationIfNecessary checks whether the migration should be aborted based on the error that was passed.
@@ -162,9 +199,72 @@ func (h *virtualMachineHandler) triggerResubmit(vm *migration.VirtualMachineImpo
 // otherwise the error is simply returned.
 func (h *virtualMachineHandler) abortMigrationIfNecessary(vmi *migration.VirtualMachineImport, err error) (*migration.VirtualMachineImport, error) {
 	if errors.Is(err, util.ErrGenerateSourceInterface) {
+		h.recorder.Eventf(vmi, corev1.EventTypeWarning, eventReasonImportFailed, "Aborting the import: %v", err)
 		vmi.Status.Status = migration.VirtualMachineMigrationFailed
-		return h.importVM.UpdateStatus(vmi)
+		setImportFailedCondition(vmi, migration.ReasonSourceNotFound, err.Error())
+		return h.updateStatus(vmi)
 	}
 
 	return vmi, err
 }
+
+// importFailureReason maps the given error to the reason that is reported
+// in the status of a failed `VirtualMachineImport`. The default reason is
+// used for errors that are not typed.
+func importFailureReason(err error, defaultReason string) string {
+	switch {
+	case errors.Is(err, util.ErrInvalidSourceCluster):
+		return migration.ReasonInvalidSourceCluster
+	case errors.Is(err, util.ErrSourceNetworkNotFound):
+		return migration.ReasonSourceNetworkNotFound
+	case errors.Is(err, util.ErrDestinationNetworkNotFound):
+		return migration.ReasonDestinationNetworkNotFound
+	case errors.Is(err, util.ErrInvalidNetworkMapping):
+		return migration.ReasonInvalidNetworkMapping
+	case errors.Is(err, util.ErrStorageClassNotFound):
+		return migration.ReasonStorageClassNotFound
+	case errors.Is(err, util.ErrVirtualMachineNotFound):
+		return migration.ReasonVirtualMachineNotFound
+	case errors.Is(err, util.ErrToolsNotRunning):
+		return migration.ReasonToolsNotRunning
+	case errors.Is(err, util.ErrInvalidSchedule):
+		return migration.ReasonInvalidSchedule
+	case errors.Is(err, util.ErrNetworkCreationFailed):
+		return migration.ReasonNetworkCreationFailed
+	case errors.Is(err, util.ErrInvalidDiskMapping):
+		return migration.ReasonInvalidDiskMapping
+	case errors.Is(err, util.ErrBootDiskExcluded):
+		return migration.ReasonBootDiskExcluded
+	case errors.Is(err, util.ErrFeatureGateDisabled):
+		return migration.ReasonFeatureGateDisabled
+	case errors.Is(err, util.ErrGenerateSourceInterface), apierrors.IsNotFound(err):
+		return migration.ReasonSourceNotFound
+	default:
+		return defaultReason
+	}
+}
+
+// setImportFailedCondition records why the import reached a terminal
+// failure state.
+func setImportFailedCondition(vm *migration.VirtualMachineImport, reason, message string) {
+	vm.Status.ImportConditions = util.AddOrUpdateCondition(vm.Status.ImportConditions, common.Condition{
+		Type:               migration.VirtualMachineImportFailed,
+		Status:             corev1.ConditionTrue,
+		LastUpdateTime:     metav1.Now().Format(time.RFC3339),
+		LastTransitionTime: metav1.Now().Format(time.RFC3339),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setImportedCondition records that the import has been finished successfully.
+func setImportedCondition(vm *migration.VirtualMachineImport) {
+	vm.Status.ImportConditions = util.AddOrUpdateCondition(vm.Status.ImportConditions, common.Condition{
+		Type:               migration.VirtualMachineImported,
+		Status:             corev1.ConditionTrue,
+		LastUpdateTime:     metav1.Now().Format(time.RFC3339),
+		LastTransitionTime: metav1.Now().Format(time.RFC3339),
+		Reason:             migration.ReasonVirtualMachineRunning,
+		Message:            fmt.Sprintf("The VM %s was imported successfully", vm.Status.ImportedVirtualMachineName),
+	})
+}
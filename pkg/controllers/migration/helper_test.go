@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+func Test_importFailureReason(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc          string
+		err           error
+		defaultReason string
+		expected      string
+	}{
+		{
+			desc:          "Source VM not found",
+			err:           fmt.Errorf("%w: server 1234 not found", util.ErrVirtualMachineNotFound),
+			defaultReason: migration.ReasonSanitizeFailed,
+			expected:      migration.ReasonVirtualMachineNotFound,
+		},
+		{
+			desc:          "Source not found",
+			err:           fmt.Errorf("%w: secret not found", util.ErrGenerateSourceInterface),
+			defaultReason: migration.ReasonSanitizeFailed,
+			expected:      migration.ReasonSourceNotFound,
+		},
+		{
+			desc:          "Invalid network mapping",
+			err:           fmt.Errorf("%w: invalid glob pattern", util.ErrInvalidNetworkMapping),
+			defaultReason: migration.ReasonPreflightChecksFailed,
+			expected:      migration.ReasonInvalidNetworkMapping,
+		},
+		{
+			desc:          "Untyped error during sanitization",
+			err:           errors.New("connection refused"),
+			defaultReason: migration.ReasonSanitizeFailed,
+			expected:      migration.ReasonSanitizeFailed,
+		},
+		{
+			desc:          "Untyped error during preflight checks",
+			err:           errors.New("connection refused"),
+			defaultReason: migration.ReasonPreflightChecksFailed,
+			expected:      migration.ReasonPreflightChecksFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, importFailureReason(tc.err, tc.defaultReason), tc.desc)
+	}
+}
@@ -0,0 +1,320 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/source/openstack"
+	"github.com/harvester/vm-import-controller/pkg/source/ova"
+	"github.com/harvester/vm-import-controller/pkg/source/vmware"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+const (
+	// defaultSourceInventoryInterval is the interval in which the inventory
+	// of a source is refreshed.
+	defaultSourceInventoryInterval = 15 * time.Minute
+
+	// maxInventoryVirtualMachines limits the number of VMs listed in an
+	// inventory to keep the object within the size limit of the API server.
+	maxInventoryVirtualMachines = 2000
+)
+
+// InventoryOperations is implemented by the clients of all sources.
+type InventoryOperations interface {
+	// ListVirtualMachines returns the VMs that can be imported from the source.
+	ListVirtualMachines() ([]migration.InventoryVirtualMachine, error)
+}
+
+type inventoryHandler struct {
+	ctx       context.Context
+	inventory migrationController.SourceInventoryController
+	vmware    migrationController.VmwareSourceCache
+	ova       migrationController.OvaSourceCache
+	openstack migrationController.OpenstackSourceCache
+	secret    corecontrollers.SecretCache
+}
+
+func RegisterInventoryController(ctx context.Context, inventory migrationController.SourceInventoryController, vmware migrationController.VmwareSourceController,
+	ova migrationController.OvaSourceController, openstack migrationController.OpenstackSourceController, secret corecontrollers.SecretController) {
+	h := &inventoryHandler{
+		ctx:       ctx,
+		inventory: inventory,
+		vmware:    vmware.Cache(),
+		ova:       ova.Cache(),
+		openstack: openstack.Cache(),
+		secret:    secret.Cache(),
+	}
+
+	inventory.OnChange(ctx, "source-inventory-change", h.OnInventoryChange)
+	vmware.OnChange(ctx, "vmware-source-inventory", func(_ string, s *migration.VmwareSource) (*migration.VmwareSource, error) {
+		if s == nil {
+			return nil, nil
+		}
+		return s, h.ensureInventory(s, "VmwareSource")
+	})
+	ova.OnChange(ctx, "ova-source-inventory", func(_ string, s *migration.OvaSource) (*migration.OvaSource, error) {
+		if s == nil {
+			return nil, nil
+		}
+		return s, h.ensureInventory(s, "OvaSource")
+	})
+	openstack.OnChange(ctx, "openstack-source-inventory", func(_ string, s *migration.OpenstackSource) (*migration.OpenstackSource, error) {
+		if s == nil {
+			return nil, nil
+		}
+		return s, h.ensureInventory(s, "OpenstackSource")
+	})
+}
+
+// sourceInventoryInterval returns the interval in which the inventories of
+// the sources are refreshed. It can be set with the env variable
+// SOURCE_INVENTORY_INTERVAL, e.g. `30m`.
+func sourceInventoryInterval() time.Duration {
+	if val := os.Getenv("SOURCE_INVENTORY_INTERVAL"); val != "" {
+		interval, err := time.ParseDuration(val)
+		if err == nil && interval > 0 {
+			return interval
+		}
+		logrus.Warnf("Ignoring invalid source inventory interval %q", val)
+	}
+	return defaultSourceInventoryInterval
+}
+
+// inventoryName returns the name of the inventory of the given source.
+func inventoryName(kind, name string) string {
+	return fmt.Sprintf("%s-%s", strings.ToLower(kind), name)
+}
+
+// ensureInventory creates the inventory of a source once the source is
+// ready. Existing inventories are enqueued if the spec or the credentials of
+// the source have been changed or the source has recovered, so that they are
+// refreshed without waiting for the interval. The source is enqueued on
+// changes of its credentials secret as well.
+func (h *inventoryHandler) ensureInventory(s sourceObject, kind string) error {
+	if s.GetDeletionTimestamp() != nil || s.ClusterStatus() != migration.ClusterReady {
+		return nil
+	}
+
+	name := inventoryName(kind, s.GetName())
+	inv, err := h.inventory.Cache().Get(s.GetNamespace(), name)
+	if apierrors.IsNotFound(err) {
+		_, err = h.inventory.Create(&migration.SourceInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.GetNamespace(),
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: migration.SchemeGroupVersion.String(),
+						Kind:       kind,
+						Name:       s.GetName(),
+						UID:        s.GetUID(),
+						Controller: ptr.To(true),
+					},
+				},
+			},
+			Spec: migration.SourceInventorySpec{
+				SourceCluster: corev1.ObjectReference{
+					APIVersion: migration.SchemeGroupVersion.String(),
+					Kind:       kind,
+					Namespace:  s.GetNamespace(),
+					Name:       s.GetName(),
+				},
+			},
+		})
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if inventorySourceChanged(inv, s, sourceSecretVersion(h.secret, s)) ||
+		!util.ConditionExists(inv.Status.Conditions, migration.InventoryReadyCondition, corev1.ConditionTrue) {
+		h.inventory.Enqueue(inv.Namespace, inv.Name)
+	}
+	return nil
+}
+
+// inventorySourceChanged reports whether the spec or the credentials of the
+// source have been changed since the inventory has been last refreshed.
+func inventorySourceChanged(inv *migration.SourceInventory, s sourceObject, secretVersion string) bool {
+	return inv.Status.ObservedSourceGeneration != s.GetGeneration() ||
+		inv.Status.ObservedSecretVersion != secretVersion
+}
+
+func (h *inventoryHandler) OnInventoryChange(_ string, inv *migration.SourceInventory) (*migration.SourceInventory, error) {
+	if inv == nil || inv.DeletionTimestamp != nil {
+		return inv, nil
+	}
+
+	s, err := h.getSource(inv.Spec.SourceCluster)
+	if apierrors.IsNotFound(err) {
+		// The inventory is garbage collected together with its source.
+		return inv, nil
+	}
+	if err != nil {
+		return inv, err
+	}
+
+	now := time.Now()
+	if s.ClusterStatus() != migration.ClusterReady {
+		// The inventory is enqueued again once the source is ready.
+		if cond := util.GetCondition(inv.Status.Conditions, migration.InventoryReadyCondition, corev1.ConditionFalse); cond != nil && cond.Reason == migration.ReasonSourceNotReady {
+			return inv, nil
+		}
+		invCopy := inv.DeepCopy()
+		invCopy.Status.Conditions = util.MergeConditions(invCopy.Status.Conditions, []common.Condition{
+			newSourceCondition(inv.Status.Conditions, migration.InventoryReadyCondition, corev1.ConditionFalse,
+				migration.ReasonSourceNotReady, "The source is not ready", now),
+		})
+		return h.inventory.UpdateStatus(invCopy)
+	}
+
+	secretVersion := sourceSecretVersion(h.secret, s)
+	changed := inventorySourceChanged(inv, s, secretVersion)
+	if wait := nextInventoryRefresh(inv.Status.Conditions, changed, now); wait > 0 {
+		h.inventory.EnqueueAfter(inv.Namespace, inv.Name, wait)
+		return inv, nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name":      inv.Name,
+		"namespace": inv.Namespace,
+	}).Info("Refreshing source inventory")
+
+	vms, err := h.listVirtualMachines(s)
+	now = time.Now()
+
+	// The observed source is recorded for failed refreshes as well, so
+	// that they are retried in the retry interval.
+	invCopy := inv.DeepCopy()
+	invCopy.Status.ObservedSourceGeneration = s.GetGeneration()
+	invCopy.Status.ObservedSecretVersion = secretVersion
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":      inv.Name,
+			"namespace": inv.Namespace,
+			"err":       err,
+		}).Error("Failed to refresh source inventory")
+		invCopy.Status.Conditions = util.MergeConditions(invCopy.Status.Conditions, []common.Condition{
+			newSourceCondition(inv.Status.Conditions, migration.InventoryReadyCondition, corev1.ConditionFalse,
+				migration.ReasonInventoryRefreshFailed, err.Error(), now),
+		})
+		return h.inventory.UpdateStatus(invCopy)
+	}
+
+	sort.Slice(vms, func(i, j int) bool {
+		if vms[i].Folder != vms[j].Folder {
+			return vms[i].Folder < vms[j].Folder
+		}
+		return vms[i].Name < vms[j].Name
+	})
+
+	invCopy.Status.VirtualMachineCount = int32(len(vms)) // nolint:gosec
+	invCopy.Status.Truncated = len(vms) > maxInventoryVirtualMachines
+	invCopy.Status.VirtualMachines = vms[:min(len(vms), maxInventoryVirtualMachines)]
+	invCopy.Status.LastRefreshTime = now.UTC().Format(time.RFC3339)
+	invCopy.Status.Conditions = util.MergeConditions(invCopy.Status.Conditions, []common.Condition{
+		newSourceCondition(inv.Status.Conditions, migration.InventoryReadyCondition, corev1.ConditionTrue,
+			migration.ReasonInventoryRefreshed, "", now),
+	})
+
+	return h.inventory.UpdateStatus(invCopy)
+}
+
+// nextInventoryRefresh returns the time to wait until the inventory has to
+// be refreshed again. Failed refreshes are retried in a shorter interval.
+func nextInventoryRefresh(conditions []common.Condition, changed bool, now time.Time) time.Duration {
+	if changed {
+		return 0
+	}
+
+	interval := sourceInventoryInterval()
+	cond := util.GetCondition(conditions, migration.InventoryReadyCondition, corev1.ConditionTrue)
+	if cond == nil {
+		cond = util.GetCondition(conditions, migration.InventoryReadyCondition, corev1.ConditionFalse)
+		interval = min(interval, sourceRetryInterval)
+	}
+	if cond == nil {
+		return 0
+	}
+
+	lastUpdate, err := time.Parse(time.RFC3339, cond.LastUpdateTime)
+	if err != nil {
+		return 0
+	}
+
+	if wait := lastUpdate.Add(interval).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// getSource returns the source referenced by an inventory.
+func (h *inventoryHandler) getSource(ref corev1.ObjectReference) (sourceObject, error) {
+	switch strings.ToLower(ref.Kind) {
+	case migration.KindVmwareSource:
+		return h.vmware.Get(ref.Namespace, ref.Name)
+	case migration.KindOvaSource:
+		return h.ova.Get(ref.Namespace, ref.Name)
+	case migration.KindOpenstackSource:
+		return h.openstack.Get(ref.Namespace, ref.Name)
+	}
+	return nil, fmt.Errorf("source kind %q not supported", ref.Kind)
+}
+
+// listVirtualMachines connects to the given source and lists its VMs.
+func (h *inventoryHandler) listVirtualMachines(s sourceObject) ([]migration.InventoryVirtualMachine, error) {
+	var secret *corev1.Secret
+	if s.HasSecret() {
+		var err error
+		ref := s.SecretReference()
+		secret, err = h.secret.Get(ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup secret %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+	}
+
+	var client InventoryOperations
+	var err error
+	switch strings.ToLower(s.GetKind()) {
+	case migration.KindVmwareSource:
+		endpoint, dc := s.GetConnectionInfo()
+		client, err = vmware.NewClient(h.ctx, endpoint, dc, secret)
+	case migration.KindOvaSource:
+		url, _ := s.GetConnectionInfo()
+		client, err = ova.NewClient(h.ctx, url, secret, s.GetOptions().(migration.OvaSourceOptions))
+	case migration.KindOpenstackSource:
+		endpoint, region := s.GetConnectionInfo()
+		client, err = openstack.NewClient(h.ctx, endpoint, region, secret, s.GetOptions().(migration.OpenstackSourceOptions))
+	default:
+		return nil, fmt.Errorf("source kind %q not supported", s.GetKind())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client: %w", err)
+	}
+
+	if closer, ok := client.(io.Closer); ok {
+		defer closer.Close() //nolint:errcheck
+	}
+
+	return client.ListVirtualMachines()
+}
@@ -0,0 +1,239 @@
+package migration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
+)
+
+// fakeInventoryController records the inventories that are created and
+// enqueued. All other methods are not implemented.
+type fakeInventoryController struct {
+	migrationController.SourceInventoryController
+
+	cache    *indexerCache[*migration.SourceInventory]
+	created  []*migration.SourceInventory
+	enqueued []string
+}
+
+func (c *fakeInventoryController) Cache() generic.CacheInterface[*migration.SourceInventory] {
+	return c.cache
+}
+
+func (c *fakeInventoryController) Create(inv *migration.SourceInventory) (*migration.SourceInventory, error) {
+	c.created = append(c.created, inv)
+	return inv, c.cache.indexer.Add(inv)
+}
+
+func (c *fakeInventoryController) Enqueue(namespace, name string) {
+	c.enqueued = append(c.enqueued, namespace+"/"+name)
+}
+
+func Test_nextInventoryRefresh(t *testing.T) {
+	assert := require.New(t)
+	now := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc       string
+		conditions []common.Condition
+		changed    bool
+		expected   time.Duration
+	}{
+		{
+			desc:     "Never refreshed",
+			expected: 0,
+		},
+		{
+			desc: "Refreshed within the interval",
+			conditions: []common.Condition{
+				{Type: migration.InventoryReadyCondition, Status: corev1.ConditionTrue, LastUpdateTime: "2025-03-15T21:50:00Z"},
+			},
+			expected: 5 * time.Minute,
+		},
+		{
+			desc: "Refreshed before the interval",
+			conditions: []common.Condition{
+				{Type: migration.InventoryReadyCondition, Status: corev1.ConditionTrue, LastUpdateTime: "2025-03-15T21:30:00Z"},
+			},
+			expected: 0,
+		},
+		{
+			desc: "Failed refresh is retried sooner",
+			conditions: []common.Condition{
+				{Type: migration.InventoryReadyCondition, Status: corev1.ConditionFalse, LastUpdateTime: "2025-03-15T21:59:40Z"},
+			},
+			expected: 10 * time.Second,
+		},
+		{
+			desc: "Changed source is refreshed immediately",
+			conditions: []common.Condition{
+				{Type: migration.InventoryReadyCondition, Status: corev1.ConditionTrue, LastUpdateTime: "2025-03-15T21:59:00Z"},
+			},
+			changed:  true,
+			expected: 0,
+		},
+		{
+			desc: "Invalid time is refreshed immediately",
+			conditions: []common.Condition{
+				{Type: migration.InventoryReadyCondition, Status: corev1.ConditionTrue, LastUpdateTime: "yesterday"},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, nextInventoryRefresh(tc.conditions, tc.changed, now), tc.desc)
+	}
+}
+
+func Test_nextInventoryRefresh_interval(t *testing.T) {
+	assert := require.New(t)
+	now := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	conditions := []common.Condition{
+		{Type: migration.InventoryReadyCondition, Status: corev1.ConditionTrue, LastUpdateTime: "2025-03-15T21:59:00Z"},
+	}
+
+	t.Setenv("SOURCE_INVENTORY_INTERVAL", "1h")
+	assert.Equal(59*time.Minute, nextInventoryRefresh(conditions, false, now))
+
+	t.Setenv("SOURCE_INVENTORY_INTERVAL", "invalid")
+	assert.Equal(14*time.Minute, nextInventoryRefresh(conditions, false, now))
+}
+
+func Test_ensureInventory(t *testing.T) {
+	assert := require.New(t)
+	readyConditions := []common.Condition{
+		{Type: migration.InventoryReadyCondition, Status: corev1.ConditionTrue},
+	}
+	testCases := []struct {
+		desc             string
+		source           *migration.VmwareSource
+		inventory        *migration.SourceInventory
+		expectedCreated  bool
+		expectedEnqueued bool
+	}{
+		{
+			desc: "Source not ready",
+			source: &migration.VmwareSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcsim", Namespace: "default", Generation: 1},
+				Spec:       migration.VmwareSourceSpec{Credentials: corev1.SecretReference{Name: "credentials", Namespace: "default"}},
+				Status:     migration.VmwareSourceStatus{Status: migration.ClusterNotReady},
+			},
+		},
+		{
+			desc: "Inventory is created for a ready source",
+			source: &migration.VmwareSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcsim", Namespace: "default", Generation: 1},
+				Spec:       migration.VmwareSourceSpec{Credentials: corev1.SecretReference{Name: "credentials", Namespace: "default"}},
+				Status:     migration.VmwareSourceStatus{Status: migration.ClusterReady},
+			},
+			expectedCreated: true,
+		},
+		{
+			desc: "Unchanged source",
+			source: &migration.VmwareSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcsim", Namespace: "default", Generation: 1},
+				Spec:       migration.VmwareSourceSpec{Credentials: corev1.SecretReference{Name: "credentials", Namespace: "default"}},
+				Status:     migration.VmwareSourceStatus{Status: migration.ClusterReady},
+			},
+			inventory: &migration.SourceInventory{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmwaresource-vcsim", Namespace: "default"},
+				Status: migration.SourceInventoryStatus{
+					Conditions:               readyConditions,
+					ObservedSourceGeneration: 1,
+					ObservedSecretVersion:    "100",
+				},
+			},
+		},
+		{
+			desc: "Changed spec of the source",
+			source: &migration.VmwareSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcsim", Namespace: "default", Generation: 2},
+				Spec:       migration.VmwareSourceSpec{Credentials: corev1.SecretReference{Name: "credentials", Namespace: "default"}},
+				Status:     migration.VmwareSourceStatus{Status: migration.ClusterReady},
+			},
+			inventory: &migration.SourceInventory{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmwaresource-vcsim", Namespace: "default"},
+				Status: migration.SourceInventoryStatus{
+					Conditions:               readyConditions,
+					ObservedSourceGeneration: 1,
+					ObservedSecretVersion:    "100",
+				},
+			},
+			expectedEnqueued: true,
+		},
+		{
+			desc: "Changed credentials of the source",
+			source: &migration.VmwareSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcsim", Namespace: "default", Generation: 1},
+				Spec:       migration.VmwareSourceSpec{Credentials: corev1.SecretReference{Name: "credentials", Namespace: "default"}},
+				Status:     migration.VmwareSourceStatus{Status: migration.ClusterReady},
+			},
+			inventory: &migration.SourceInventory{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmwaresource-vcsim", Namespace: "default"},
+				Status: migration.SourceInventoryStatus{
+					Conditions:               readyConditions,
+					ObservedSourceGeneration: 1,
+					ObservedSecretVersion:    "99",
+				},
+			},
+			expectedEnqueued: true,
+		},
+		{
+			desc: "Failed inventory of a recovered source",
+			source: &migration.VmwareSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcsim", Namespace: "default", Generation: 1},
+				Spec:       migration.VmwareSourceSpec{Credentials: corev1.SecretReference{Name: "credentials", Namespace: "default"}},
+				Status:     migration.VmwareSourceStatus{Status: migration.ClusterReady},
+			},
+			inventory: &migration.SourceInventory{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmwaresource-vcsim", Namespace: "default"},
+				Status: migration.SourceInventoryStatus{
+					Conditions: []common.Condition{
+						{Type: migration.InventoryReadyCondition, Status: corev1.ConditionFalse, Reason: migration.ReasonSourceNotReady},
+					},
+					ObservedSourceGeneration: 1,
+					ObservedSecretVersion:    "100",
+				},
+			},
+			expectedEnqueued: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		secrets := newIndexerCache[*corev1.Secret]()
+		assert.NoError(secrets.indexer.Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default", ResourceVersion: "100"},
+		}))
+		inventory := &fakeInventoryController{cache: newIndexerCache[*migration.SourceInventory]()}
+		if tc.inventory != nil {
+			assert.NoError(inventory.cache.indexer.Add(tc.inventory))
+		}
+		h := &inventoryHandler{
+			inventory: inventory,
+			secret:    secrets,
+		}
+
+		assert.NoError(h.ensureInventory(tc.source, "VmwareSource"), tc.desc)
+		if tc.expectedCreated {
+			assert.Len(inventory.created, 1, tc.desc)
+			assert.Equal("vmwaresource-vcsim", inventory.created[0].Name, tc.desc)
+			assert.Equal("VmwareSource", inventory.created[0].Spec.SourceCluster.Kind, tc.desc)
+			assert.Equal("vcsim", inventory.created[0].OwnerReferences[0].Name, tc.desc)
+		} else {
+			assert.Empty(inventory.created, tc.desc)
+		}
+		if tc.expectedEnqueued {
+			assert.Equal([]string{"default/vmwaresource-vcsim"}, inventory.enqueued, tc.desc)
+		} else {
+			assert.Empty(inventory.enqueued, tc.desc)
+		}
+	}
+}
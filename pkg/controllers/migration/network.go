@@ -0,0 +1,160 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/source"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+// vlanNetwork is a Harvester VLAN network that an unmapped source network
+// is mapped to.
+type vlanNetwork struct {
+	clusterNetwork string
+	vlanID         int32
+}
+
+// mapNetworkInterfaces maps the network interfaces of the source VM to the
+// networks they will be attached to and records the result in the status
+// of the import. If requested, unmapped source networks are mapped to VLAN
+// networks. The networks that do not exist yet are returned and must be
+// created by the caller once all preflight checks have passed.
+func (h *virtualMachineHandler) mapNetworkInterfaces(vm *migration.VirtualMachineImport, ss migration.SourceInterface, vmo VirtualMachineOperations) ([]vlanNetwork, error) {
+	networkInfos, err := vmo.GetNetworkInfos(vm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the network interfaces of the source VM: %w", err)
+	}
+
+	var missing []vlanNetwork
+	if vm.Spec.AutoCreateNetworks != nil {
+		missing, err = h.planVlanNetworks(vm, ss, networkInfos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mappedNetworks, err := source.MapNetworkInterfaces(networkInfos, vm)
+	if err != nil {
+		return nil, err
+	}
+	vm.Status.NetworkInterfaces = source.NetworkInterfaceMappings(mappedNetworks)
+
+	return missing, nil
+}
+
+// planVlanNetworks maps every source network of the VM that is not mapped
+// but has a known VLAN ID to a Harvester VLAN network. The mappings to these
+// networks are recorded in the status of the import. Existing networks are
+// reused if their config matches, the missing ones are returned.
+func (h *virtualMachineHandler) planVlanNetworks(vm *migration.VirtualMachineImport, ss migration.SourceInterface, networkInfos []source.NetworkInfo) ([]vlanNetwork, error) {
+	sourceNetworks := make(map[string]migration.SourceNetwork)
+	for _, sn := range ss.GetNetworks() {
+		sourceNetworks[sn.Name] = sn
+	}
+
+	var mapping []migration.NetworkMapping
+	var missing []vlanNetwork
+	planned := make(map[string]bool)
+	for _, name := range source.UnmappedNetworks(networkInfos, vm.Spec.Mapping) {
+		logrusEntry := logrus.WithFields(logrus.Fields{
+			"name":          vm.Name,
+			"namespace":     vm.Namespace,
+			"sourceNetwork": name,
+		})
+
+		sn, ok := sourceNetworks[name]
+		if !ok || sn.VlanID < 1 || sn.VlanID > 4094 {
+			logrusEntry.WithField("vlanType", sn.VlanType).
+				Warn("Skipping the creation of a network for the unmapped source network because its VLAN ID is unknown")
+			continue
+		}
+
+		network := vlanNetwork{clusterNetwork: vm.Spec.AutoCreateNetworks.ClusterNetwork, vlanID: sn.VlanID}
+		nadName := fmt.Sprintf("%s/%s", vm.Namespace, util.VlanNetworkName(network.clusterNetwork, network.vlanID))
+
+		if !planned[nadName] {
+			planned[nadName] = true
+			exists, err := h.checkVlanNetwork(vm.Namespace, network)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				missing = append(missing, network)
+			}
+		}
+
+		logrusEntry.WithFields(logrus.Fields{
+			"destinationNetwork": nadName,
+			"vlanID":             sn.VlanID,
+		}).Info("Mapping the source network to an auto-created network")
+
+		mapping = append(mapping, migration.NetworkMapping{
+			SourceNetwork:      name,
+			DestinationNetwork: nadName,
+		})
+	}
+
+	vm.Status.GeneratedNetworkMapping = mapping
+
+	return missing, nil
+}
+
+// checkVlanNetwork checks whether the given VLAN network exists in the
+// given namespace. An existing network is only reused if it is the VLAN
+// network for the same cluster network and VLAN ID, a network with the same
+// name but a different config is an error.
+func (h *virtualMachineHandler) checkVlanNetwork(namespace string, network vlanNetwork) (bool, error) {
+	name := util.VlanNetworkName(network.clusterNetwork, network.vlanID)
+	existing, err := h.nadCache.Get(namespace, name)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: '%s/%s': %v", util.ErrNetworkCreationFailed, namespace, name, err)
+	}
+
+	if err := util.CheckVlanNetworkConfig(existing, network.clusterNetwork, network.vlanID); err != nil {
+		return false, fmt.Errorf("%w: existing network '%s/%s' cannot be reused: %v", util.ErrNetworkCreationFailed, namespace, name, err)
+	}
+
+	return true, nil
+}
+
+// createVlanNetworks creates the given VLAN networks in the namespace of the
+// import. A network that has been created in the meantime is reused if its
+// config matches.
+func (h *virtualMachineHandler) createVlanNetworks(vm *migration.VirtualMachineImport, networks []vlanNetwork) error {
+	for _, network := range networks {
+		nad, err := util.NewVlanNetworkAttachmentDefinition(vm.Namespace, network.clusterNetwork, network.vlanID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", util.ErrNetworkCreationFailed, err)
+		}
+		name := fmt.Sprintf("%s/%s", nad.Namespace, nad.Name)
+
+		_, err = h.nad.Create(nad)
+		if apierrors.IsAlreadyExists(err) {
+			existing, err := h.nad.Get(nad.Namespace, nad.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("%w: '%s': %v", util.ErrNetworkCreationFailed, name, err)
+			}
+			if err := util.CheckVlanNetworkConfig(existing, network.clusterNetwork, network.vlanID); err != nil {
+				return fmt.Errorf("%w: existing network '%s' cannot be reused: %v", util.ErrNetworkCreationFailed, name, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w: '%s': %v", util.ErrNetworkCreationFailed, name, err)
+		}
+
+		h.recorder.Eventf(vm, corev1.EventTypeNormal, eventReasonNetworkCreated,
+			"Created VLAN network %s with VLAN ID %d on cluster network %s", name, network.vlanID, network.clusterNetwork)
+	}
+
+	return nil
+}
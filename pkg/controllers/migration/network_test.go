@@ -0,0 +1,178 @@
+package migration
+
+import (
+	"testing"
+
+	ctlcniv1 "github.com/harvester/harvester/pkg/generated/controllers/k8s.cni.cncf.io/v1"
+	cniv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/source"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+// fakeNadController creates the networks in the given cache. All other
+// methods are not implemented.
+type fakeNadController struct {
+	ctlcniv1.NetworkAttachmentDefinitionController
+
+	cache   *indexerCache[*cniv1.NetworkAttachmentDefinition]
+	created []*cniv1.NetworkAttachmentDefinition
+}
+
+func (c *fakeNadController) Create(nad *cniv1.NetworkAttachmentDefinition) (*cniv1.NetworkAttachmentDefinition, error) {
+	if _, err := c.cache.Get(nad.Namespace, nad.Name); err == nil {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{}, nad.Name)
+	}
+	c.created = append(c.created, nad)
+	return nad, c.cache.indexer.Add(nad)
+}
+
+func (c *fakeNadController) Get(namespace, name string, _ metav1.GetOptions) (*cniv1.NetworkAttachmentDefinition, error) {
+	return c.cache.Get(namespace, name)
+}
+
+func newVlanNetwork(t *testing.T, clusterNetwork string, vlanID int32, name string) *cniv1.NetworkAttachmentDefinition {
+	nad, err := util.NewVlanNetworkAttachmentDefinition("default", clusterNetwork, vlanID)
+	require.NoError(t, err)
+	nad.Name = name
+	return nad
+}
+
+func Test_planVlanNetworks(t *testing.T) {
+	assert := require.New(t)
+	ss := &migration.VmwareSource{
+		Status: migration.VmwareSourceStatus{
+			Networks: []migration.SourceNetwork{
+				{Name: "VM Network", VlanID: 100},
+				{Name: "Backup Network", VlanID: 100},
+				{Name: "Storage Network", VlanID: 200},
+				{Name: "Trunk Network"},
+			},
+		},
+	}
+	networkInfos := []source.NetworkInfo{
+		{NetworkName: "VM Network"},
+		{NetworkName: "Backup Network"},
+		{NetworkName: "Storage Network"},
+		{NetworkName: "Trunk Network"},
+	}
+	testCases := []struct {
+		desc            string
+		existing        []*cniv1.NetworkAttachmentDefinition
+		expectedMissing []vlanNetwork
+		expectedErr     bool
+	}{
+		{
+			desc: "No existing networks",
+			expectedMissing: []vlanNetwork{
+				{clusterNetwork: "mgmt", vlanID: 100},
+				{clusterNetwork: "mgmt", vlanID: 200},
+			},
+		},
+		{
+			desc: "Matching network is reused",
+			existing: []*cniv1.NetworkAttachmentDefinition{
+				newVlanNetwork(t, "mgmt", 100, "mgmt-vlan100"),
+			},
+			expectedMissing: []vlanNetwork{
+				{clusterNetwork: "mgmt", vlanID: 200},
+			},
+		},
+		{
+			desc: "Network with a different VLAN ID",
+			existing: []*cniv1.NetworkAttachmentDefinition{
+				newVlanNetwork(t, "mgmt", 300, "mgmt-vlan100"),
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "Network on a different cluster network",
+			existing: []*cniv1.NetworkAttachmentDefinition{
+				newVlanNetwork(t, "other", 200, "mgmt-vlan200"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		nadCache := newIndexerCache[*cniv1.NetworkAttachmentDefinition]()
+		for _, nad := range tc.existing {
+			assert.NoError(nadCache.indexer.Add(nad), tc.desc)
+		}
+		h := &virtualMachineHandler{nadCache: nadCache}
+		vm := &migration.VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: migration.VirtualMachineImportSpec{
+				AutoCreateNetworks: &migration.NetworkAutoCreation{ClusterNetwork: "mgmt"},
+			},
+		}
+
+		missing, err := h.planVlanNetworks(vm, ss, networkInfos)
+		if tc.expectedErr {
+			assert.ErrorIs(err, util.ErrNetworkCreationFailed, tc.desc)
+			continue
+		}
+		assert.NoError(err, tc.desc)
+		assert.Equal(tc.expectedMissing, missing, tc.desc)
+		assert.Equal([]migration.NetworkMapping{
+			{SourceNetwork: "VM Network", DestinationNetwork: "default/mgmt-vlan100"},
+			{SourceNetwork: "Backup Network", DestinationNetwork: "default/mgmt-vlan100"},
+			{SourceNetwork: "Storage Network", DestinationNetwork: "default/mgmt-vlan200"},
+		}, vm.Status.GeneratedNetworkMapping, tc.desc)
+	}
+}
+
+func Test_createVlanNetworks(t *testing.T) {
+	assert := require.New(t)
+	networks := []vlanNetwork{{clusterNetwork: "mgmt", vlanID: 100}}
+	testCases := []struct {
+		desc            string
+		existing        *cniv1.NetworkAttachmentDefinition
+		expectedCreated bool
+		expectedErr     bool
+	}{
+		{
+			desc:            "Network is created",
+			expectedCreated: true,
+		},
+		{
+			desc:     "Matching network created in the meantime",
+			existing: newVlanNetwork(t, "mgmt", 100, "mgmt-vlan100"),
+		},
+		{
+			desc:        "Different network created in the meantime",
+			existing:    newVlanNetwork(t, "mgmt", 200, "mgmt-vlan100"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		nad := &fakeNadController{cache: newIndexerCache[*cniv1.NetworkAttachmentDefinition]()}
+		if tc.existing != nil {
+			assert.NoError(nad.cache.indexer.Add(tc.existing), tc.desc)
+		}
+		h := &virtualMachineHandler{nad: nad, recorder: record.NewFakeRecorder(10)}
+		vm := &migration.VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		}
+
+		err := h.createVlanNetworks(vm, networks)
+		if tc.expectedErr {
+			assert.ErrorIs(err, util.ErrNetworkCreationFailed, tc.desc)
+			continue
+		}
+		assert.NoError(err, tc.desc)
+		if tc.expectedCreated {
+			assert.Len(nad.created, 1, tc.desc)
+			assert.Equal("mgmt-vlan100", nad.created[0].Name, tc.desc)
+		} else {
+			assert.Empty(nad.created, tc.desc)
+		}
+	}
+}
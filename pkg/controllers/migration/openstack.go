@@ -6,30 +6,34 @@ import (
 	"time"
 
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"github.com/sirupsen/logrus"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
-	"github.com/harvester/vm-import-controller/pkg/apis/common"
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
 	"github.com/harvester/vm-import-controller/pkg/source/openstack"
-	"github.com/harvester/vm-import-controller/pkg/util"
 )
 
 type openstackHandler struct {
-	ctx    context.Context
-	source migrationController.OpenstackSourceController
-	secret corecontrollers.SecretController
+	ctx      context.Context
+	source   migrationController.OpenstackSourceController
+	secret   corecontrollers.SecretController
+	recorder record.EventRecorder
 }
 
-func RegisterOpenstackController(ctx context.Context, source migrationController.OpenstackSourceController, secret corecontrollers.SecretController) {
+func RegisterOpenstackController(ctx context.Context, source migrationController.OpenstackSourceController, secret corecontrollers.SecretController, recorder record.EventRecorder) {
 	oHandler := &openstackHandler{
-		ctx:    ctx,
-		source: source,
-		secret: secret,
+		ctx:      ctx,
+		source:   source,
+		secret:   secret,
+		recorder: recorder,
 	}
 	source.OnChange(ctx, "openstack-source-change", oHandler.OnSourceChange)
+
+	source.Cache().AddIndexer(openstackSourceBySecretIndex, indexSourceBySecret[*migration.OpenstackSource])
+	relatedresource.Watch(ctx, "openstack-source-secret-change", resolveSourcesBySecret(source.Cache(), openstackSourceBySecretIndex), source, secret)
 }
 
 func (h *openstackHandler) OnSourceChange(_ string, o *migration.OpenstackSource) (*migration.OpenstackSource, error) {
@@ -37,71 +41,107 @@ func (h *openstackHandler) OnSourceChange(_ string, o *migration.OpenstackSource
 		return nil, nil
 	}
 
+	// Sources are verified periodically to detect expired credentials or
+	// unreachable endpoints before an import fails.
+	secretVersion := sourceSecretVersion(h.secret.Cache(), o)
+	changed := o.Status.ObservedGeneration != o.Generation || o.Status.ObservedSecretVersion != secretVersion
+	if wait := nextSourceVerification(o.Status.Status, o.Status.LastVerifiedTime, changed, time.Now()); wait > 0 {
+		h.source.EnqueueAfter(o.Namespace, o.Name, wait)
+		return o, nil
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"kind":      o.Kind,
 		"name":      o.Name,
 		"namespace": o.Namespace,
 	}).Info("Reconciling source")
 
-	if o.Status.Status != migration.ClusterReady {
-		// process migration logic
-		secretObj, err := h.secret.Get(o.SecretReference().Namespace, o.SecretReference().Name, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to lookup secret for %s migration %s: %w", o.Kind, o.NamespacedName(), err)
+	startTime := time.Now()
+	result := h.verify(o)
+	now := time.Now()
+
+	if result.err != nil {
+		logrus.WithFields(logrus.Fields{
+			"apiVersion": o.APIVersion,
+			"kind":       o.Kind,
+			"name":       o.Name,
+			"namespace":  o.Namespace,
+			"reason":     result.reason,
+			"err":        result.err,
+		}).Error("Failed to verify source for migration")
+	}
+
+	oCopy := o.DeepCopy()
+	oCopy.Status.Conditions, oCopy.Status.Status = sourceVerificationConditions(oCopy.Status.Conditions, result, now)
+	oCopy.Status.ObservedGeneration = o.Generation
+	oCopy.Status.ObservedSecretVersion = secretVersion
+	oCopy.Status.LastVerifiedTime = now.UTC().Format(time.RFC3339)
+	oCopy.Status.VerificationLatency = &metav1.Duration{Duration: now.Sub(startTime).Round(time.Millisecond)}
+	if result.version != "" {
+		oCopy.Status.Version = result.version
+	}
+	if result.networks != nil {
+		oCopy.Status.Networks = result.networks
+	}
+
+	// Successful periodic verifications are not worth an event.
+	if result.err != nil || o.Status.Status != migration.ClusterReady {
+		recordSourceVerification(h.recorder, oCopy, result.err)
+	}
+
+	return h.source.UpdateStatus(oCopy)
+}
+
+// verify checks the connection to the OpenStack cloud and reports the
+// version of the Keystone identity API and the available networks.
+func (h *openstackHandler) verify(o *migration.OpenstackSource) *sourceVerification {
+	secretObj, err := h.secret.Cache().Get(o.SecretReference().Namespace, o.SecretReference().Name)
+	if err != nil {
+		return &sourceVerification{
+			err:    fmt.Errorf("failed to lookup secret for %s migration %s: %w", o.Kind, o.NamespacedName(), err),
+			reason: migration.ReasonCredentialsNotFound,
 		}
+	}
 
-		client, err := openstack.NewClient(h.ctx, o.Spec.EndpointAddress, o.Spec.Region, secretObj, o.GetOptions().(migration.OpenstackSourceOptions))
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate client for %s migration %s: %w", o.Kind, o.NamespacedName(), err)
+	client, err := openstack.NewClient(h.ctx, o.Spec.EndpointAddress, o.Spec.Region, secretObj, o.GetOptions().(migration.OpenstackSourceOptions))
+	if err != nil {
+		return &sourceVerification{
+			err:    fmt.Errorf("failed to generate client for %s migration %s: %w", o.Kind, o.NamespacedName(), err),
+			reason: migration.ReasonConnectionFailed,
 		}
+	}
 
-		err = client.Verify()
-		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"apiVersion": o.APIVersion,
-				"kind":       o.Kind,
-				"name":       o.Name,
-				"namespace":  o.Namespace,
-				"err":        err,
-			}).Error("Failed to verify source for migration")
-
-			conds := []common.Condition{
-				{
-					Type:               migration.ClusterErrorCondition,
-					Status:             corev1.ConditionTrue,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				}, {
-					Type:               migration.ClusterReadyCondition,
-					Status:             corev1.ConditionFalse,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				},
-			}
-
-			o.Status.Conditions = util.MergeConditions(o.Status.Conditions, conds)
-			o.Status.Status = migration.ClusterNotReady
-		} else {
-			conds := []common.Condition{
-				{
-					Type:               migration.ClusterReadyCondition,
-					Status:             corev1.ConditionTrue,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				}, {
-					Type:               migration.ClusterErrorCondition,
-					Status:             corev1.ConditionFalse,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				},
-			}
-
-			o.Status.Conditions = util.MergeConditions(o.Status.Conditions, conds)
-			o.Status.Status = migration.ClusterReady
+	if err := client.Verify(); err != nil {
+		return &sourceVerification{
+			err:    err,
+			reason: migration.ReasonSourceVerificationFailed,
 		}
+	}
+
+	version, err := client.Version()
+	if err != nil {
+		// The version is informational only.
+		logrus.WithFields(logrus.Fields{
+			"kind":      o.Kind,
+			"name":      o.Name,
+			"namespace": o.Namespace,
+			"err":       err,
+		}).Warn("Failed to lookup identity API version")
+	}
 
-		return h.source.UpdateStatus(o)
+	networks, err := client.ListNetworks()
+	if err != nil {
+		// The networks are informational only.
+		logrus.WithFields(logrus.Fields{
+			"kind":      o.Kind,
+			"name":      o.Name,
+			"namespace": o.Namespace,
+			"err":       err,
+		}).Warn("Failed to list networks of the source")
 	}
 
-	return nil, nil
+	return &sourceVerification{
+		version:  version,
+		networks: networks,
+	}
 }
@@ -6,30 +6,35 @@ import (
 	"time"
 
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
-	"github.com/harvester/vm-import-controller/pkg/apis/common"
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
 	"github.com/harvester/vm-import-controller/pkg/source/ova"
-	"github.com/harvester/vm-import-controller/pkg/util"
 )
 
 type ovaHandler struct {
-	ctx    context.Context
-	source migrationController.OvaSourceController
-	secret corecontrollers.SecretController
+	ctx      context.Context
+	source   migrationController.OvaSourceController
+	secret   corecontrollers.SecretController
+	recorder record.EventRecorder
 }
 
-func RegisterOvaController(ctx context.Context, source migrationController.OvaSourceController, secret corecontrollers.SecretController) {
+func RegisterOvaController(ctx context.Context, source migrationController.OvaSourceController, secret corecontrollers.SecretController, recorder record.EventRecorder) {
 	handler := &ovaHandler{
-		ctx:    ctx,
-		source: source,
-		secret: secret,
+		ctx:      ctx,
+		source:   source,
+		secret:   secret,
+		recorder: recorder,
 	}
 	source.OnChange(ctx, "ova-source-change", handler.OnSourceChange)
+
+	source.Cache().AddIndexer(ovaSourceBySecretIndex, indexSourceBySecret[*migration.OvaSource])
+	relatedresource.Watch(ctx, "ova-source-secret-change", resolveSourcesBySecret(source.Cache(), ovaSourceBySecretIndex), source, secret)
 }
 
 func (h *ovaHandler) OnSourceChange(_ string, s *migration.OvaSource) (*migration.OvaSource, error) {
@@ -37,76 +42,80 @@ func (h *ovaHandler) OnSourceChange(_ string, s *migration.OvaSource) (*migratio
 		return nil, nil
 	}
 
+	// Sources are verified periodically to detect expired credentials or
+	// unreachable endpoints before an import fails.
+	secretVersion := sourceSecretVersion(h.secret.Cache(), s)
+	changed := s.Status.ObservedGeneration != s.Generation || s.Status.ObservedSecretVersion != secretVersion
+	if wait := nextSourceVerification(s.Status.Status, s.Status.LastVerifiedTime, changed, time.Now()); wait > 0 {
+		h.source.EnqueueAfter(s.Namespace, s.Name, wait)
+		return s, nil
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"kind":      s.Kind,
 		"name":      s.Name,
 		"namespace": s.Namespace,
 	}).Info("Reconciling source")
 
-	if s.Status.Status != migration.ClusterReady {
-		var secret *corev1.Secret
+	startTime := time.Now()
+	result := h.verify(s)
+	now := time.Now()
+
+	if result.err != nil {
+		logrus.WithFields(logrus.Fields{
+			"apiVersion": s.APIVersion,
+			"kind":       s.Kind,
+			"name":       s.Name,
+			"namespace":  s.Namespace,
+			"reason":     result.reason,
+			"err":        result.err,
+		}).Error("Failed to verify source for migration")
+	}
 
-		if s.HasSecret() {
-			var err error
-			secret, err = h.secret.Get(s.SecretReference().Namespace, s.SecretReference().Name, metav1.GetOptions{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to lookup secret for %s migration %s: %w", s.Kind, s.NamespacedName(), err)
-			}
-		}
+	sCopy := s.DeepCopy()
+	sCopy.Status.Conditions, sCopy.Status.Status = sourceVerificationConditions(sCopy.Status.Conditions, result, now)
+	sCopy.Status.ObservedGeneration = s.Generation
+	sCopy.Status.ObservedSecretVersion = secretVersion
+	sCopy.Status.LastVerifiedTime = now.UTC().Format(time.RFC3339)
+	sCopy.Status.VerificationLatency = &metav1.Duration{Duration: now.Sub(startTime).Round(time.Millisecond)}
 
-		client, err := ova.NewClient(h.ctx, s.Spec.Url, secret, s.GetOptions().(migration.OvaSourceOptions))
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate client for %s migration %s: %w", s.Kind, s.NamespacedName(), err)
-		}
+	// Successful periodic verifications are not worth an event.
+	if result.err != nil || s.Status.Status != migration.ClusterReady {
+		recordSourceVerification(h.recorder, sCopy, result.err)
+	}
 
-		err = client.Verify()
-		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"apiVersion": s.APIVersion,
-				"kind":       s.Kind,
-				"name":       s.Name,
-				"namespace":  s.Namespace,
-				"err":        err,
-			}).Error("Failed to verify source for migration")
-
-			// unable to find specific datacenter
-			conds := []common.Condition{
-				{
-					Type:               migration.ClusterErrorCondition,
-					Status:             corev1.ConditionTrue,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				}, {
-					Type:               migration.ClusterReadyCondition,
-					Status:             corev1.ConditionFalse,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				},
-			}
+	return h.source.UpdateStatus(sCopy)
+}
 
-			s.Status.Conditions = util.MergeConditions(s.Status.Conditions, conds)
-			s.Status.Status = migration.ClusterNotReady
-		} else {
-			conds := []common.Condition{
-				{
-					Type:               migration.ClusterReadyCondition,
-					Status:             corev1.ConditionTrue,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				}, {
-					Type:               migration.ClusterErrorCondition,
-					Status:             corev1.ConditionFalse,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				},
+// verify checks that the OVA file can be downloaded from the configured URL.
+func (h *ovaHandler) verify(s *migration.OvaSource) *sourceVerification {
+	var secret *corev1.Secret
+
+	if s.HasSecret() {
+		var err error
+		secret, err = h.secret.Cache().Get(s.SecretReference().Namespace, s.SecretReference().Name)
+		if err != nil {
+			return &sourceVerification{
+				err:    fmt.Errorf("failed to lookup secret for %s migration %s: %w", s.Kind, s.NamespacedName(), err),
+				reason: migration.ReasonCredentialsNotFound,
 			}
+		}
+	}
 
-			s.Status.Conditions = util.MergeConditions(s.Status.Conditions, conds)
-			s.Status.Status = migration.ClusterReady
+	client, err := ova.NewClient(h.ctx, s.Spec.Url, secret, s.GetOptions().(migration.OvaSourceOptions))
+	if err != nil {
+		return &sourceVerification{
+			err:    fmt.Errorf("failed to generate client for %s migration %s: %w", s.Kind, s.NamespacedName(), err),
+			reason: migration.ReasonConnectionFailed,
 		}
+	}
 
-		return h.source.UpdateStatus(s)
+	if err := client.Verify(); err != nil {
+		return &sourceVerification{
+			err:    err,
+			reason: migration.ReasonSourceVerificationFailed,
+		}
 	}
 
-	return nil, nil
+	return &sourceVerification{}
 }
@@ -0,0 +1,202 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+const (
+	// defaultSourceVerificationInterval is the interval in which ready
+	// sources are verified again.
+	defaultSourceVerificationInterval = 5 * time.Minute
+
+	// sourceRetryInterval is the interval in which sources that are not
+	// ready are verified again.
+	sourceRetryInterval = 30 * time.Second
+)
+
+// Names of the cache indexes that map secrets to the sources using them.
+const (
+	vmwareSourceBySecretIndex    = "migration.harvesterhci.io/vmwaresource-by-secret"
+	ovaSourceBySecretIndex       = "migration.harvesterhci.io/ovasource-by-secret"
+	openstackSourceBySecretIndex = "migration.harvesterhci.io/openstacksource-by-secret"
+
+	// virtualMachineImportBySourceIndex maps sources to the imports using
+	// them.
+	virtualMachineImportBySourceIndex = "migration.harvesterhci.io/virtualmachineimport-by-source"
+)
+
+// sourceObject is implemented by all source types.
+type sourceObject interface {
+	runtime.Object
+	metav1.Object
+	migration.SourceInterface
+}
+
+// sourceVerification contains the result of the verification of a source.
+type sourceVerification struct {
+	err    error
+	reason string
+	// The version of the source API. Empty if not supported by the source.
+	version string
+	// The networks available in the source. Nil if not supported by the
+	// source or if the networks could not be listed.
+	networks []migration.SourceNetwork
+}
+
+// sourceVerificationInterval returns the interval in which ready sources are
+// verified periodically. It can be set with the env variable
+// SOURCE_VERIFICATION_INTERVAL, e.g. `10m`.
+func sourceVerificationInterval() time.Duration {
+	if val := os.Getenv("SOURCE_VERIFICATION_INTERVAL"); val != "" {
+		interval, err := time.ParseDuration(val)
+		if err == nil && interval > 0 {
+			return interval
+		}
+		logrus.Warnf("Ignoring invalid source verification interval %q", val)
+	}
+	return defaultSourceVerificationInterval
+}
+
+// nextSourceVerification returns the time to wait until the source has to be
+// verified again. A duration of zero means that the verification is due,
+// which is always the case if the spec or the credentials of the source have
+// been changed. Each verification records its time in the status, so the
+// status of an unchanged source is written at most once per interval.
+func nextSourceVerification(status migration.ClusterStatus, lastVerifiedTime string, changed bool, now time.Time) time.Duration {
+	if lastVerifiedTime == "" || changed {
+		return 0
+	}
+
+	lastVerified, err := time.Parse(time.RFC3339, lastVerifiedTime)
+	if err != nil {
+		return 0
+	}
+
+	interval := sourceVerificationInterval()
+	if status != migration.ClusterReady {
+		interval = min(interval, sourceRetryInterval)
+	}
+
+	if wait := lastVerified.Add(interval).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// sourceVerificationConditions merges the result of a verification into the
+// given conditions of a source and returns the resulting status.
+func sourceVerificationConditions(conditions []common.Condition, result *sourceVerification, now time.Time) ([]common.Condition, migration.ClusterStatus) {
+	readyStatus, errorStatus := corev1.ConditionTrue, corev1.ConditionFalse
+	clusterStatus := migration.ClusterReady
+	reason, message := migration.ReasonSourceVerified, ""
+	if result.err != nil {
+		readyStatus, errorStatus = corev1.ConditionFalse, corev1.ConditionTrue
+		clusterStatus = migration.ClusterNotReady
+		reason, message = result.reason, result.err.Error()
+	}
+
+	conds := []common.Condition{
+		newSourceCondition(conditions, migration.ClusterReadyCondition, readyStatus, reason, message, now),
+		newSourceCondition(conditions, migration.ClusterErrorCondition, errorStatus, reason, message, now),
+	}
+
+	return util.MergeConditions(conditions, conds), clusterStatus
+}
+
+// newSourceCondition returns a condition of the given type. The transition
+// time is kept if the status of the condition has not changed.
+func newSourceCondition(conditions []common.Condition, c condition.Cond, status corev1.ConditionStatus, reason, message string, now time.Time) common.Condition {
+	nowStr := now.UTC().Format(time.RFC3339)
+	transitionTime := nowStr
+	if existing := util.GetCondition(conditions, c, status); existing != nil && existing.LastTransitionTime != "" {
+		transitionTime = existing.LastTransitionTime
+	}
+
+	return common.Condition{
+		Type:               c,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     nowStr,
+		LastTransitionTime: transitionTime,
+	}
+}
+
+// sourceSecretVersion returns the resource version of the secret that
+// contains the credentials of the given source. It is empty if the source
+// has no secret or the secret does not exist.
+func sourceSecretVersion(secretCache ctlcorev1.SecretCache, source migration.SourceInterface) string {
+	ref := source.SecretReference()
+	if !source.HasSecret() || ref == nil {
+		return ""
+	}
+
+	secret, err := secretCache.Get(ref.Namespace, ref.Name)
+	if err != nil {
+		return ""
+	}
+	return secret.ResourceVersion
+}
+
+// secretIndexKey returns the key of the given secret in the source indexes.
+func secretIndexKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// indexSourceBySecret indexes a source by the secret that contains its
+// credentials.
+func indexSourceBySecret[T migration.SourceInterface](source T) ([]string, error) {
+	ref := source.SecretReference()
+	if !source.HasSecret() || ref == nil {
+		return nil, nil
+	}
+	return []string{secretIndexKey(ref.Namespace, ref.Name)}, nil
+}
+
+// resolveSourcesBySecret returns a resolver that enqueues the sources which
+// reference a changed secret, so that rotated credentials are verified
+// immediately.
+func resolveSourcesBySecret[T sourceObject](cache generic.CacheInterface[T], indexName string) relatedresource.Resolver {
+	return func(namespace, name string, _ runtime.Object) ([]relatedresource.Key, error) {
+		sources, err := cache.GetByIndex(indexName, secretIndexKey(namespace, name))
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]relatedresource.Key, 0, len(sources))
+		for _, s := range sources {
+			keys = append(keys, relatedresource.Key{
+				Namespace: s.GetNamespace(),
+				Name:      s.GetName(),
+			})
+		}
+		return keys, nil
+	}
+}
+
+// sourceIndexKey returns the key of the given source in the import index.
+func sourceIndexKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), namespace, name)
+}
+
+// indexVirtualMachineImportBySource indexes an import by its source.
+func indexVirtualMachineImportBySource(vm *migration.VirtualMachineImport) ([]string, error) {
+	ref := vm.Spec.SourceCluster
+	return []string{sourceIndexKey(ref.Kind, ref.Namespace, ref.Name)}, nil
+}
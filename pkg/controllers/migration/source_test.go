@@ -0,0 +1,298 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_nextSourceVerification(t *testing.T) {
+	assert := require.New(t)
+	now := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc             string
+		status           migration.ClusterStatus
+		lastVerifiedTime string
+		changed          bool
+		expected         time.Duration
+	}{
+		{
+			desc:     "Never verified",
+			status:   migration.ClusterReady,
+			expected: 0,
+		},
+		{
+			desc:             "Ready source within the interval",
+			status:           migration.ClusterReady,
+			lastVerifiedTime: "2025-03-15T21:58:00Z",
+			expected:         3 * time.Minute,
+		},
+		{
+			desc:             "Ready source after the interval",
+			status:           migration.ClusterReady,
+			lastVerifiedTime: "2025-03-15T21:50:00Z",
+			expected:         0,
+		},
+		{
+			desc:             "Source not ready is retried sooner",
+			status:           migration.ClusterNotReady,
+			lastVerifiedTime: "2025-03-15T21:59:50Z",
+			expected:         20 * time.Second,
+		},
+		{
+			desc:             "Changed source is verified immediately",
+			status:           migration.ClusterReady,
+			lastVerifiedTime: "2025-03-15T21:59:00Z",
+			changed:          true,
+			expected:         0,
+		},
+		{
+			desc:             "Invalid time is verified immediately",
+			status:           migration.ClusterReady,
+			lastVerifiedTime: "yesterday",
+			expected:         0,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, nextSourceVerification(tc.status, tc.lastVerifiedTime, tc.changed, now), tc.desc)
+	}
+}
+
+func Test_nextSourceVerification_interval(t *testing.T) {
+	assert := require.New(t)
+	now := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+
+	t.Setenv("SOURCE_VERIFICATION_INTERVAL", "10m")
+	assert.Equal(9*time.Minute, nextSourceVerification(migration.ClusterReady, "2025-03-15T21:59:00Z", false, now))
+
+	t.Setenv("SOURCE_VERIFICATION_INTERVAL", "invalid")
+	assert.Equal(4*time.Minute, nextSourceVerification(migration.ClusterReady, "2025-03-15T21:59:00Z", false, now))
+}
+
+func Test_sourceVerificationConditions(t *testing.T) {
+	assert := require.New(t)
+	now := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	readyConditions := []common.Condition{
+		{
+			Type:               migration.ClusterReadyCondition,
+			Status:             corev1.ConditionTrue,
+			Reason:             migration.ReasonSourceVerified,
+			LastUpdateTime:     "2025-03-15T21:55:00Z",
+			LastTransitionTime: "2025-03-15T20:00:00Z",
+		},
+		{
+			Type:               migration.ClusterErrorCondition,
+			Status:             corev1.ConditionFalse,
+			Reason:             migration.ReasonSourceVerified,
+			LastUpdateTime:     "2025-03-15T21:55:00Z",
+			LastTransitionTime: "2025-03-15T20:00:00Z",
+		},
+	}
+	testCases := []struct {
+		desc               string
+		conditions         []common.Condition
+		result             *sourceVerification
+		expectedStatus     migration.ClusterStatus
+		expectedReady      common.Condition
+		expectedConditions int
+	}{
+		{
+			desc:           "First successful verification",
+			result:         &sourceVerification{},
+			expectedStatus: migration.ClusterReady,
+			expectedReady: common.Condition{
+				Type:               migration.ClusterReadyCondition,
+				Status:             corev1.ConditionTrue,
+				Reason:             migration.ReasonSourceVerified,
+				LastUpdateTime:     "2025-03-15T22:00:00Z",
+				LastTransitionTime: "2025-03-15T22:00:00Z",
+			},
+			expectedConditions: 2,
+		},
+		{
+			desc:           "Periodic successful verification keeps the transition time",
+			conditions:     readyConditions,
+			result:         &sourceVerification{},
+			expectedStatus: migration.ClusterReady,
+			expectedReady: common.Condition{
+				Type:               migration.ClusterReadyCondition,
+				Status:             corev1.ConditionTrue,
+				Reason:             migration.ReasonSourceVerified,
+				LastUpdateTime:     "2025-03-15T22:00:00Z",
+				LastTransitionTime: "2025-03-15T20:00:00Z",
+			},
+			expectedConditions: 2,
+		},
+		{
+			desc:       "Failed verification of a ready source",
+			conditions: readyConditions,
+			result: &sourceVerification{
+				err:    errors.New("invalid credentials"),
+				reason: migration.ReasonSourceVerificationFailed,
+			},
+			expectedStatus: migration.ClusterNotReady,
+			expectedReady: common.Condition{
+				Type:               migration.ClusterReadyCondition,
+				Status:             corev1.ConditionFalse,
+				Reason:             migration.ReasonSourceVerificationFailed,
+				Message:            "invalid credentials",
+				LastUpdateTime:     "2025-03-15T22:00:00Z",
+				LastTransitionTime: "2025-03-15T22:00:00Z",
+			},
+			expectedConditions: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		// The conditions are updated in place, so each case works on a copy.
+		conditions := append([]common.Condition(nil), tc.conditions...)
+
+		result, status := sourceVerificationConditions(conditions, tc.result, now)
+		assert.Equal(tc.expectedStatus, status, tc.desc)
+		assert.Len(result, tc.expectedConditions, tc.desc)
+		assert.Equal(tc.expectedReady, result[0], tc.desc)
+		assert.Equal(migration.ClusterErrorCondition, result[1].Type, tc.desc)
+		assert.Equal(tc.expectedReady.Reason, result[1].Reason, tc.desc)
+	}
+}
+
+// indexerCache is a cache backed by a client-go indexer, so that the cache
+// indexes of the controllers can be tested without an API server.
+type indexerCache[T runtime.Object] struct {
+	indexer cache.Indexer
+}
+
+func newIndexerCache[T runtime.Object]() *indexerCache[T] {
+	return &indexerCache[T]{indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})}
+}
+
+func (c *indexerCache[T]) Get(namespace, name string) (T, error) {
+	var result T
+	obj, exists, err := c.indexer.GetByKey(namespace + "/" + name)
+	if err != nil {
+		return result, err
+	}
+	if !exists {
+		return result, apierrors.NewNotFound(schema.GroupResource{}, name)
+	}
+	return obj.(T), nil
+}
+
+func (c *indexerCache[T]) List(namespace string, selector labels.Selector) ([]T, error) {
+	var result []T
+	err := cache.ListAllByNamespace(c.indexer, namespace, selector, func(obj interface{}) {
+		result = append(result, obj.(T))
+	})
+	return result, err
+}
+
+func (c *indexerCache[T]) AddIndexer(indexName string, indexer generic.Indexer[T]) {
+	_ = c.indexer.AddIndexers(cache.Indexers{
+		indexName: func(obj interface{}) ([]string, error) {
+			return indexer(obj.(T))
+		},
+	})
+}
+
+func (c *indexerCache[T]) GetByIndex(indexName, key string) ([]T, error) {
+	objs, err := c.indexer.ByIndex(indexName, key)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.(T))
+	}
+	return result, nil
+}
+
+func Test_resolveSourcesBySecret(t *testing.T) {
+	assert := require.New(t)
+
+	sources := newIndexerCache[*migration.OvaSource]()
+	sources.AddIndexer(ovaSourceBySecretIndex, indexSourceBySecret[*migration.OvaSource])
+	for _, s := range []*migration.OvaSource{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ova-a", Namespace: "default"},
+			Spec: migration.OvaSourceSpec{
+				Credentials: &corev1.SecretReference{Name: "credentials", Namespace: "default"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ova-b", Namespace: "default"},
+			Spec: migration.OvaSourceSpec{
+				Credentials: &corev1.SecretReference{Name: "other", Namespace: "default"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ova-c", Namespace: "team"},
+			Spec: migration.OvaSourceSpec{
+				Credentials: &corev1.SecretReference{Name: "credentials", Namespace: "default"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ova-d", Namespace: "default"},
+		},
+	} {
+		assert.NoError(sources.indexer.Add(s))
+	}
+
+	resolve := resolveSourcesBySecret(sources, ovaSourceBySecretIndex)
+	testCases := []struct {
+		desc      string
+		namespace string
+		name      string
+		expected  []relatedresource.Key
+	}{
+		{
+			desc:      "Secret shared by sources of different namespaces",
+			namespace: "default",
+			name:      "credentials",
+			expected: []relatedresource.Key{
+				{Namespace: "default", Name: "ova-a"},
+				{Namespace: "team", Name: "ova-c"},
+			},
+		},
+		{
+			desc:      "Secret of a single source",
+			namespace: "default",
+			name:      "other",
+			expected: []relatedresource.Key{
+				{Namespace: "default", Name: "ova-b"},
+			},
+		},
+		{
+			desc:      "Secret with the same name in another namespace",
+			namespace: "team",
+			name:      "credentials",
+			expected:  []relatedresource.Key{},
+		},
+		{
+			desc:      "Secret that is not used by any source",
+			namespace: "default",
+			name:      "unused",
+			expected:  []relatedresource.Key{},
+		},
+	}
+
+	for _, tc := range testCases {
+		keys, err := resolve(tc.namespace, tc.name, &corev1.Secret{})
+		assert.NoError(err, tc.desc)
+		assert.ElementsMatch(tc.expected, keys, tc.desc)
+	}
+}
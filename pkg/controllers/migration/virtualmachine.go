@@ -3,6 +3,7 @@ package migration
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/harvester/vm-import-controller/pkg/apis/common"
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/metrics"
 	"github.com/harvester/vm-import-controller/pkg/server"
+	"github.com/harvester/vm-import-controller/pkg/source"
 	"github.com/harvester/vm-import-controller/pkg/source/openstack"
 	"github.com/harvester/vm-import-controller/pkg/source/ova"
 	"github.com/harvester/vm-import-controller/pkg/source/vmware"
@@ -22,6 +25,7 @@ import (
 	harvester "github.com/harvester/harvester/pkg/generated/controllers/harvesterhci.io/v1beta1"
 	ctlcniv1 "github.com/harvester/harvester/pkg/generated/controllers/k8s.cni.cncf.io/v1"
 	kubevirtv1 "github.com/harvester/harvester/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/harvester/harvester/pkg/settings"
 	coreControllers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"github.com/sirupsen/logrus"
@@ -32,6 +36,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	kubevirt "kubevirt.io/api/core/v1"
 
 	storageControllers "github.com/rancher/wrangler/v3/pkg/generated/controllers/storage/v1"
@@ -42,6 +48,10 @@ const (
 	labelImported          = "migration.harvesterhci.io/imported"
 	labelImageDisplayName  = "harvesterhci.io/imageDisplayName"
 	expectedAPIVersion     = "migration.harvesterhci.io/v1beta1"
+
+	// featureGateDeclarativeHotplugVolumes is the KubeVirt feature gate that
+	// allows CD-ROM drives without a volume.
+	featureGateDeclarativeHotplugVolumes = "DeclarativeHotplugVolumes"
 )
 
 type VirtualMachineOperations interface {
@@ -69,6 +79,15 @@ type VirtualMachineOperations interface {
 	// PreFlightChecks checks the cluster-specific configurations.
 	PreFlightChecks(vm *migration.VirtualMachineImport) error
 
+	// GetNetworkInfos returns the network interfaces of the source VM.
+	GetNetworkInfos(vm *migration.VirtualMachineImport) ([]source.NetworkInfo, error)
+
+	// GetDiskInfos returns the disks of the source VM.
+	GetDiskInfos(vm *migration.VirtualMachineImport) ([]migration.DiskInfo, error)
+
+	// GetVirtualMachineIdentity returns the identifiers of the source VM.
+	GetVirtualMachineIdentity(vm *migration.VirtualMachineImport) (*migration.SourceVirtualMachineIdentity, error)
+
 	// Cleanup is responsible for cleaning up any temporary data.
 	Cleanup(vm *migration.VirtualMachineImport) error
 }
@@ -82,12 +101,16 @@ type virtualMachineHandler struct {
 	importVM  migrationController.VirtualMachineImportController
 	vmi       harvester.VirtualMachineImageController
 	kubevirt  kubevirtv1.VirtualMachineController
+	kvConfig  kubevirtv1.KubeVirtCache
 	pvc       coreControllers.PersistentVolumeClaimController
 	sc        storageControllers.StorageClassCache
+	setting   harvester.SettingCache
+	nad       ctlcniv1.NetworkAttachmentDefinitionController
 	nadCache  ctlcniv1.NetworkAttachmentDefinitionCache
+	recorder  record.EventRecorder
 }
 
-func RegisterVMImportController(ctx context.Context, vmware migrationController.VmwareSourceController, openstack migrationController.OpenstackSourceController, ova migrationController.OvaSourceController, secret coreControllers.SecretController, importVM migrationController.VirtualMachineImportController, vmi harvester.VirtualMachineImageController, kubevirt kubevirtv1.VirtualMachineController, pvc coreControllers.PersistentVolumeClaimController, scCache storageControllers.StorageClassCache, nadCache ctlcniv1.NetworkAttachmentDefinitionCache) {
+func RegisterVMImportController(ctx context.Context, vmware migrationController.VmwareSourceController, openstack migrationController.OpenstackSourceController, ova migrationController.OvaSourceController, secret coreControllers.SecretController, importVM migrationController.VirtualMachineImportController, vmi harvester.VirtualMachineImageController, kubevirt kubevirtv1.VirtualMachineController, kvConfig kubevirtv1.KubeVirtCache, pvc coreControllers.PersistentVolumeClaimController, scCache storageControllers.StorageClassCache, settingCache harvester.SettingCache, nad ctlcniv1.NetworkAttachmentDefinitionController, recorder record.EventRecorder) {
 	vmHandler := &virtualMachineHandler{
 		ctx:       ctx,
 		vmware:    vmware,
@@ -97,13 +120,20 @@ func RegisterVMImportController(ctx context.Context, vmware migrationController.
 		importVM:  importVM,
 		vmi:       vmi,
 		kubevirt:  kubevirt,
+		kvConfig:  kvConfig,
 		pvc:       pvc,
 		sc:        scCache,
-		nadCache:  nadCache,
+		setting:   settingCache,
+		nad:       nad,
+		nadCache:  nad.Cache(),
+		recorder:  recorder,
 	}
 
 	relatedresource.Watch(ctx, "virtualmachineimage-change", vmHandler.ReconcileVMI, importVM, vmi)
 
+	importVM.Cache().AddIndexer(virtualMachineImportBySourceIndex, indexVirtualMachineImportBySource)
+	relatedresource.Watch(ctx, "virtualmachineimport-secret-change", vmHandler.ReconcileSecret, importVM, secret)
+
 	importVM.OnChange(ctx, vmImportControllerName, vmHandler.OnVirtualMachineChange)
 	importVM.OnRemove(ctx, vmImportControllerName, vmHandler.OnVirtualMachineRemove)
 }
@@ -113,12 +143,39 @@ func (h *virtualMachineHandler) OnVirtualMachineChange(_ string, vmi *migration.
 		return nil, nil
 	}
 
+	newVMI, err := h.reconcileVirtualMachineImport(vmi.DeepCopy())
+	if newVMI != nil {
+		metrics.ObserveImport(newVMI)
+	} else {
+		metrics.ObserveImport(vmi)
+	}
+	if err != nil {
+		recordReconcileFailure(h.recorder, newVMI, vmi.Status.Status, err)
+		return newVMI, err
+	}
+
+	recordImportStatusTransition(h.recorder, newVMI, vmi.Status.Status)
+
+	return newVMI, nil
+}
+
+// updateStatus updates the status of the given `VirtualMachineImport`. The
+// time spent in each phase and the progress are kept track of with the same
+// update.
+func (h *virtualMachineHandler) updateStatus(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
+	util.RecordPhaseTransition(vm, time.Now())
+	util.UpdateImportProgress(vm)
+	return h.importVM.UpdateStatus(vm)
+}
+
+// reconcileVirtualMachineImport drives the given `VirtualMachineImport`
+// object through the import phases.
+func (h *virtualMachineHandler) reconcileVirtualMachineImport(vmiCopy *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
 	logrusEntry := logrus.WithFields(logrus.Fields{
-		"name":                    vmi.Name,
-		"namespace":               vmi.Namespace,
-		"spec.virtualMachineName": vmi.Spec.VirtualMachineName,
+		"name":                    vmiCopy.Name,
+		"namespace":               vmiCopy.Namespace,
+		"spec.virtualMachineName": vmiCopy.Spec.VirtualMachineName,
 	})
-	vmiCopy := vmi.DeepCopy()
 
 	switch vmiCopy.Status.Status {
 	case "":
@@ -132,6 +189,10 @@ func (h *virtualMachineHandler) OnVirtualMachineChange(_ string, vmi *migration.
 		// vmiCopy migration is valid and ready. trigger migration specific import
 		logrusEntry.Info("Importing client disk images ...")
 		return h.abortMigrationIfNecessary(h.runVirtualMachineExport(vmiCopy))
+	case migration.VirtualMachineImportScheduled:
+		// wait for the schedule window to open before the source VM is touched
+		logrusEntry.Info("Waiting for the import schedule window ...")
+		return h.reconcileSchedule(vmiCopy)
 	case migration.DisksExported:
 		// prepare and add routes for disks to be used for VirtualMachineImage CRD
 		logrusEntry.Info("Creating VM images ...")
@@ -152,7 +213,7 @@ func (h *virtualMachineHandler) OnVirtualMachineChange(_ string, vmi *migration.
 
 		vmiCopy.Status.Status = *newStatus
 
-		return h.importVM.UpdateStatus(vmiCopy)
+		return h.updateStatus(vmiCopy)
 	case migration.DiskImagesFailed:
 		logrusEntry.Error("Failed to import client disk images. Try again ...")
 		return h.triggerResubmit(vmiCopy)
@@ -167,7 +228,7 @@ func (h *virtualMachineHandler) OnVirtualMachineChange(_ string, vmi *migration.
 
 		vmiCopy.Status.Status = migration.VirtualMachineCreated
 
-		return h.importVM.UpdateStatus(vmiCopy)
+		return h.updateStatus(vmiCopy)
 	case migration.VirtualMachineCreated:
 		// wait for VM to be running using a watch on VM's
 		logrusEntry.Info("Checking VM instances ...")
@@ -220,6 +281,8 @@ func (h *virtualMachineHandler) OnVirtualMachineRemove(_ string, vmi *migration.
 		"spec.sourceCluster.name": vmi.Spec.SourceCluster.Name,
 	})
 
+	metrics.ForgetImport(vmi)
+
 	logrusEntry.Info("Cleaning up temporary data ...")
 
 	err := h.triggerCleanup(vmi)
@@ -234,7 +297,7 @@ func (h *virtualMachineHandler) OnVirtualMachineRemove(_ string, vmi *migration.
 // preFlightChecks is used to validate that the associate sources and VM migration references are valid
 func (h *virtualMachineHandler) preFlightChecks(vm *migration.VirtualMachineImport) error {
 	if vm.Spec.SourceCluster.APIVersion != expectedAPIVersion {
-		return fmt.Errorf("expected migration cluster apiversion to be '%s' but got '%s'", expectedAPIVersion, vm.Spec.SourceCluster.APIVersion)
+		return fmt.Errorf("%w: expected migration cluster apiversion to be '%s' but got '%s'", util.ErrInvalidSourceCluster, expectedAPIVersion, vm.Spec.SourceCluster.APIVersion)
 	}
 
 	var ss migration.SourceInterface
@@ -244,10 +307,10 @@ func (h *virtualMachineHandler) preFlightChecks(vm *migration.VirtualMachineImpo
 	case migration.KindVmwareSource, migration.KindOvaSource, migration.KindOpenstackSource:
 		ss, err = h.generateSource(vm)
 		if err != nil {
-			return fmt.Errorf("error generating migration in preflight checks: %v", err)
+			return fmt.Errorf("error generating migration in preflight checks: %w", err)
 		}
 	default:
-		return fmt.Errorf("unsupported source kind %q", vm.Spec.SourceCluster.Kind)
+		return fmt.Errorf("%w: unsupported source kind %q", util.ErrInvalidSourceCluster, vm.Spec.SourceCluster.Kind)
 	}
 
 	if ss.ClusterStatus() != migration.ClusterReady {
@@ -263,53 +326,80 @@ func (h *virtualMachineHandler) preFlightChecks(vm *migration.VirtualMachineImpo
 	if vm.Spec.StorageClass != "" {
 		_, err := util.GetStorageClassByName(vm.Spec.StorageClass, h.sc)
 		if err != nil {
+			return fmt.Errorf("%w: '%s': %v", util.ErrStorageClassNotFound, vm.Spec.StorageClass, err)
+		}
+	}
+
+	// verify the disk mappings and the storage classes they refer to.
+	for _, dm := range vm.Spec.DiskMapping {
+		if err := util.ValidateDiskMapping(dm); err != nil {
+			return err
+		}
+		if dm.StorageClass != "" {
+			_, err := util.GetStorageClassByName(dm.StorageClass, h.sc)
+			if err != nil {
+				return fmt.Errorf("%w: '%s': %v", util.ErrStorageClassNotFound, dm.StorageClass, err)
+			}
+		}
+	}
+
+	for _, ds := range slices.Concat(vm.Spec.IncludeDisks, vm.Spec.ExcludeDisks) {
+		if err := util.ValidateDiskSelector(ds); err != nil {
 			return err
 		}
 	}
 
+	// verify the storage classes of the storage class mapping of the source.
+	// The mapping is recorded, so that later changes of the source do not
+	// affect the import.
+	for _, m := range ss.GetStorageClassMapping() {
+		_, err := util.GetStorageClassByName(m.StorageClass, h.sc)
+		if err != nil {
+			return fmt.Errorf("%w: '%s': %v", util.ErrStorageClassNotFound, m.StorageClass, err)
+		}
+	}
+	vm.Status.StorageClassMapping = slices.Clone(ss.GetStorageClassMapping())
+
 	// dedup source network names as the same source network name cannot appear twice
 	sourceNetworkMap := make(map[string]bool)
 	for _, network := range vm.Spec.Mapping {
+		if err := util.ValidateSourceNetworkPattern(network); err != nil {
+			return err
+		}
 		_, ok := sourceNetworkMap[network.SourceNetwork]
 		if !ok {
 			sourceNetworkMap[network.SourceNetwork] = true
 			continue
 		}
-		return fmt.Errorf("source network %s appears multiple times in VirtualMachineImport spec", network.SourceNetwork)
+		return fmt.Errorf("%w: source network %s appears multiple times in VirtualMachineImport spec", util.ErrInvalidNetworkMapping, network.SourceNetwork)
 	}
 
 	// Validate the destination network configuration.
 	for _, nm := range vm.Spec.Mapping {
-		// The destination network supports the following format:
-		// - <networkName>
-		// - <namespace>/<networkName>
-		// See `MultusNetwork.NetworkName` for more details.
-		parts := strings.Split(nm.DestinationNetwork, "/")
-		switch len(parts) {
-		case 1:
-			// If namespace is not specified, `VirtualMachineImport` namespace is assumed.
-			parts = append([]string{vm.Namespace}, parts[0])
-			fallthrough
-		case 2:
-			_, err := h.nadCache.Get(parts[0], parts[1])
-			if err != nil {
-				logrus.WithFields(logrus.Fields{
-					"name":                    vm.Name,
-					"namespace":               vm.Namespace,
-					"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
-					"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
-				}).Errorf("Failed to get destination network '%s/%s': %v",
-					parts[0], parts[1], err)
-				return err
-			}
-		default:
-			logrus.WithFields(logrus.Fields{
-				"name":                    vm.Name,
-				"namespace":               vm.Namespace,
-				"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
-				"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
-			}).Errorf("Invalid destination network '%s'", nm.DestinationNetwork)
-			return fmt.Errorf("invalid destination network '%s'", nm.DestinationNetwork)
+		if err := h.checkDestinationNetwork(vm, nm.DestinationNetwork); err != nil {
+			return err
+		}
+	}
+	if vm.Spec.DefaultDestinationNetwork != "" {
+		if err := h.checkDestinationNetwork(vm, vm.Spec.DefaultDestinationNetwork); err != nil {
+			return err
+		}
+	}
+
+	// Make sure the schedule can be evaluated later on.
+	if err := util.ValidateImportSchedule(vm.Spec.Schedule); err != nil {
+		return err
+	}
+
+	// Empty CD-ROM drives can only be recreated if KubeVirt allows disks
+	// without a volume.
+	if vm.GetCDROMImport() == migration.CDROMImportEmpty {
+		enabled, err := h.declarativeHotplugVolumesEnabled()
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return fmt.Errorf("%w: %s is required to recreate CD-ROM drives without media", util.ErrFeatureGateDisabled, featureGateDeclarativeHotplugVolumes)
 		}
 	}
 
@@ -320,6 +410,40 @@ func (h *virtualMachineHandler) preFlightChecks(vm *migration.VirtualMachineImpo
 		return fmt.Errorf("error generating VMO in preFlightChecks: %w", err)
 	}
 
+	// Make sure the source VM can still boot with the selected disks.
+	if err := checkBootDisk(vm, vmo); err != nil {
+		return err
+	}
+
+	// Map the network interfaces of the source VM. The networks for the
+	// unmapped source networks are only created once all checks have
+	// passed, so that a failed import does not leave them behind.
+	vlanNetworks, err := h.mapNetworkInterfaces(vm, ss, vmo)
+	if err != nil {
+		return err
+	}
+
+	// Record the time of the import and the identity of the source VM for
+	// the provenance annotations of the imported objects. The provenance is
+	// informational only, so the import continues if the identity cannot be
+	// determined.
+	if vm.Status.ImportTime == "" {
+		vm.Status.ImportTime = time.Now().UTC().Format(time.RFC3339)
+	}
+	if vm.Status.SourceVirtualMachine == nil {
+		identity, err := vmo.GetVirtualMachineIdentity(vm)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"name":                    vm.Name,
+				"namespace":               vm.Namespace,
+				"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
+				"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
+			}).Warnf("Failed to get the identity of the source VM: %v", err)
+		} else {
+			vm.Status.SourceVirtualMachine = identity
+		}
+	}
+
 	if vm.SkipPreflightChecks() {
 		logrus.WithFields(logrus.Fields{
 			"name":                    vm.Name,
@@ -334,9 +458,69 @@ func (h *virtualMachineHandler) preFlightChecks(vm *migration.VirtualMachineImpo
 		}
 	}
 
+	return h.createVlanNetworks(vm, vlanNetworks)
+}
+
+// checkDestinationNetwork verifies that the given destination network of
+// the import exists.
+func (h *virtualMachineHandler) checkDestinationNetwork(vm *migration.VirtualMachineImport, network string) error {
+	// The destination network supports the following format:
+	// - <networkName>
+	// - <namespace>/<networkName>
+	// See `MultusNetwork.NetworkName` for more details.
+	parts := strings.Split(network, "/")
+	switch len(parts) {
+	case 1:
+		// If namespace is not specified, `VirtualMachineImport` namespace is assumed.
+		parts = append([]string{vm.Namespace}, parts[0])
+		fallthrough
+	case 2:
+		_, err := h.nadCache.Get(parts[0], parts[1])
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"name":                    vm.Name,
+				"namespace":               vm.Namespace,
+				"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
+				"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
+			}).Errorf("Failed to get destination network '%s/%s': %v",
+				parts[0], parts[1], err)
+			return fmt.Errorf("%w: '%s/%s': %v", util.ErrDestinationNetworkNotFound, parts[0], parts[1], err)
+		}
+	default:
+		logrus.WithFields(logrus.Fields{
+			"name":                    vm.Name,
+			"namespace":               vm.Namespace,
+			"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
+			"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
+		}).Errorf("Invalid destination network '%s'", network)
+		return fmt.Errorf("%w: invalid destination network '%s'", util.ErrInvalidNetworkMapping, network)
+	}
+
 	return nil
 }
 
+// declarativeHotplugVolumesEnabled checks whether the KubeVirt feature gate
+// DeclarativeHotplugVolumes is enabled.
+func (h *virtualMachineHandler) declarativeHotplugVolumesEnabled() (bool, error) {
+	kvs, err := h.kvConfig.List("", labels.Everything())
+	if err != nil {
+		return false, fmt.Errorf("error listing KubeVirt configurations: %w", err)
+	}
+	return featureGateEnabled(kvs, featureGateDeclarativeHotplugVolumes), nil
+}
+
+// featureGateEnabled checks whether the given feature gate is enabled in
+// any of the given KubeVirt configurations.
+func featureGateEnabled(kvs []*kubevirt.KubeVirt, gate string) bool {
+	for _, kv := range kvs {
+		dc := kv.Spec.Configuration.DeveloperConfiguration
+		if dc != nil && slices.Contains(dc.FeatureGates, gate) {
+			return true
+		}
+	}
+	return false
+}
+
 // triggerShutdownGuest triggers the shutdown of the guest OS of the source VM.
 func triggerShutdownGuest(vm *migration.VirtualMachineImport, vmo VirtualMachineOperations) error {
 	logrus.WithFields(logrus.Fields{
@@ -394,6 +578,20 @@ func (h *virtualMachineHandler) triggerExport(vm *migration.VirtualMachineImport
 		return fmt.Errorf("error generating VMO in triggerExport: %w", err)
 	}
 
+	// Do not start to power off the source VM outside the schedule window.
+	// Once the shutdown has been triggered, the import continues even if
+	// the window closes in the meantime.
+	if !util.ConditionExists(vm.Status.ImportConditions, migration.VirtualMachinePoweringOff, corev1.ConditionTrue) &&
+		!util.ConditionExists(vm.Status.ImportConditions, migration.VirtualMachineShutdownGuest, corev1.ConditionTrue) {
+		open, _, err := util.EvaluateImportSchedule(vm.Spec.Schedule, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to evaluate import schedule: %w", err)
+		}
+		if !open {
+			return util.ErrScheduleWindowClosed
+		}
+	}
+
 	// Trigger power off or shutdown guest OS of the source VM.
 	if vmo.IsPowerOffSupported() && vm.GetForcePowerOff() {
 		if !util.ConditionExists(vm.Status.ImportConditions, migration.VirtualMachinePoweringOff, corev1.ConditionTrue) {
@@ -502,8 +700,13 @@ func (h *virtualMachineHandler) triggerExport(vm *migration.VirtualMachineImport
 				"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
 				"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
 			}).Errorf("Failed to export source VM: %v", err)
+			h.recorder.Eventf(vm, corev1.EventTypeWarning, eventReasonExportFailed, "Failed to export source VM: %v", err)
 			return nil
 		}
+		// Resolve the storage class of each exported disk once.
+		for i, d := range vm.Status.DiskImportStatus {
+			vm.Status.DiskImportStatus[i].StorageClass = util.GetDiskStorageClass(vm, d, vm.Status.StorageClassMapping)
+		}
 		conds := []common.Condition{
 			{
 				Type:               migration.VirtualMachineExported,
@@ -661,12 +864,85 @@ func (h *virtualMachineHandler) createVirtualMachine(vm *migration.VirtualMachin
 		return fmt.Errorf("error generating Kubevirt VM: %v", err)
 	}
 
+	if err := h.applyResourcePolicy(vm, runVM); err != nil {
+		return err
+	}
+
 	// create PVC claims from VMI's to create the Kubevirt VM
 	err = h.findAndCreatePVC(vm)
 	if err != nil {
 		return err
 	}
 
+	// CD-ROM drives without media can only be recreated if KubeVirt allows
+	// disks without a volume.
+	emptyCDROMs, err := h.declarativeHotplugVolumesEnabled()
+	if err != nil {
+		return err
+	}
+	if !emptyCDROMs && len(vm.Status.EmptyCDROMDrives) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"name":      vm.Name,
+			"namespace": vm.Namespace,
+			"drives":    len(vm.Status.EmptyCDROMDrives),
+		}).Warnf("Dropping CD-ROM drives without media, the KubeVirt feature gate %s is disabled", featureGateDeclarativeHotplugVolumes)
+		h.recorder.Eventf(vm, corev1.EventTypeWarning, eventReasonEmptyCDROMDrivesDropped,
+			"Dropped %d CD-ROM drives without media, the KubeVirt feature gate %s is disabled", len(vm.Status.EmptyCDROMDrives), featureGateDeclarativeHotplugVolumes)
+	}
+
+	var maxBootOrder uint
+	for _, iface := range runVM.Spec.Template.Spec.Domain.Devices.Interfaces {
+		maxBootOrder = max(maxBootOrder, ptr.Deref(iface.BootOrder, 0))
+	}
+	vmVols, disks := virtualMachineDisks(vm, maxBootOrder, emptyCDROMs)
+
+	runVM.Spec.Template.Spec.Volumes = vmVols
+	runVM.Spec.Template.Spec.Domain.Devices.Disks = disks
+
+	// Apply a label to the `VirtualMachine` object to make the newly
+	// created VM identifiable.
+	metav1.SetMetaDataLabel(&runVM.ObjectMeta, labelImported, "true")
+
+	// Record where the VM comes from.
+	provenance, err := util.VirtualMachineProvenanceAnnotations(vm)
+	if err != nil {
+		return err
+	}
+	for k, v := range provenance {
+		metav1.SetMetaDataAnnotation(&runVM.ObjectMeta, k, v)
+	}
+
+	// Make sure the new VM is created only if it does not exist.
+	found := false
+	existingVM, err := h.kubevirt.Get(runVM.Namespace, runVM.Name, metav1.GetOptions{})
+	if err == nil {
+		value, ok := existingVM.Labels[labelImported]
+		if ok && value == "true" {
+			found = true
+		}
+	}
+
+	if !found {
+		_, err := h.kubevirt.Create(runVM)
+		if err != nil {
+			return fmt.Errorf("error creating kubevirt VM %v in createVirtualMachine: %v", runVM, err)
+		}
+	}
+
+	return nil
+}
+
+// virtualMachineDisks returns the volumes and disks of the VM that is
+// created for the given import. The disks keep the boot order of the source
+// VM and are booted from after the network interfaces up to maxBootOrder.
+// CD-ROM drives without media are only added if emptyCDROMs is set, as
+// KubeVirt rejects disks without a volume unless the feature gate
+// DeclarativeHotplugVolumes is enabled.
+func virtualMachineDisks(vm *migration.VirtualMachineImport, maxBootOrder uint, emptyCDROMs bool) ([]kubevirt.Volume, []kubevirt.Disk) {
+	// The disks keep the boot order of the source VM. Disks that are not
+	// in that boot order are booted from after all other devices.
+	bootOrders := util.DiskBootOrders(vm.Status.DiskImportStatus, maxBootOrder)
+
 	// patch VM object with PVC info
 	vmVols := make([]kubevirt.Volume, 0, len(vm.Status.DiskImportStatus))
 	disks := make([]kubevirt.Disk, 0, len(vm.Status.DiskImportStatus))
@@ -682,11 +958,28 @@ func (h *virtualMachineHandler) createVirtualMachine(vm *migration.VirtualMachin
 				},
 			},
 		})
-		diskOrder := i
-		diskOrder++ // Disk order cant be 0, so need to kick things off from 1
+		// CD-ROM images are attached read-only and are only booted from
+		// if they are part of the boot order of the source VM.
+		if v.CDROM {
+			var bootOrder *uint
+			if bootOrders[i] > 0 {
+				bootOrder = ptr.To(bootOrders[i])
+			}
+			disks = append(disks, kubevirt.Disk{
+				Name:      fmt.Sprintf("disk-%d", i),
+				BootOrder: bootOrder,
+				DiskDevice: kubevirt.DiskDevice{
+					CDRom: &kubevirt.CDRomTarget{
+						Bus:      v.BusType,
+						ReadOnly: ptr.To(true),
+					},
+				},
+			})
+			continue
+		}
 		disks = append(disks, kubevirt.Disk{
 			Name:      fmt.Sprintf("disk-%d", i),
-			BootOrder: &[]uint{uint(diskOrder)}[0], // nolint:gosec
+			BootOrder: ptr.To(bootOrders[i]),
 			DiskDevice: kubevirt.DiskDevice{
 				Disk: &kubevirt.DiskTarget{
 					Bus: v.BusType,
@@ -695,31 +988,61 @@ func (h *virtualMachineHandler) createVirtualMachine(vm *migration.VirtualMachin
 		})
 	}
 
-	runVM.Spec.Template.Spec.Volumes = vmVols
-	runVM.Spec.Template.Spec.Domain.Devices.Disks = disks
+	if !emptyCDROMs {
+		return vmVols, disks
+	}
 
-	// Apply a label to the `VirtualMachine` object to make the newly
-	// created VM identifiable.
-	metav1.SetMetaDataLabel(&runVM.ObjectMeta, labelImported, "true")
+	// CD-ROM drives without media have no volume.
+	for i, cd := range vm.Status.EmptyCDROMDrives {
+		disks = append(disks, kubevirt.Disk{
+			Name: fmt.Sprintf("cdrom-%d", i),
+			DiskDevice: kubevirt.DiskDevice{
+				CDRom: &kubevirt.CDRomTarget{
+					Bus: cd.BusType,
+				},
+			},
+		})
+	}
 
-	// Make sure the new VM is created only if it does not exist.
-	found := false
-	existingVM, err := h.kubevirt.Get(runVM.Namespace, runVM.Name, metav1.GetOptions{})
-	if err == nil {
-		value, ok := existingVM.Labels[labelImported]
-		if ok && value == "true" {
-			found = true
-		}
+	return vmVols, disks
+}
+
+// applyResourcePolicy sets the resource requests, the reserved memory and
+// the hugepages of the generated VM according to the resource policies of
+// the import and its source and the overcommit setting of Harvester.
+func (h *virtualMachineHandler) applyResourcePolicy(vm *migration.VirtualMachineImport, runVM *kubevirt.VirtualMachine) error {
+	ss, err := h.generateSource(vm)
+	if err != nil {
+		return fmt.Errorf("error generating source in applyResourcePolicy: %w", err)
 	}
 
-	if !found {
-		_, err := h.kubevirt.Create(runVM)
-		if err != nil {
-			return fmt.Errorf("error creating kubevirt VM %v in createVirtualMachine: %v", runVM, err)
-		}
+	overcommit, err := h.getOvercommit()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":      vm.Name,
+			"namespace": vm.Namespace,
+		}).WithError(err).Warn("Failed to get the overcommit setting, only the request percentages of the resource policy are applied")
 	}
 
-	return nil
+	rp := util.MergeResourcePolicies(vm.Spec.ResourcePolicy, ss.GetResourcePolicy())
+	return util.ApplyResourcePolicy(runVM, rp, overcommit)
+}
+
+// getOvercommit returns the `overcommit-config` setting of Harvester, or nil
+// if it does not exist.
+func (h *virtualMachineHandler) getOvercommit() (*settings.Overcommit, error) {
+	s, err := h.setting.Get(settings.OvercommitConfigSettingName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	value := s.Value
+	if value == "" {
+		value = s.Default
+	}
+	return util.ParseOvercommit(value)
 }
 
 func (h *virtualMachineHandler) checkVirtualMachine(vm *migration.VirtualMachineImport) (bool, error) {
@@ -751,6 +1074,59 @@ func (h *virtualMachineHandler) ReconcileVMI(_ string, _ string, obj runtime.Obj
 	return nil, nil
 }
 
+// ReconcileSecret enqueues the in-flight imports whose source uses the
+// changed secret. The clients of the source are created for every reconcile,
+// so the imports continue with the new credentials right away.
+func (h *virtualMachineHandler) ReconcileSecret(namespace string, name string, _ runtime.Object) ([]relatedresource.Key, error) {
+	secretKey := secretIndexKey(namespace, name)
+	var sourceKeys []string
+
+	vmwareSources, err := h.vmware.Cache().GetByIndex(vmwareSourceBySecretIndex, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range vmwareSources {
+		sourceKeys = append(sourceKeys, sourceIndexKey(migration.KindVmwareSource, s.Namespace, s.Name))
+	}
+
+	ovaSources, err := h.ova.Cache().GetByIndex(ovaSourceBySecretIndex, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range ovaSources {
+		sourceKeys = append(sourceKeys, sourceIndexKey(migration.KindOvaSource, s.Namespace, s.Name))
+	}
+
+	openstackSources, err := h.openstack.Cache().GetByIndex(openstackSourceBySecretIndex, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range openstackSources {
+		sourceKeys = append(sourceKeys, sourceIndexKey(migration.KindOpenstackSource, s.Namespace, s.Name))
+	}
+
+	var keys []relatedresource.Key
+	for _, sourceKey := range sourceKeys {
+		imports, err := h.importVM.Cache().GetByIndex(virtualMachineImportBySourceIndex, sourceKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, vm := range imports {
+			switch vm.Status.Status {
+			case migration.VirtualMachineRunning, migration.VirtualMachineImportInvalid, migration.VirtualMachineMigrationFailed:
+				// Nothing to do for finished imports.
+			default:
+				keys = append(keys, relatedresource.Key{
+					Namespace: vm.Namespace,
+					Name:      vm.Name,
+				})
+			}
+		}
+	}
+
+	return keys, nil
+}
+
 func (h *virtualMachineHandler) cleanupAndResubmit(vm *migration.VirtualMachineImport) error {
 	// need to wait for all VMI's to be complete or failed before we cleanup failed objects
 	for i, d := range vm.Status.DiskImportStatus {
@@ -791,17 +1167,21 @@ func (h *virtualMachineHandler) findAndCreatePVC(vm *migration.VirtualMachineImp
 			}
 
 			if createPVC {
+				annotations, err := util.DiskProvenanceAnnotations(vm, v)
+				if err != nil {
+					return err
+				}
+				annotations[harvesterutil.AnnotationImageID] = fmt.Sprintf("%s/%s", vmiObj.Namespace, vmiObj.Name)
+
 				pvcObj := &corev1.PersistentVolumeClaim{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      pvcName,
-						Namespace: vm.Namespace,
-						Annotations: map[string]string{
-							harvesterutil.AnnotationImageID: fmt.Sprintf("%s/%s", vmiObj.Namespace, vmiObj.Name),
-						},
+						Name:        pvcName,
+						Namespace:   vm.Namespace,
+						Annotations: annotations,
 					},
 					Spec: corev1.PersistentVolumeClaimSpec{
 						AccessModes: []corev1.PersistentVolumeAccessMode{
-							corev1.ReadWriteMany,
+							util.GetDiskAccessMode(vm, v),
 						},
 						Resources: corev1.VolumeResourceRequirements{
 							Requests: corev1.ResourceList{
@@ -809,7 +1189,7 @@ func (h *virtualMachineHandler) findAndCreatePVC(vm *migration.VirtualMachineImp
 							},
 						},
 						StorageClassName: &vmiObj.Status.StorageClassName,
-						VolumeMode:       &[]corev1.PersistentVolumeMode{corev1.PersistentVolumeBlock}[0],
+						VolumeMode:       ptr.To(util.GetDiskVolumeMode(vm, v)),
 					},
 				}
 
@@ -889,10 +1269,16 @@ func (h *virtualMachineHandler) checkAndCreateVirtualMachineImage(vm *migration.
 	}
 
 	// No VirtualMachineImage object found. Create a new one and return the object.
+	annotations, err := util.DiskProvenanceAnnotations(vm, d)
+	if err != nil {
+		return nil, err
+	}
+
 	vmi := &harvesterv1beta1.VirtualMachineImage{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "image-",
 			Namespace:    vm.Namespace,
+			Annotations:  annotations,
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion: vm.APIVersion,
@@ -917,19 +1303,15 @@ func (h *virtualMachineHandler) checkAndCreateVirtualMachineImage(vm *migration.
 		},
 	}
 
-	if vm.Spec.StorageClass != "" {
-		vmiBackend, err := util.GetBackendFromStorageClassName(vm.Spec.StorageClass, h.sc)
+	if sc := d.StorageClass; sc != "" {
+		vmiBackend, err := util.GetBackendFromStorageClassName(sc, h.sc)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get VMI backend from storage class '%s': %v", vm.Spec.StorageClass, err)
+			return nil, fmt.Errorf("failed to get VMI backend from storage class '%s': %v", sc, err)
 		}
 
-		if vmi.Annotations == nil {
-			vmi.Annotations = make(map[string]string)
-		}
-
-		vmi.Annotations[harvesterutil.AnnotationStorageClassName] = vm.Spec.StorageClass
+		vmi.Annotations[harvesterutil.AnnotationStorageClassName] = sc
 		vmi.Spec.Backend = vmiBackend
-		vmi.Spec.TargetStorageClassName = vm.Spec.StorageClass
+		vmi.Spec.TargetStorageClassName = sc
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -958,6 +1340,7 @@ func (h *virtualMachineHandler) sanitizeVirtualMachineImport(vm *migration.Virtu
 	err = vmo.SanitizeVirtualMachineImport(vm)
 	if err != nil {
 		vm.Status.Status = migration.VirtualMachineImportInvalid
+		setImportFailedCondition(vm, importFailureReason(err, migration.ReasonSanitizeFailed), err.Error())
 		logrus.WithFields(logrus.Fields{
 			"kind":                              vm.Kind,
 			"name":                              vm.Name,
@@ -965,10 +1348,13 @@ func (h *virtualMachineHandler) sanitizeVirtualMachineImport(vm *migration.Virtu
 			"spec.virtualMachineName":           vm.Spec.VirtualMachineName,
 			"status.importedVirtualMachineName": vm.Status.ImportedVirtualMachineName,
 		}).Errorf("Failed to sanitize the import spec: %v", err)
+		h.recorder.Eventf(vm, corev1.EventTypeWarning, eventReasonSanitizeFailed, "Failed to sanitize the import spec: %v", err)
 	} else {
 		// Make sure the ImportedVirtualMachineName is RFC 1123 compliant.
 		if errs := validation.IsDNS1123Label(vm.Status.ImportedVirtualMachineName); len(errs) != 0 {
 			vm.Status.Status = migration.VirtualMachineImportInvalid
+			setImportFailedCondition(vm, migration.ReasonInvalidVirtualMachineName,
+				fmt.Sprintf("the definitive name %q of the imported VM is not RFC 1123 compliant: %s", vm.Status.ImportedVirtualMachineName, strings.Join(errs, ", ")))
 			logrus.WithFields(logrus.Fields{
 				"kind":                              vm.Kind,
 				"name":                              vm.Name,
@@ -976,6 +1362,8 @@ func (h *virtualMachineHandler) sanitizeVirtualMachineImport(vm *migration.Virtu
 				"spec.virtualMachineName":           vm.Spec.VirtualMachineName,
 				"status.importedVirtualMachineName": vm.Status.ImportedVirtualMachineName,
 			}).Error("The definitive name of the imported VM is not RFC 1123 compliant")
+			h.recorder.Eventf(vm, corev1.EventTypeWarning, eventReasonSanitizeFailed,
+				"The definitive name %q of the imported VM is not RFC 1123 compliant", vm.Status.ImportedVirtualMachineName)
 		} else {
 			vm.Status.Status = migration.SourceReady
 			logrus.WithFields(logrus.Fields{
@@ -988,5 +1376,5 @@ func (h *virtualMachineHandler) sanitizeVirtualMachineImport(vm *migration.Virtu
 		}
 	}
 
-	return h.importVM.UpdateStatus(vm)
+	return h.updateStatus(vm)
 }
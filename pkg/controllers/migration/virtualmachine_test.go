@@ -0,0 +1,139 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	kubevirt "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_virtualMachineDisks(t *testing.T) {
+	assert := require.New(t)
+	vm := &migration.VirtualMachineImport{
+		Status: migration.VirtualMachineImportStatus{
+			DiskImportStatus: []migration.DiskInfo{
+				{Name: "disk-0", VirtualMachineImage: "image-disk", BusType: kubevirt.DiskBusVirtio, BootOrder: 1},
+				{Name: "installer.iso", VirtualMachineImage: "image-iso", BusType: kubevirt.DiskBusSATA, CDROM: true},
+			},
+			EmptyCDROMDrives: []migration.CDROMDrive{
+				{BusType: kubevirt.DiskBusSATA},
+				{BusType: kubevirt.DiskBusSCSI},
+			},
+		},
+	}
+
+	vols, disks := virtualMachineDisks(vm, 0, true)
+	assert.Len(vols, 2)
+	assert.Equal("image-disk", vols[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal("image-iso", vols[1].PersistentVolumeClaim.ClaimName)
+	assert.Equal([]kubevirt.Disk{
+		{
+			Name:       "disk-0",
+			BootOrder:  ptr.To(uint(1)),
+			DiskDevice: kubevirt.DiskDevice{Disk: &kubevirt.DiskTarget{Bus: kubevirt.DiskBusVirtio}},
+		},
+		{
+			Name:       "disk-1",
+			DiskDevice: kubevirt.DiskDevice{CDRom: &kubevirt.CDRomTarget{Bus: kubevirt.DiskBusSATA, ReadOnly: ptr.To(true)}},
+		},
+		{
+			Name:       "cdrom-0",
+			DiskDevice: kubevirt.DiskDevice{CDRom: &kubevirt.CDRomTarget{Bus: kubevirt.DiskBusSATA}},
+		},
+		{
+			Name:       "cdrom-1",
+			DiskDevice: kubevirt.DiskDevice{CDRom: &kubevirt.CDRomTarget{Bus: kubevirt.DiskBusSCSI}},
+		},
+	}, disks)
+
+	// Without the feature gate, the drives without media are dropped.
+	vols, disks = virtualMachineDisks(vm, 0, false)
+	assert.Len(vols, 2)
+	assert.Len(disks, 2)
+	assert.Equal("disk-1", disks[1].Name)
+}
+
+func Test_featureGateEnabled(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc     string
+		kvs      []*kubevirt.KubeVirt
+		expected bool
+	}{
+		{
+			desc:     "No KubeVirt configuration",
+			expected: false,
+		},
+		{
+			desc: "No developer configuration",
+			kvs: []*kubevirt.KubeVirt{
+				{},
+			},
+			expected: false,
+		},
+		{
+			desc: "Other feature gates",
+			kvs: []*kubevirt.KubeVirt{
+				{Spec: kubevirt.KubeVirtSpec{Configuration: kubevirt.KubeVirtConfiguration{
+					DeveloperConfiguration: &kubevirt.DeveloperConfiguration{FeatureGates: []string{"HotplugVolumes"}},
+				}}},
+			},
+			expected: false,
+		},
+		{
+			desc: "Feature gate enabled",
+			kvs: []*kubevirt.KubeVirt{
+				{Spec: kubevirt.KubeVirtSpec{Configuration: kubevirt.KubeVirtConfiguration{
+					DeveloperConfiguration: &kubevirt.DeveloperConfiguration{FeatureGates: []string{"HotplugVolumes", featureGateDeclarativeHotplugVolumes}},
+				}}},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, featureGateEnabled(tc.kvs, featureGateDeclarativeHotplugVolumes), tc.desc)
+	}
+}
+
+// fakeImportController records the status updates of the imports. All other
+// methods are not implemented.
+type fakeImportController struct {
+	migrationController.VirtualMachineImportController
+
+	updated []*migration.VirtualMachineImport
+}
+
+func (c *fakeImportController) UpdateStatus(vm *migration.VirtualMachineImport) (*migration.VirtualMachineImport, error) {
+	c.updated = append(c.updated, vm.DeepCopy())
+	return vm, nil
+}
+
+func Test_updateStatus(t *testing.T) {
+	assert := require.New(t)
+	importVM := &fakeImportController{}
+	h := &virtualMachineHandler{importVM: importVM}
+	vm := &migration.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		Status: migration.VirtualMachineImportStatus{
+			Status:           migration.VirtualMachineImportValid,
+			DiskImportStatus: []migration.DiskInfo{{Name: "disk-0"}},
+		},
+	}
+
+	_, err := h.updateStatus(vm)
+	assert.NoError(err)
+
+	// The phase history and the progress are written with the same update.
+	assert.Len(importVM.updated, 1)
+	updated := importVM.updated[0]
+	assert.Len(updated.Status.PhaseHistory, 2)
+	assert.Equal(migration.ImportStepPreflight, updated.Status.PhaseHistory[0].Step)
+	assert.Equal(migration.VirtualMachineImportValid, updated.Status.PhaseHistory[1].Phase)
+	assert.Equal(int32(1), updated.Status.DiskCount)
+}
@@ -6,30 +6,34 @@ import (
 	"time"
 
 	corecontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"github.com/sirupsen/logrus"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
-	"github.com/harvester/vm-import-controller/pkg/apis/common"
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	migrationController "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
 	"github.com/harvester/vm-import-controller/pkg/source/vmware"
-	"github.com/harvester/vm-import-controller/pkg/util"
 )
 
 type vmwareHandler struct {
-	ctx    context.Context
-	source migrationController.VmwareSourceController
-	secret corecontrollers.SecretController
+	ctx      context.Context
+	source   migrationController.VmwareSourceController
+	secret   corecontrollers.SecretController
+	recorder record.EventRecorder
 }
 
-func RegisterVmwareController(ctx context.Context, source migrationController.VmwareSourceController, secret corecontrollers.SecretController) {
+func RegisterVmwareController(ctx context.Context, source migrationController.VmwareSourceController, secret corecontrollers.SecretController, recorder record.EventRecorder) {
 	vHandler := &vmwareHandler{
-		ctx:    ctx,
-		source: source,
-		secret: secret,
+		ctx:      ctx,
+		source:   source,
+		secret:   secret,
+		recorder: recorder,
 	}
 	source.OnChange(ctx, "vmware-source-change", vHandler.OnSourceChange)
+
+	source.Cache().AddIndexer(vmwareSourceBySecretIndex, indexSourceBySecret[*migration.VmwareSource])
+	relatedresource.Watch(ctx, "vmware-source-secret-change", resolveSourcesBySecret(source.Cache(), vmwareSourceBySecretIndex), source, secret)
 }
 
 func (h *vmwareHandler) OnSourceChange(_ string, v *migration.VmwareSource) (*migration.VmwareSource, error) {
@@ -37,74 +41,98 @@ func (h *vmwareHandler) OnSourceChange(_ string, v *migration.VmwareSource) (*mi
 		return nil, nil
 	}
 
+	// Sources are verified periodically to detect expired credentials or
+	// unreachable endpoints before an import fails.
+	secretVersion := sourceSecretVersion(h.secret.Cache(), v)
+	changed := v.Status.ObservedGeneration != v.Generation || v.Status.ObservedSecretVersion != secretVersion
+	if wait := nextSourceVerification(v.Status.Status, v.Status.LastVerifiedTime, changed, time.Now()); wait > 0 {
+		h.source.EnqueueAfter(v.Namespace, v.Name, wait)
+		return v, nil
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"kind":      v.Kind,
 		"name":      v.Name,
 		"namespace": v.Namespace,
 	}).Info("Reconciling source")
 
-	if v.Status.Status != migration.ClusterReady {
-		var client *vmware.Client
-		var err error
+	startTime := time.Now()
+	result := h.verify(v)
+	now := time.Now()
+
+	if result.err != nil {
+		logrus.WithFields(logrus.Fields{
+			"apiVersion": v.APIVersion,
+			"kind":       v.Kind,
+			"name":       v.Name,
+			"namespace":  v.Namespace,
+			"reason":     result.reason,
+			"err":        result.err,
+		}).Error("Failed to verify source for migration")
+	}
+
+	vCopy := v.DeepCopy()
+	vCopy.Status.Conditions, vCopy.Status.Status = sourceVerificationConditions(vCopy.Status.Conditions, result, now)
+	vCopy.Status.ObservedGeneration = v.Generation
+	vCopy.Status.ObservedSecretVersion = secretVersion
+	vCopy.Status.LastVerifiedTime = now.UTC().Format(time.RFC3339)
+	vCopy.Status.VerificationLatency = &metav1.Duration{Duration: now.Sub(startTime).Round(time.Millisecond)}
+	if result.version != "" {
+		vCopy.Status.Version = result.version
+	}
+	if result.networks != nil {
+		vCopy.Status.Networks = result.networks
+	}
 
-		secretObj, err := h.secret.Get(v.SecretReference().Namespace, v.SecretReference().Name, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to lookup secret for %s migration %s: %w", v.Kind, v.NamespacedName(), err)
+	// Successful periodic verifications are not worth an event.
+	if result.err != nil || v.Status.Status != migration.ClusterReady {
+		recordSourceVerification(h.recorder, vCopy, result.err)
+	}
+
+	return h.source.UpdateStatus(vCopy)
+}
+
+// verify checks the connection to the vCenter or ESXi host and that the
+// configured datacenter exists. The networks of the datacenter are reported
+// as well.
+func (h *vmwareHandler) verify(v *migration.VmwareSource) *sourceVerification {
+	secretObj, err := h.secret.Cache().Get(v.SecretReference().Namespace, v.SecretReference().Name)
+	if err != nil {
+		return &sourceVerification{
+			err:    fmt.Errorf("failed to lookup secret for %s migration %s: %w", v.Kind, v.NamespacedName(), err),
+			reason: migration.ReasonCredentialsNotFound,
 		}
+	}
 
-		client, err = vmware.NewClient(h.ctx, v.Spec.EndpointAddress, v.Spec.Datacenter, secretObj)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate client for %s migration %s: %w", v.Kind, v.NamespacedName(), err)
+	client, err := vmware.NewClient(h.ctx, v.Spec.EndpointAddress, v.Spec.Datacenter, secretObj)
+	if err != nil {
+		return &sourceVerification{
+			err:    fmt.Errorf("failed to generate client for %s migration %s: %w", v.Kind, v.NamespacedName(), err),
+			reason: migration.ReasonConnectionFailed,
 		}
+	}
+	defer client.Close() //nolint:errcheck
 
-		err = client.Verify()
-		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"apiVersion": v.APIVersion,
-				"kind":       v.Kind,
-				"name":       v.Name,
-				"namespace":  v.Namespace,
-				"err":        err,
-			}).Error("Failed to verify source for migration")
-
-			// unable to find specific datacenter
-			conds := []common.Condition{
-				{
-					Type:               migration.ClusterErrorCondition,
-					Status:             corev1.ConditionTrue,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				}, {
-					Type:               migration.ClusterReadyCondition,
-					Status:             corev1.ConditionFalse,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				},
-			}
-
-			v.Status.Conditions = util.MergeConditions(v.Status.Conditions, conds)
-			v.Status.Status = migration.ClusterNotReady
-		} else {
-			conds := []common.Condition{
-				{
-					Type:               migration.ClusterReadyCondition,
-					Status:             corev1.ConditionTrue,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				}, {
-					Type:               migration.ClusterErrorCondition,
-					Status:             corev1.ConditionFalse,
-					LastUpdateTime:     metav1.Now().Format(time.RFC3339),
-					LastTransitionTime: metav1.Now().Format(time.RFC3339),
-				},
-			}
-
-			v.Status.Conditions = util.MergeConditions(v.Status.Conditions, conds)
-			v.Status.Status = migration.ClusterReady
+	if err := client.Verify(); err != nil {
+		return &sourceVerification{
+			err:    err,
+			reason: migration.ReasonSourceVerificationFailed,
 		}
+	}
 
-		return h.source.UpdateStatus(v)
+	networks, err := client.ListNetworks()
+	if err != nil {
+		// The networks are informational only.
+		logrus.WithFields(logrus.Fields{
+			"kind":      v.Kind,
+			"name":      v.Name,
+			"namespace": v.Namespace,
+			"err":       err,
+		}).Warn("Failed to list networks of the source")
 	}
 
-	return nil, nil
+	return &sourceVerification{
+		version:  client.Version(),
+		networks: networks,
+	}
 }
@@ -2,33 +2,195 @@ package crd
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 
 	"github.com/rancher/wrangler/v3/pkg/crd"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	migrationv1beta2 "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta2"
+	"github.com/harvester/vm-import-controller/pkg/webhook"
 )
 
-func List() []crd.CRD {
+// lastVerifiedColumn shows the time since the last verification of a source.
+var lastVerifiedColumn = apiextv1.CustomResourceColumnDefinition{
+	Name:     "Last Verified",
+	Type:     "date",
+	JSONPath: ".status.lastVerifiedTime",
+}
+
+func List() ([]crd.CRD, error) {
+	vmImportCRD, err := newVirtualMachineImportCRD()
+	if err != nil {
+		return nil, err
+	}
+
 	return []crd.CRD{
 		newCRD("migration.harvesterhci.io", &migration.VmwareSource{}, func(c crd.CRD) crd.CRD {
 			return c.
-				WithColumn("Status", ".status.status")
+				WithColumn("Status", ".status.status").
+				WithColumn("Version", ".status.version").
+				WithCustomColumn(lastVerifiedColumn)
 		}),
 		newCRD("migration.harvesterhci.io", &migration.OvaSource{}, func(c crd.CRD) crd.CRD {
 			return c.
-				WithColumn("Status", ".status.status")
+				WithColumn("Status", ".status.status").
+				WithCustomColumn(lastVerifiedColumn)
 		}),
 		newCRD("migration.harvesterhci.io", &migration.OpenstackSource{}, func(c crd.CRD) crd.CRD {
 			return c.
-				WithColumn("Status", ".status.status")
+				WithColumn("Status", ".status.status").
+				WithColumn("Version", ".status.version").
+				WithCustomColumn(lastVerifiedColumn)
 		}),
-		newCRD("migration.harvesterhci.io", &migration.VirtualMachineImport{}, func(c crd.CRD) crd.CRD {
+		newCRD("migration.harvesterhci.io", &migration.SourceInventory{}, func(c crd.CRD) crd.CRD {
 			return c.
-				WithColumn("Status", ".status.importStatus")
+				WithColumn("Source Kind", ".spec.sourceCluster.kind").
+				WithColumn("Source", ".spec.sourceCluster.name").
+				WithCustomColumn(
+					apiextv1.CustomResourceColumnDefinition{
+						Name:        "VMs",
+						Type:        "integer",
+						Description: "The number of virtual machines found in the source",
+						JSONPath:    ".status.virtualMachineCount",
+					},
+					apiextv1.CustomResourceColumnDefinition{
+						Name:     "Last Refresh",
+						Type:     "date",
+						JSONPath: ".status.lastRefreshTime",
+					},
+				)
 		}),
+		vmImportCRD,
+	}, nil
+}
+
+// newVirtualMachineImportCRD returns the `VirtualMachineImport` CRD that
+// serves the v1beta1 and v1beta2 API versions. The objects are stored as
+// v1beta1 and converted by the conversion webhook of the controller. If the
+// webhooks are disabled, v1beta2 is not served, as it cannot be converted.
+func newVirtualMachineImportCRD() (crd.CRD, error) {
+	v1beta1CRD := newCRD("migration.harvesterhci.io", &migration.VirtualMachineImport{}, func(c crd.CRD) crd.CRD {
+		return withVirtualMachineImportColumns(c.
+			WithColumn("Status", ".status.importStatus"))
+	})
+
+	v1beta2CRD := newCRD("migration.harvesterhci.io", &migrationv1beta2.VirtualMachineImport{}, func(c crd.CRD) crd.CRD {
+		c.GVK.Version = migrationv1beta2.SchemeGroupVersion.Version
+		return withVirtualMachineImportColumns(c.
+			WithColumn("Phase", ".status.phase"))
+	})
+
+	v1beta1Def, err := toCustomResourceDefinition(v1beta1CRD)
+	if err != nil {
+		return v1beta1CRD, err
+	}
+
+	v1beta2Def, err := toCustomResourceDefinition(v1beta2CRD)
+	if err != nil {
+		return v1beta1CRD, err
 	}
+
+	webhookDisabled := webhook.Disabled()
+	for i := range v1beta2Def.Spec.Versions {
+		v1beta2Def.Spec.Versions[i].Storage = false
+		v1beta2Def.Spec.Versions[i].Served = !webhookDisabled
+	}
+	v1beta1Def.Spec.Versions = append(v1beta1Def.Spec.Versions, v1beta2Def.Spec.Versions...)
+	v1beta1Def.Spec.Conversion = &apiextv1.CustomResourceConversion{
+		Strategy: apiextv1.WebhookConverter,
+		Webhook: &apiextv1.WebhookConversion{
+			ClientConfig:             webhook.ConversionClientConfig(),
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	if webhookDisabled {
+		v1beta1Def.Spec.Conversion = &apiextv1.CustomResourceConversion{
+			Strategy: apiextv1.NoneConverter,
+		}
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(v1beta1Def)
+	if err != nil {
+		return v1beta1CRD, err
+	}
+	// The field is omitted if false, but must be set explicitly.
+	if err := unstructured.SetNestedField(data, false, "spec", "preserveUnknownFields"); err != nil {
+		return v1beta1CRD, err
+	}
+	unstructured.RemoveNestedField(data, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(data, "status")
+
+	v1beta1CRD.Override = &unstructured.Unstructured{Object: data}
+	return v1beta1CRD, nil
+}
+
+// withVirtualMachineImportColumns adds the printer columns that are shared by
+// all API versions of the `VirtualMachineImport` CRD. The status column is
+// added by the caller because its path differs between the versions.
+func withVirtualMachineImportColumns(c crd.CRD) crd.CRD {
+	return c.
+		WithColumn("Source Kind", ".spec.sourceCluster.kind").
+		WithColumn("Source", ".spec.sourceCluster.name").
+		WithColumn("Source VM", ".spec.virtualMachineName").
+		WithColumn("Imported VM", ".status.importedVirtualMachineName").
+		WithCustomColumn(
+			apiextv1.CustomResourceColumnDefinition{
+				Name:        "Disks",
+				Type:        "integer",
+				Description: "The number of imported disks",
+				JSONPath:    ".status.diskCount",
+			},
+			apiextv1.CustomResourceColumnDefinition{
+				Name:        "Progress",
+				Type:        "integer",
+				Description: "The estimated progress of the import in percent",
+				JSONPath:    ".status.progress",
+			},
+		).
+		WithCustomColumn(apiextv1.CustomResourceColumnDefinition{
+			Name:        "Elapsed",
+			Type:        "string",
+			Description: "The time spent on the import up to the last phase transition",
+			JSONPath:    ".status.elapsedTime",
+		}, apiextv1.CustomResourceColumnDefinition{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		})
+}
+
+func toCustomResourceDefinition(c crd.CRD) (*apiextv1.CustomResourceDefinition, error) {
+	obj, err := c.ToCustomResourceDefinition()
+	if err != nil {
+		return nil, err
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected CRD type %T", obj)
+	}
+
+	// The numbers of the generated schema are encoded as `json.Number`,
+	// which is not supported by the unstructured converter.
+	data, err := json.Marshal(u.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &apiextv1.CustomResourceDefinition{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func Create(ctx context.Context, cfg *rest.Config) error {
@@ -37,7 +199,55 @@ func Create(ctx context.Context, cfg *rest.Config) error {
 		return err
 	}
 
-	return factory.BatchCreateCRDs(ctx, List()...).BatchWait()
+	crds, err := List()
+	if err != nil {
+		return err
+	}
+
+	if err := preserveConversionCABundle(crds, func(name string) (*apiextv1.CustomResourceDefinition, error) {
+		return factory.CRDClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	}); err != nil {
+		return err
+	}
+
+	return factory.BatchCreateCRDs(ctx, crds...).BatchWait()
+}
+
+// preserveConversionCABundle copies the CA bundle of the conversion webhook
+// from the existing CRDs into the given CRDs. The CA bundle is injected by
+// the webhook server and must not be dropped when the CRDs are re-applied.
+func preserveConversionCABundle(crds []crd.CRD, existing func(name string) (*apiextv1.CustomResourceDefinition, error)) error {
+	for _, c := range crds {
+		u, ok := c.Override.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		if _, found, _ := unstructured.NestedMap(u.Object, "spec", "conversion", "webhook", "clientConfig"); !found {
+			continue
+		}
+
+		live, err := existing(u.GetName())
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get CRD %s: %w", u.GetName(), err)
+		}
+
+		conversion := live.Spec.Conversion
+		if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil ||
+			len(conversion.Webhook.ClientConfig.CABundle) == 0 {
+			continue
+		}
+
+		caBundle := base64.StdEncoding.EncodeToString(conversion.Webhook.ClientConfig.CABundle)
+		if err := unstructured.SetNestedField(u.Object, caBundle, "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func newCRD(group string, obj interface{}, customize func(crd.CRD) crd.CRD) crd.CRD {
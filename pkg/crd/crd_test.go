@@ -0,0 +1,95 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_preserveConversionCABundle(t *testing.T) {
+	assert := require.New(t)
+
+	crds, err := List()
+	assert.NoError(err)
+
+	caBundle := func() string {
+		for _, c := range crds {
+			if u, ok := c.Override.(*unstructured.Unstructured); ok {
+				val, _, _ := unstructured.NestedString(u.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+				return val
+			}
+		}
+		return ""
+	}
+
+	// CRDs that do not exist yet are applied without a CA bundle.
+	err = preserveConversionCABundle(crds, func(name string) (*apiextv1.CustomResourceDefinition, error) {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: apiextv1.GroupName, Resource: "customresourcedefinitions"}, name)
+	})
+	assert.NoError(err)
+	assert.Empty(caBundle())
+
+	// The CA bundle of an existing CRD is kept.
+	err = preserveConversionCABundle(crds, func(name string) (*apiextv1.CustomResourceDefinition, error) {
+		assert.Equal("virtualmachineimports.migration.harvesterhci.io", name)
+		return &apiextv1.CustomResourceDefinition{
+			Spec: apiextv1.CustomResourceDefinitionSpec{
+				Conversion: &apiextv1.CustomResourceConversion{
+					Strategy: apiextv1.WebhookConverter,
+					Webhook: &apiextv1.WebhookConversion{
+						ClientConfig: &apiextv1.WebhookClientConfig{CABundle: []byte("ca")},
+					},
+				},
+			},
+		}, nil
+	})
+	assert.NoError(err)
+	assert.Equal("Y2E=", caBundle())
+}
+
+func Test_newVirtualMachineImportCRD(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc             string
+		disableWebhook   string
+		expectedStrategy apiextv1.ConversionStrategyType
+		expectedServed   []string
+	}{
+		{
+			desc:             "Conversion webhook",
+			expectedStrategy: apiextv1.WebhookConverter,
+			expectedServed:   []string{"v1beta1", "v1beta2"},
+		},
+		{
+			desc:             "Webhooks disabled",
+			disableWebhook:   "true",
+			expectedStrategy: apiextv1.NoneConverter,
+			expectedServed:   []string{"v1beta1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Setenv("DISABLE_WEBHOOK", tc.disableWebhook)
+
+		c, err := newVirtualMachineImportCRD()
+		assert.NoError(err, tc.desc)
+		u, ok := c.Override.(*unstructured.Unstructured)
+		assert.True(ok, tc.desc)
+		def := &apiextv1.CustomResourceDefinition{}
+		assert.NoError(runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, def), tc.desc)
+
+		assert.Equal(tc.expectedStrategy, def.Spec.Conversion.Strategy, tc.desc)
+		var served []string
+		for _, v := range def.Spec.Versions {
+			if v.Served {
+				served = append(served, v.Name)
+			}
+		}
+		assert.Equal(tc.expectedServed, served, tc.desc)
+	}
+}
@@ -20,10 +20,12 @@ package migration
 
 import (
 	v1beta1 "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta1"
+	v1beta2 "github.com/harvester/vm-import-controller/pkg/generated/controllers/migration.harvesterhci.io/v1beta2"
 	"github.com/rancher/lasso/pkg/controller"
 )
 
 type Interface interface {
+	V1beta2() v1beta2.Interface
 	V1beta1() v1beta1.Interface
 }
 
@@ -38,6 +40,10 @@ func New(controllerFactory controller.SharedControllerFactory) Interface {
 	}
 }
 
+func (g *group) V1beta2() v1beta2.Interface {
+	return v1beta2.New(g.controllerFactory)
+}
+
 func (g *group) V1beta1() v1beta1.Interface {
 	return v1beta1.New(g.controllerFactory)
 }
@@ -33,6 +33,7 @@ func init() {
 type Interface interface {
 	OpenstackSource() OpenstackSourceController
 	OvaSource() OvaSourceController
+	SourceInventory() SourceInventoryController
 	VirtualMachineImport() VirtualMachineImportController
 	VmwareSource() VmwareSourceController
 }
@@ -55,6 +56,10 @@ func (v *version) OvaSource() OvaSourceController {
 	return generic.NewController[*v1beta1.OvaSource, *v1beta1.OvaSourceList](schema.GroupVersionKind{Group: "migration.harvesterhci.io", Version: "v1beta1", Kind: "OvaSource"}, "ovasources", true, v.controllerFactory)
 }
 
+func (v *version) SourceInventory() SourceInventoryController {
+	return generic.NewController[*v1beta1.SourceInventory, *v1beta1.SourceInventoryList](schema.GroupVersionKind{Group: "migration.harvesterhci.io", Version: "v1beta1", Kind: "SourceInventory"}, "sourceinventories", true, v.controllerFactory)
+}
+
 func (v *version) VirtualMachineImport() VirtualMachineImportController {
 	return generic.NewController[*v1beta1.VirtualMachineImport, *v1beta1.VirtualMachineImportList](schema.GroupVersionKind{Group: "migration.harvesterhci.io", Version: "v1beta1", Kind: "VirtualMachineImport"}, "virtualmachineimports", true, v.controllerFactory)
 }
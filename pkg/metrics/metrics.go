@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+const (
+	namespace = "vm_import_controller"
+
+	labelStatus     = "status"
+	labelSourceKind = "source_kind"
+	labelPhase      = "phase"
+)
+
+var (
+	// Registry is the registry that contains all metrics of the controller.
+	Registry = prometheus.NewRegistry()
+
+	imports = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "imports",
+		Help:      "Number of VirtualMachineImports by status and source kind.",
+	}, []string{labelStatus, labelSourceKind})
+
+	phaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "import_phase_duration_seconds",
+		Help:      "Time a VirtualMachineImport spent in an import phase.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 10), // 1s .. ~3d
+	}, []string{labelPhase, labelSourceKind})
+
+	bytesTransferred = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transferred_bytes_total",
+		Help:      "Number of bytes downloaded from the sources.",
+	}, []string{labelSourceKind})
+
+	conversionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "disk_conversion_duration_seconds",
+		Help:      "Time needed to convert a disk image to RAW format.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14), // 1s .. ~2h
+	}, []string{labelSourceKind})
+
+	sourceVerificationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_verification_failures_total",
+		Help:      "Number of failed source verifications.",
+	}, []string{labelSourceKind})
+
+	tracker = &importTracker{
+		imports: make(map[string]trackedImport),
+	}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		imports,
+		phaseDuration,
+		bytesTransferred,
+		conversionDuration,
+		sourceVerificationFailures,
+	)
+}
+
+// trackedImport is the last observed state of a VirtualMachineImport.
+type trackedImport struct {
+	sourceKind string
+	status     migration.ImportStatus
+	since      time.Time
+	// complete is false if the controller did not observe the transition
+	// into the current status, e.g. after a restart. The time spent in such
+	// a phase is unknown and therefore not recorded.
+	complete bool
+}
+
+type importTracker struct {
+	mutex   sync.Mutex
+	imports map[string]trackedImport
+}
+
+// ObserveImport updates the import metrics with the current status of the
+// given VirtualMachineImport.
+func ObserveImport(vm *migration.VirtualMachineImport) {
+	tracker.observe(vm.NamespacedName(), strings.ToLower(vm.Spec.SourceCluster.Kind), vm.Status.Status, time.Now())
+}
+
+// ForgetImport removes the given VirtualMachineImport from the import metrics.
+func ForgetImport(vm *migration.VirtualMachineImport) {
+	tracker.forget(vm.NamespacedName())
+}
+
+// AddBytesTransferred increases the number of bytes that have been
+// downloaded from a source of the given kind.
+func AddBytesTransferred(sourceKind string, bytes int64) {
+	bytesTransferred.WithLabelValues(sourceKind).Add(float64(bytes))
+}
+
+// ObserveConversionDuration records the time needed to convert a disk image
+// of a source of the given kind.
+func ObserveConversionDuration(sourceKind string, d time.Duration) {
+	conversionDuration.WithLabelValues(sourceKind).Observe(d.Seconds())
+}
+
+// IncSourceVerificationFailures increases the number of failed verifications
+// of sources of the given kind.
+func IncSourceVerificationFailures(sourceKind string) {
+	sourceVerificationFailures.WithLabelValues(sourceKind).Inc()
+}
+
+func (t *importTracker) observe(key, sourceKind string, status migration.ImportStatus, now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	last, found := t.imports[key]
+	if found && last.status == status {
+		return
+	}
+
+	if found {
+		imports.WithLabelValues(statusLabel(last.status), last.sourceKind).Dec()
+		if last.complete {
+			phaseDuration.WithLabelValues(statusLabel(last.status), last.sourceKind).Observe(now.Sub(last.since).Seconds())
+		}
+	}
+
+	imports.WithLabelValues(statusLabel(status), sourceKind).Inc()
+	t.imports[key] = trackedImport{
+		sourceKind: sourceKind,
+		status:     status,
+		since:      now,
+		// A new import is always observed from the beginning.
+		complete: found || status == "",
+	}
+}
+
+func (t *importTracker) forget(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	last, found := t.imports[key]
+	if !found {
+		return
+	}
+
+	imports.WithLabelValues(statusLabel(last.status), last.sourceKind).Dec()
+	delete(t.imports, key)
+}
+
+// statusLabel returns the label value for the given status. New imports do
+// not have a status yet.
+func statusLabel(status migration.ImportStatus) string {
+	if status == "" {
+		return "new"
+	}
+	return string(status)
+}
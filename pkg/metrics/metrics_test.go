@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_importTracker(t *testing.T) {
+	assert := require.New(t)
+	tr := &importTracker{
+		imports: make(map[string]trackedImport),
+	}
+	now := time.Now()
+	kind := "test"
+
+	tr.observe("default/vm", kind, "", now)
+	assert.Equal(1.0, testutil.ToFloat64(imports.WithLabelValues("new", kind)))
+
+	tr.observe("default/vm", kind, migration.VirtualMachineImportValid, now.Add(time.Minute))
+	assert.Equal(0.0, testutil.ToFloat64(imports.WithLabelValues("new", kind)))
+	assert.Equal(1.0, testutil.ToFloat64(imports.WithLabelValues(string(migration.VirtualMachineImportValid), kind)))
+	assert.Equal(1, testutil.CollectAndCount(phaseDuration))
+
+	// Restarting in the middle of an import does not record the phase duration.
+	tr.observe("default/other", kind, migration.DisksExported, now)
+	tr.observe("default/other", kind, migration.DiskImagesSubmitted, now.Add(time.Minute))
+	assert.Equal(1, testutil.CollectAndCount(phaseDuration))
+
+	tr.forget("default/vm")
+	tr.forget("default/other")
+	assert.Equal(0.0, testutil.ToFloat64(imports.WithLabelValues(string(migration.VirtualMachineImportValid), kind)))
+	assert.Equal(0.0, testutil.ToFloat64(imports.WithLabelValues(string(migration.DiskImagesSubmitted), kind)))
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/harvester/vm-import-controller/pkg/metrics"
+)
+
+const (
+	defaultMetricsPort = 9090
+)
+
+// NewMetricsServer serves the Prometheus metrics of the controller.
+func NewMetricsServer(ctx context.Context) error {
+	metrics.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "vm_import_controller",
+		Name:      "scratch_space_used_bytes",
+		Help:      "Number of bytes used by exported disk images in the scratch space.",
+	}, func() float64 {
+		return float64(scratchSpaceUsage(tmpDir))
+	}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+
+	srv := http.Server{
+		Addr:              fmt.Sprintf(":%d", defaultMetricsPort),
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler:           mux,
+	}
+
+	eg, _ := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return srv.ListenAndServe()
+	})
+
+	eg.Go(func() error {
+		<-ctx.Done()
+		return srv.Shutdown(ctx)
+	})
+
+	return eg.Wait()
+}
+
+// scratchSpaceUsage returns the total size of all files below the given path.
+func scratchSpaceUsage(path string) int64 {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("path", path).Debug("failed to calculate scratch space usage")
+	}
+	return size
+}
@@ -31,18 +31,62 @@ type Hardware struct {
 	NumCPU            uint32 // The type is adapted to KubeVirt CPU
 	NumCoresPerSocket uint32 // The type is adapted to KubeVirt CPU
 	MemoryMB          int64
+
+	// NumSockets and NumThreadsPerCore complete the CPU topology of the
+	// source VM. A value of 0 means that it is not known, see `CPUTopology`.
+	NumSockets        uint32
+	NumThreadsPerCore uint32
 }
 
 func NewHardware(numCPU, numCoresPerSocket uint32, memoryMB int64) *Hardware {
 	return &Hardware{NumCPU: numCPU, NumCoresPerSocket: numCoresPerSocket, MemoryMB: memoryMB}
 }
 
+// CPUTopology is the number of sockets, cores per socket and threads per
+// core of a VM.
+type CPUTopology struct {
+	Sockets uint32
+	Cores   uint32
+	Threads uint32
+}
+
+// CPUTopology returns the CPU topology of the hardware. Unknown values are
+// derived from the number of vCPUs, threads default to 1 and cores per
+// socket default to 1 if the number of sockets is unknown. If the result
+// does not match the number of vCPUs, e.g. because the source reports an
+// inconsistent topology, every vCPU is mapped to a socket. This makes sure
+// that the imported VM never has more vCPUs than the source VM.
+func (hw Hardware) CPUTopology() CPUTopology {
+	numCPU := max(hw.NumCPU, 1)
+	threads := max(hw.NumThreadsPerCore, 1)
+	sockets := hw.NumSockets
+	cores := hw.NumCoresPerSocket
+
+	switch {
+	case sockets == 0 && cores == 0:
+		cores = 1
+		sockets = numCPU / threads
+	case sockets == 0:
+		sockets = numCPU / (cores * threads)
+	case cores == 0:
+		cores = numCPU / (sockets * threads)
+	}
+
+	if sockets*cores*threads != numCPU {
+		return CPUTopology{Sockets: numCPU, Cores: 1, Threads: 1}
+	}
+
+	return CPUTopology{Sockets: sockets, Cores: cores, Threads: threads}
+}
+
 type VirtualMachineSpecConfig struct {
 	Name     string
 	Hardware Hardware
 }
 
 func NewVirtualMachineSpec(cfg VirtualMachineSpecConfig) *kubevirtv1.VirtualMachineSpec {
+	topology := cfg.Hardware.CPUTopology()
+
 	return &kubevirtv1.VirtualMachineSpec{
 		RunStrategy: ptr.To(kubevirtv1.RunStrategyRerunOnFailure),
 		Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
@@ -55,9 +99,9 @@ func NewVirtualMachineSpec(cfg VirtualMachineSpecConfig) *kubevirtv1.VirtualMach
 				EvictionStrategy: ptr.To(kubevirtv1.EvictionStrategyLiveMigrateIfPossible),
 				Domain: kubevirtv1.DomainSpec{
 					CPU: &kubevirtv1.CPU{
-						Cores:   cfg.Hardware.NumCPU,
-						Sockets: cfg.Hardware.NumCoresPerSocket,
-						Threads: 1,
+						Cores:   topology.Cores,
+						Sockets: topology.Sockets,
+						Threads: topology.Threads,
 					},
 					Memory: &kubevirtv1.Memory{
 						Guest: ptr.To(resource.MustParse(fmt.Sprintf("%dM", cfg.Hardware.MemoryMB))),
@@ -108,6 +152,93 @@ func ApplyFirmwareSettings(vmSpec *kubevirtv1.VirtualMachineSpec, fw *Firmware)
 	}
 }
 
+// CPUSettings are the CPU and memory settings of the source VM that can be
+// carried over to the imported VM, see `ApplyCPUSettings`.
+type CPUSettings struct {
+	// Features are the CPU features that are hidden or forced by the
+	// source VM.
+	Features []kubevirtv1.CPUFeature
+	// DedicatedCPUs reports whether the vCPUs of the source VM are pinned
+	// to physical CPUs.
+	DedicatedCPUs bool
+	// NUMANodes is the number of guest NUMA nodes, 0 if it is not known.
+	NUMANodes uint32
+	// HugepageSize is the size of the hugepages backing the memory of the
+	// source VM, e.g. "2Mi", empty if it is not known.
+	HugepageSize string
+	// CPUReservation, CPULimit and MemoryReservation are the resources
+	// reserved for and the CPU limit of the source VM, nil if there are none.
+	CPUReservation    *resource.Quantity
+	CPULimit          *resource.Quantity
+	MemoryReservation *resource.Quantity
+}
+
+// defaultHugepageSize is the size of the hugepages used for a guest NUMA
+// topology if the size of the source VM is not known.
+const defaultHugepageSize = "2Mi"
+
+// ApplyCPUSettings applies the CPU model of the import and the CPU settings
+// of the source VM that the import asks to preserve. A guest NUMA topology
+// is only created for dedicated CPUs, because KubeVirt derives it from the
+// CPUs that are exclusively assigned to the VM, which also requires the
+// memory to be backed by hugepages. Reservations and the CPU limit never
+// exceed the limits of the imported VM, and are ignored for dedicated CPUs.
+func ApplyCPUSettings(vmSpec *kubevirtv1.VirtualMachineSpec, vm *migration.VirtualMachineImport, cpu CPUSettings) {
+	domain := &vmSpec.Template.Spec.Domain
+
+	if model := vm.GetCPUModel(); model != "" {
+		domain.CPU.Model = model
+	}
+
+	if vm.PreserveCPUFeatures() && len(cpu.Features) > 0 {
+		domain.CPU.Features = cpu.Features
+	}
+
+	if vm.PreserveCPUPlacement() && cpu.DedicatedCPUs {
+		domain.CPU.DedicatedCPUPlacement = true
+		if cpu.NUMANodes > 1 {
+			domain.CPU.NUMA = &kubevirtv1.NUMA{
+				GuestMappingPassthrough: &kubevirtv1.NUMAGuestMappingPassthrough{},
+			}
+			pageSize := cpu.HugepageSize
+			if pageSize == "" {
+				pageSize = defaultHugepageSize
+			}
+			domain.Memory.Hugepages = &kubevirtv1.Hugepages{PageSize: pageSize}
+		}
+	}
+
+	if vm.PreserveCPUReservations() {
+		limits := domain.Resources.Limits
+		capped := func(q *resource.Quantity, name corev1.ResourceName) *resource.Quantity {
+			if q == nil || q.Sign() <= 0 {
+				return nil
+			}
+			if l, ok := limits[name]; ok && q.Cmp(l) > 0 {
+				return ptr.To(l.DeepCopy())
+			}
+			return q
+		}
+		requests := corev1.ResourceList{}
+		// Dedicated CPUs require whole CPUs to be requested and the memory
+		// request to match the limit, which KubeVirt takes care of.
+		if !domain.CPU.DedicatedCPUPlacement {
+			if q := capped(cpu.CPULimit, corev1.ResourceCPU); q != nil {
+				limits[corev1.ResourceCPU] = *q
+			}
+			if q := capped(cpu.CPUReservation, corev1.ResourceCPU); q != nil {
+				requests[corev1.ResourceCPU] = *q
+			}
+			if q := capped(cpu.MemoryReservation, corev1.ResourceMemory); q != nil {
+				requests[corev1.ResourceMemory] = *q
+			}
+		}
+		if len(requests) > 0 {
+			domain.Resources.Requests = requests
+		}
+	}
+}
+
 // RemoveTempImageFiles removes temporary image files used during migration.
 // Not existing files are ignored. All occurring errors are aggregated and returned.
 func RemoveTempImageFiles(dis []migration.DiskInfo) error {
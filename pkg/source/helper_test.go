@@ -5,8 +5,12 @@ import (
 
 	harvesterutil "github.com/harvester/harvester/pkg/util"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/ptr"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 )
 
 func Test_vmSpecSetupUefiSettings(t *testing.T) {
@@ -79,7 +83,7 @@ func Test_NewVirtualMachineSpec(t *testing.T) {
 				Name:     "basic-vm",
 				Hardware: *NewHardware(4, 2, 8192),
 			},
-			expectedCPUCores:   4,
+			expectedCPUCores:   2,
 			expectedCPUSockets: 2,
 			expectedMemory:     "8192M",
 		},
@@ -89,8 +93,8 @@ func Test_NewVirtualMachineSpec(t *testing.T) {
 				Name:     "high-performance-vm",
 				Hardware: *NewHardware(64, 32, 65536),
 			},
-			expectedCPUCores:   64,
-			expectedCPUSockets: 32,
+			expectedCPUCores:   32,
+			expectedCPUSockets: 2,
 			expectedMemory:     "65536M",
 		},
 		{
@@ -113,11 +117,131 @@ func Test_NewVirtualMachineSpec(t *testing.T) {
 		assert.Equal(ptr.Deref(vmSpec.Template.Spec.EvictionStrategy, ""), kubevirtv1.EvictionStrategyLiveMigrateIfPossible, "expected EvictionStrategy to match")
 		assert.Equal(vmSpec.Template.ObjectMeta.Labels["harvesterhci.io/vmName"], tc.config.Name, "expected VM Name to match")
 		assert.Equal(vmSpec.Template.Spec.Domain.CPU.Cores, tc.expectedCPUCores, "expected CPU cores to match")
-		assert.Equal(vmSpec.Template.Spec.Domain.CPU.Sockets, tc.expectedCPUSockets, "expected CPU cores to match")
+		assert.Equal(vmSpec.Template.Spec.Domain.CPU.Sockets, tc.expectedCPUSockets, "expected CPU sockets to match")
 		assert.Equal(vmSpec.Template.Spec.Domain.CPU.Threads, uint32(1), "expected CPU threads to match")
 		assert.Equal(vmSpec.Template.Spec.Domain.Memory.Guest.String(), tc.expectedMemory, "expected memory to match")
 		assert.Equal(vmSpec.Template.Spec.Domain.Resources.Limits.Memory().String(), tc.expectedMemory, "expected memory limit to match")
-		assert.Equal(vmSpec.Template.Spec.Domain.Resources.Limits.Cpu().Value(), int64(tc.expectedCPUCores), "expected CPU limit to match")
+		assert.Equal(vmSpec.Template.Spec.Domain.Resources.Limits.Cpu().Value(), int64(tc.config.Hardware.NumCPU), "expected CPU limit to match")
 		assert.Equal(vmSpec.Template.Spec.Domain.Features.ACPI.Enabled, ptr.To(true), "expected ACPI to be enabled")
 	}
 }
+
+func Test_CPUTopology(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc     string
+		hw       Hardware
+		expected CPUTopology
+	}{
+		{
+			desc:     "Cores per socket",
+			hw:       Hardware{NumCPU: 8, NumCoresPerSocket: 4},
+			expected: CPUTopology{Sockets: 2, Cores: 4, Threads: 1},
+		},
+		{
+			desc:     "One socket per vCPU by default",
+			hw:       Hardware{NumCPU: 4},
+			expected: CPUTopology{Sockets: 4, Cores: 1, Threads: 1},
+		},
+		{
+			desc:     "Sockets only",
+			hw:       Hardware{NumCPU: 8, NumSockets: 2},
+			expected: CPUTopology{Sockets: 2, Cores: 4, Threads: 1},
+		},
+		{
+			desc:     "Sockets, cores and threads",
+			hw:       Hardware{NumCPU: 16, NumSockets: 2, NumCoresPerSocket: 4, NumThreadsPerCore: 2},
+			expected: CPUTopology{Sockets: 2, Cores: 4, Threads: 2},
+		},
+		{
+			desc:     "Threads only",
+			hw:       Hardware{NumCPU: 8, NumThreadsPerCore: 2},
+			expected: CPUTopology{Sockets: 4, Cores: 1, Threads: 2},
+		},
+		{
+			desc:     "Cores per socket that do not divide the vCPUs",
+			hw:       Hardware{NumCPU: 6, NumCoresPerSocket: 4},
+			expected: CPUTopology{Sockets: 6, Cores: 1, Threads: 1},
+		},
+		{
+			desc:     "Topology with more vCPUs than the source",
+			hw:       Hardware{NumCPU: 8, NumSockets: 4, NumCoresPerSocket: 4},
+			expected: CPUTopology{Sockets: 8, Cores: 1, Threads: 1},
+		},
+		{
+			desc:     "More cores per socket than vCPUs",
+			hw:       Hardware{NumCPU: 2, NumCoresPerSocket: 4},
+			expected: CPUTopology{Sockets: 2, Cores: 1, Threads: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		topology := tc.hw.CPUTopology()
+		assert.Equal(tc.expected, topology, tc.desc)
+		assert.Equal(max(tc.hw.NumCPU, 1), topology.Sockets*topology.Cores*topology.Threads, "%s: expected no vCPU inflation", tc.desc)
+	}
+}
+
+func Test_ApplyCPUSettings(t *testing.T) {
+	assert := require.New(t)
+	hw := Hardware{NumCPU: 4, MemoryMB: 4096}
+	cpu := CPUSettings{
+		Features:          []kubevirtv1.CPUFeature{{Name: "vmx", Policy: "disable"}},
+		DedicatedCPUs:     true,
+		NUMANodes:         2,
+		CPUReservation:    ptr.To(resource.MustParse("1500m")),
+		CPULimit:          ptr.To(resource.MustParse("8")),
+		MemoryReservation: ptr.To(resource.MustParse("2048M")),
+	}
+
+	// Nothing is carried over by default.
+	vmSpec := NewVirtualMachineSpec(VirtualMachineSpecConfig{Name: "test", Hardware: hw})
+	ApplyCPUSettings(vmSpec, &migration.VirtualMachineImport{}, cpu)
+	assert.Empty(vmSpec.Template.Spec.Domain.CPU.Model)
+	assert.Empty(vmSpec.Template.Spec.Domain.CPU.Features)
+	assert.False(vmSpec.Template.Spec.Domain.CPU.DedicatedCPUPlacement)
+	assert.Nil(vmSpec.Template.Spec.Domain.CPU.NUMA)
+	assert.Empty(vmSpec.Template.Spec.Domain.Resources.Requests)
+
+	vm := &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			CPU: &migration.CPUOptions{
+				Model:                "host-passthrough",
+				PreserveFeatures:     ptr.To(true),
+				PreservePlacement:    ptr.To(true),
+				PreserveReservations: ptr.To(true),
+			},
+		},
+	}
+	vmSpec = NewVirtualMachineSpec(VirtualMachineSpecConfig{Name: "test", Hardware: hw})
+	ApplyCPUSettings(vmSpec, vm, cpu)
+	domain := vmSpec.Template.Spec.Domain
+	assert.Equal("host-passthrough", domain.CPU.Model)
+	assert.Equal(cpu.Features, domain.CPU.Features)
+	assert.True(domain.CPU.DedicatedCPUPlacement)
+	assert.NotNil(domain.CPU.NUMA)
+	assert.NotNil(domain.CPU.NUMA.GuestMappingPassthrough)
+	assert.Equal("2Mi", domain.Memory.Hugepages.PageSize)
+	assert.Equal(resource.MustParse("4"), domain.Resources.Limits[corev1.ResourceCPU])
+	assert.NotContains(domain.Resources.Requests, corev1.ResourceCPU, "expected no CPU request for dedicated CPUs")
+	assert.NotContains(domain.Resources.Requests, corev1.ResourceMemory, "expected no memory request below the limit for dedicated CPUs")
+
+	// CPU reservations and limits apply to shared CPUs.
+	cpu.DedicatedCPUs = false
+	cpu.CPULimit = ptr.To(resource.MustParse("2"))
+	vmSpec = NewVirtualMachineSpec(VirtualMachineSpecConfig{Name: "test", Hardware: hw})
+	ApplyCPUSettings(vmSpec, vm, cpu)
+	domain = vmSpec.Template.Spec.Domain
+	assert.False(domain.CPU.DedicatedCPUPlacement)
+	assert.Nil(domain.CPU.NUMA)
+	assert.Nil(domain.Memory.Hugepages)
+	assert.Equal(resource.MustParse("2"), domain.Resources.Limits[corev1.ResourceCPU])
+	assert.Equal(resource.MustParse("1500m"), domain.Resources.Requests[corev1.ResourceCPU])
+	assert.Equal(resource.MustParse("2048M"), domain.Resources.Requests[corev1.ResourceMemory])
+
+	// Reservations never exceed the limits of the imported VM.
+	cpu.MemoryReservation = ptr.To(resource.MustParse("8192M"))
+	vmSpec = NewVirtualMachineSpec(VirtualMachineSpecConfig{Name: "test", Hardware: hw})
+	ApplyCPUSettings(vmSpec, vm, cpu)
+	assert.Equal(resource.MustParse("4096M"), vmSpec.Template.Spec.Domain.Resources.Requests[corev1.ResourceMemory], "expected the memory request to be capped")
+}
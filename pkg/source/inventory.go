@@ -0,0 +1,35 @@
+package source
+
+import (
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// InventoryFirmware returns the firmware type of the given firmware settings
+// as used in the inventory of a source.
+func InventoryFirmware(fw *Firmware) string {
+	if fw == nil {
+		return ""
+	}
+	if fw.UEFI {
+		return migration.FirmwareEFI
+	}
+	return migration.FirmwareBIOS
+}
+
+// InventoryNetworkInterfaces converts the network information of a VM into
+// the network interfaces of the inventory of a source.
+func InventoryNetworkInterfaces(networkInfos []NetworkInfo) []migration.InventoryNetworkInterface {
+	if len(networkInfos) == 0 {
+		return nil
+	}
+
+	result := make([]migration.InventoryNetworkInterface, 0, len(networkInfos))
+	for _, ni := range networkInfos {
+		result = append(result, migration.InventoryNetworkInterface{
+			MACAddress: ni.MAC,
+			Network:    ni.NetworkName,
+			Model:      ni.Model,
+		})
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_InventoryFirmware(t *testing.T) {
+	assert := require.New(t)
+	assert.Empty(InventoryFirmware(nil))
+	assert.Equal(migration.FirmwareBIOS, InventoryFirmware(NewFirmware(false, false, false)))
+	assert.Equal(migration.FirmwareEFI, InventoryFirmware(NewFirmware(true, true, true)))
+}
+
+func Test_InventoryNetworkInterfaces(t *testing.T) {
+	assert := require.New(t)
+	assert.Nil(InventoryNetworkInterfaces(nil))
+
+	result := InventoryNetworkInterfaces([]NetworkInfo{
+		{
+			NetworkName:   "VM Network",
+			MAC:           "00:50:56:9a:33:12",
+			MappedNetwork: "default/vlan1",
+			Model:         migration.NetworkInterfaceModelVirtio,
+		},
+	})
+	assert.Equal([]migration.InventoryNetworkInterface{
+		{
+			MACAddress: "00:50:56:9a:33:12",
+			Network:    "VM Network",
+			Model:      migration.NetworkInterfaceModelVirtio,
+		},
+	}, result)
+}
@@ -0,0 +1,78 @@
+package source
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+// AnnotationDescription is the annotation that Harvester shows as the
+// description of a VM.
+const AnnotationDescription = "field.cattle.io/description"
+
+// The key of the description and the key prefixes of the other metadata of
+// a source VM.
+const (
+	MetadataKeyDescription  = "description"
+	MetadataPrefixTag       = "tag/"
+	MetadataPrefixAttribute = "attribute/"
+	MetadataPrefixMetadata  = "metadata/"
+	MetadataPrefixProperty  = "property/"
+)
+
+// metadataKeyPrefix is the prefix of the labels and annotations that hold
+// the metadata of the source VM.
+const metadataKeyPrefix = "source.migration.harvesterhci.io/"
+
+// Metadata are the description, tags, custom attributes and properties of
+// a source VM by key, e.g. `tag/<category>` for a VMware tag or
+// `metadata/<key>` for an OpenStack server metadata item.
+type Metadata map[string]string
+
+// MetadataKey returns the name of the label or annotation for the given key
+// of the metadata of a source VM, e.g. `source.migration.harvesterhci.io/tag.env`
+// for `tag/env`. The result is empty if a part of the key does not contain
+// any valid characters.
+func MetadataKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = util.SanitizeLabelValue(part)
+		if parts[i] == "" {
+			return ""
+		}
+	}
+	return metadataKeyPrefix + util.SanitizeLabelValue(strings.Join(parts, "."))
+}
+
+// ApplyMetadata adds the metadata of the source VM to the VM as labels or
+// annotations according to the metadata mapping of the import. The
+// description is added as the description annotation of Harvester. Label
+// values are sanitized, keys that do not contain any valid characters are
+// dropped.
+func ApplyMetadata(newVM *kubevirtv1.VirtualMachine, vm *migration.VirtualMachineImport, md Metadata) {
+	for _, key := range slices.Sorted(maps.Keys(md)) {
+		value := md[key]
+		switch util.GetMetadataTarget(vm.Spec.MetadataMapping, key) {
+		case migration.MetadataTargetAnnotation:
+			if key == MetadataKeyDescription {
+				if value != "" {
+					metav1.SetMetaDataAnnotation(&newVM.ObjectMeta, AnnotationDescription, value)
+				}
+				continue
+			}
+			if name := MetadataKey(key); name != "" {
+				metav1.SetMetaDataAnnotation(&newVM.ObjectMeta, name, value)
+			}
+		case migration.MetadataTargetLabel:
+			if name := MetadataKey(key); name != "" {
+				metav1.SetMetaDataLabel(&newVM.ObjectMeta, name, util.SanitizeLabelValue(value))
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_ApplyMetadata(t *testing.T) {
+	assert := require.New(t)
+	md := Metadata{
+		MetadataKeyDescription:        "Web server",
+		MetadataPrefixTag + "env":     "prod",
+		MetadataPrefixMetadata + "ou": "Team A/B",
+		MetadataPrefixTag + "äöü":     "",
+	}
+
+	// All metadata is added as annotations by default.
+	newVM := &kubevirtv1.VirtualMachine{}
+	ApplyMetadata(newVM, &migration.VirtualMachineImport{}, md)
+	assert.Equal(map[string]string{
+		AnnotationDescription:                          "Web server",
+		"source.migration.harvesterhci.io/tag.env":     "prod",
+		"source.migration.harvesterhci.io/metadata.ou": "Team A/B",
+	}, newVM.Annotations)
+	assert.Empty(newVM.Labels)
+
+	vm := &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			MetadataMapping: []migration.MetadataMapping{
+				{SourceKey: "metadata/*", Target: ptr.To(migration.MetadataTargetLabel)},
+				{SourceKey: "tag/*", Target: ptr.To(migration.MetadataTargetLabel)},
+			},
+		},
+	}
+	newVM = &kubevirtv1.VirtualMachine{}
+	ApplyMetadata(newVM, vm, md)
+	assert.Empty(newVM.Annotations)
+	assert.Equal(map[string]string{
+		"source.migration.harvesterhci.io/tag.env":     "prod",
+		"source.migration.harvesterhci.io/metadata.ou": "Team-A-B",
+	}, newVM.Labels)
+}
@@ -3,9 +3,11 @@ package source
 import (
 	"fmt"
 
+	"k8s.io/utils/ptr"
 	kubevirt "kubevirt.io/api/core/v1"
 
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
 )
 
 type NetworkInfo struct {
@@ -13,24 +15,155 @@ type NetworkInfo struct {
 	MAC           string
 	MappedNetwork string
 	Model         string
+
+	// PodNetwork is set if the network interface is attached to the pod
+	// network instead of the mapped network.
+	PodNetwork bool
+
+	// BootOrder is the position of the network interface in the boot order
+	// of the source VM, starting at 1, or 0 if it is not booted from.
+	BootOrder int32
 }
 
-func MapNetworks(networkInfos []NetworkInfo, networkMappings []migration.NetworkMapping) []NetworkInfo {
-	result := make([]NetworkInfo, 0)
+// podNetworkName is the name of the pod network in the VM spec.
+const podNetworkName = "pod-network"
+
+// dropped reports whether the network interface is not attached to any
+// network.
+func (ni NetworkInfo) dropped() bool {
+	return ni.MappedNetwork == "" && !ni.PodNetwork
+}
+
+// bootOrder returns the boot order of the network interface in the VM
+// spec, or nil if it is not booted from.
+func (ni NetworkInfo) bootOrder() *uint {
+	if ni.BootOrder < 1 {
+		return nil
+	}
+	return ptr.To(uint(ni.BootOrder))
+}
+
+// networkMappingMatcher matches source networks against a list of network
+// mappings. The patterns of the mappings are compiled once per mapping pass
+// instead of once per network interface.
+type networkMappingMatcher struct {
+	mappings []migration.NetworkMapping
+	matchers []util.SourceNetworkMatcher
+}
+
+func newNetworkMappingMatcher(networkMappings []migration.NetworkMapping) *networkMappingMatcher {
+	m := &networkMappingMatcher{
+		mappings: networkMappings,
+		matchers: make([]util.SourceNetworkMatcher, 0, len(networkMappings)),
+	}
+	for _, nm := range networkMappings {
+		m.matchers = append(m.matchers, util.NewSourceNetworkMatcher(nm))
+	}
+	return m
+}
+
+// match returns the first network mapping that matches the given source
+// network, or nil if there is none.
+func (m *networkMappingMatcher) match(networkName string) *migration.NetworkMapping {
+	for i, matches := range m.matchers {
+		if matches(networkName) {
+			return &m.mappings[i]
+		}
+	}
+	return nil
+}
+
+// applyNetworkMapping attaches the network interface to the destination
+// network of the given mapping.
+func applyNetworkMapping(ni NetworkInfo, nm *migration.NetworkMapping) NetworkInfo {
+	ni.MappedNetwork = nm.DestinationNetwork
+
+	// Override the auto-detected interface model if it is
+	// customized by the user via the `NetworkMapping`.
+	if nm.NetworkInterfaceModel != nil {
+		ni.Model = nm.GetNetworkInterfaceModel()
+	}
+
+	return ni
+}
+
+// MapNetworkInterfaces maps all network interfaces of the source VM
+// according to the network mappings of the given import. Network interfaces
+// that are not matched by a mapping are attached to the default destination
+// network. If that is not set, the `onUnmapped` policy of the import is
+// applied. Dropped network interfaces are part of the result, but they are
+// not attached to any network.
+func MapNetworkInterfaces(networkInfos []NetworkInfo, vm *migration.VirtualMachineImport) ([]NetworkInfo, error) {
+	result := make([]NetworkInfo, 0, len(networkInfos))
+	matcher := newNetworkMappingMatcher(vm.GetNetworkMapping())
+	podNetwork := false
 
 	for _, ni := range networkInfos {
-		for _, nm := range networkMappings {
-			if nm.SourceNetwork == ni.NetworkName {
-				ni.MappedNetwork = nm.DestinationNetwork
-
-				// Override the auto-detected interface model if it is
-				// customized by the user via the `NetworkMapping`.
-				if nm.NetworkInterfaceModel != nil {
-					ni.Model = nm.GetNetworkInterfaceModel()
-				}
-
-				result = append(result, ni)
-			}
+		if nm := matcher.match(ni.NetworkName); nm != nil {
+			result = append(result, applyNetworkMapping(ni, nm))
+			continue
+		}
+
+		if vm.Spec.DefaultDestinationNetwork != "" {
+			ni.MappedNetwork = vm.Spec.DefaultDestinationNetwork
+			result = append(result, ni)
+			continue
+		}
+
+		switch vm.GetOnUnmapped() {
+		case migration.UnmappedNetworkFail:
+			return nil, fmt.Errorf("%w: source network '%s' of the network interface '%s' is not mapped",
+				util.ErrInvalidNetworkMapping, ni.NetworkName, ni.MAC)
+		case migration.UnmappedNetworkPodNetwork:
+			// A VM can only be attached to the pod network once.
+			ni.PodNetwork = !podNetwork
+			podNetwork = true
+		}
+		result = append(result, ni)
+	}
+
+	return result, nil
+}
+
+// NetworkInterfaceMappings converts the mapped network interfaces into the
+// network interface list of the import status.
+func NetworkInterfaceMappings(networkInfos []NetworkInfo) []migration.NetworkInterfaceMapping {
+	if len(networkInfos) == 0 {
+		return nil
+	}
+
+	result := make([]migration.NetworkInterfaceMapping, 0, len(networkInfos))
+	for _, ni := range networkInfos {
+		nim := migration.NetworkInterfaceMapping{
+			MACAddress:    ni.MAC,
+			SourceNetwork: ni.NetworkName,
+			BootOrder:     ni.BootOrder,
+		}
+		if ni.PodNetwork {
+			nim.DestinationNetwork = podNetworkName
+		} else {
+			nim.DestinationNetwork = ni.MappedNetwork
+		}
+		result = append(result, nim)
+	}
+	return result
+}
+
+// UnmappedNetworks returns the names of the source networks that are used by
+// the given network interfaces but have no network mapping. Each name is
+// only returned once.
+func UnmappedNetworks(networkInfos []NetworkInfo, networkMappings []migration.NetworkMapping) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	matcher := newNetworkMappingMatcher(networkMappings)
+
+	for _, ni := range networkInfos {
+		if seen[ni.NetworkName] {
+			continue
+		}
+		seen[ni.NetworkName] = true
+		if matcher.match(ni.NetworkName) == nil {
+			result = append(result, ni.NetworkName)
 		}
 	}
 
@@ -41,20 +174,44 @@ func GenerateNetworkInterfaceConfigs(networkInfos []NetworkInfo, defaultNetworkI
 	networks := make([]kubevirt.Network, 0, len(networkInfos))
 	interfaces := make([]kubevirt.Interface, 0, len(networkInfos))
 
-	for i, ni := range networkInfos {
+	for _, ni := range networkInfos {
+		switch {
+		case ni.dropped():
+			continue
+		case ni.PodNetwork:
+			networks = append(networks, kubevirt.Network{
+				Name: podNetworkName,
+				NetworkSource: kubevirt.NetworkSource{
+					Pod: &kubevirt.PodNetwork{},
+				},
+			})
+			interfaces = append(interfaces, kubevirt.Interface{
+				Name:       podNetworkName,
+				MacAddress: ni.MAC,
+				Model:      ni.Model,
+				BootOrder:  ni.bootOrder(),
+				InterfaceBindingMethod: kubevirt.InterfaceBindingMethod{
+					Masquerade: &kubevirt.InterfaceMasquerade{},
+				},
+			})
+			continue
+		}
+
+		name := fmt.Sprintf("migrated-%d", len(networks))
 		networks = append(networks, kubevirt.Network{
 			NetworkSource: kubevirt.NetworkSource{
 				Multus: &kubevirt.MultusNetwork{
 					NetworkName: ni.MappedNetwork,
 				},
 			},
-			Name: fmt.Sprintf("migrated-%d", i),
+			Name: name,
 		})
 
 		interfaces = append(interfaces, kubevirt.Interface{
-			Name:       fmt.Sprintf("migrated-%d", i),
+			Name:       name,
 			MacAddress: ni.MAC,
 			Model:      ni.Model,
+			BootOrder:  ni.bootOrder(),
 			InterfaceBindingMethod: kubevirt.InterfaceBindingMethod{
 				Bridge: &kubevirt.InterfaceBridge{},
 			},
@@ -65,13 +222,13 @@ func GenerateNetworkInterfaceConfigs(networkInfos []NetworkInfo, defaultNetworkI
 	// be booted up.
 	if len(networks) == 0 {
 		networks = append(networks, kubevirt.Network{
-			Name: "pod-network",
+			Name: podNetworkName,
 			NetworkSource: kubevirt.NetworkSource{
 				Pod: &kubevirt.PodNetwork{},
 			},
 		})
 		interfaces = append(interfaces, kubevirt.Interface{
-			Name:  "pod-network",
+			Name:  podNetworkName,
 			Model: defaultNetworkInterfaceModel,
 			InterfaceBindingMethod: kubevirt.InterfaceBindingMethod{
 				Masquerade: &kubevirt.InterfaceMasquerade{},
@@ -0,0 +1,162 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+func Test_UnmappedNetworks(t *testing.T) {
+	assert := require.New(t)
+
+	networkInfos := []NetworkInfo{
+		{NetworkName: "VM Network", MAC: "00:50:56:00:00:01"},
+		{NetworkName: "vlan-100", MAC: "00:50:56:00:00:02"},
+		{NetworkName: "vlan-200", MAC: "00:50:56:00:00:03"},
+		{NetworkName: "vlan-100", MAC: "00:50:56:00:00:04"},
+	}
+	networkMappings := []migration.NetworkMapping{
+		{SourceNetwork: "VM Network", DestinationNetwork: "default/vlan1"},
+	}
+
+	assert.Equal([]string{"vlan-100", "vlan-200"}, UnmappedNetworks(networkInfos, networkMappings))
+	assert.Empty(UnmappedNetworks(networkInfos[:1], networkMappings))
+	assert.Empty(UnmappedNetworks(nil, nil))
+}
+
+func Test_MapNetworkInterfaces(t *testing.T) {
+	assert := require.New(t)
+
+	networkInfos := []NetworkInfo{
+		{NetworkName: "VM Network", MAC: "00:50:56:00:00:01", Model: migration.NetworkInterfaceModelVirtio},
+		{NetworkName: "vlan-100", MAC: "00:50:56:00:00:02", Model: migration.NetworkInterfaceModelVirtio},
+		{NetworkName: "backup", MAC: "00:50:56:00:00:03", Model: migration.NetworkInterfaceModelE1000},
+		{NetworkName: "storage", MAC: "00:50:56:00:00:04", Model: migration.NetworkInterfaceModelE1000},
+	}
+
+	testCases := []struct {
+		desc        string
+		vm          *migration.VirtualMachineImport
+		expected    []string
+		expectError bool
+	}{
+		{
+			desc: "Drop unmapped network interfaces",
+			vm: &migration.VirtualMachineImport{
+				Spec: migration.VirtualMachineImportSpec{
+					Mapping: []migration.NetworkMapping{
+						{SourceNetwork: "VM Network", DestinationNetwork: "default/mgmt"},
+						{SourceNetwork: "vlan-*", DestinationNetwork: "default/vlan", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+					},
+				},
+			},
+			expected: []string{"default/mgmt", "default/vlan", "", ""},
+		},
+		{
+			desc: "Attach unmapped network interfaces to the default destination network",
+			vm: &migration.VirtualMachineImport{
+				Spec: migration.VirtualMachineImportSpec{
+					Mapping: []migration.NetworkMapping{
+						{SourceNetwork: "VM Network", DestinationNetwork: "default/mgmt"},
+						{SourceNetwork: "vlan-*", DestinationNetwork: "default/vlan", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+					},
+					DefaultDestinationNetwork: "default/other",
+					OnUnmapped:                ptr.To(migration.UnmappedNetworkFail),
+				},
+			},
+			expected: []string{"default/mgmt", "default/vlan", "default/other", "default/other"},
+		},
+		{
+			desc: "Attach the first unmapped network interface to the pod network",
+			vm: &migration.VirtualMachineImport{
+				Spec: migration.VirtualMachineImportSpec{
+					Mapping: []migration.NetworkMapping{
+						{SourceNetwork: "VM Network", DestinationNetwork: "default/mgmt"},
+						{SourceNetwork: "vlan-*", DestinationNetwork: "default/vlan", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+					},
+					OnUnmapped: ptr.To(migration.UnmappedNetworkPodNetwork),
+				},
+			},
+			expected: []string{"default/mgmt", "default/vlan", podNetworkName, ""},
+		},
+		{
+			desc: "Fail on unmapped network interfaces",
+			vm: &migration.VirtualMachineImport{
+				Spec: migration.VirtualMachineImportSpec{
+					Mapping: []migration.NetworkMapping{
+						{SourceNetwork: "VM Network", DestinationNetwork: "default/mgmt"},
+						{SourceNetwork: "vlan-*", DestinationNetwork: "default/vlan", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+					},
+					OnUnmapped: ptr.To(migration.UnmappedNetworkFail),
+				},
+			},
+			expectError: true,
+		},
+		{
+			desc: "Generated network mappings are used",
+			vm: &migration.VirtualMachineImport{
+				Spec: migration.VirtualMachineImportSpec{
+					Mapping: []migration.NetworkMapping{
+						{SourceNetwork: "VM Network", DestinationNetwork: "default/mgmt"},
+						{SourceNetwork: "vlan-*", DestinationNetwork: "default/vlan", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+					},
+				},
+				Status: migration.VirtualMachineImportStatus{
+					GeneratedNetworkMapping: []migration.NetworkMapping{
+						{SourceNetwork: "backup", DestinationNetwork: "default/mgmt-vlan10"},
+					},
+				},
+			},
+			expected: []string{"default/mgmt", "default/vlan", "default/mgmt-vlan10", ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		result, err := MapNetworkInterfaces(networkInfos, tc.vm)
+		if tc.expectError {
+			assert.ErrorIs(err, util.ErrInvalidNetworkMapping, tc.desc)
+			continue
+		}
+		assert.NoError(err, tc.desc)
+
+		statuses := NetworkInterfaceMappings(result)
+		assert.Len(statuses, len(networkInfos), tc.desc)
+		for i, status := range statuses {
+			assert.Equal(networkInfos[i].MAC, status.MACAddress, tc.desc)
+			assert.Equal(networkInfos[i].NetworkName, status.SourceNetwork, tc.desc)
+			assert.Equal(tc.expected[i], status.DestinationNetwork, tc.desc)
+		}
+	}
+}
+
+func Test_GenerateNetworkInterfaceConfigs(t *testing.T) {
+	assert := require.New(t)
+
+	networkInfos := []NetworkInfo{
+		{NetworkName: "VM Network", MAC: "00:50:56:00:00:01", Model: migration.NetworkInterfaceModelVirtio, MappedNetwork: "default/mgmt", BootOrder: 2},
+		{NetworkName: "backup", MAC: "00:50:56:00:00:02", Model: migration.NetworkInterfaceModelE1000},
+		{NetworkName: "storage", MAC: "00:50:56:00:00:03", Model: migration.NetworkInterfaceModelE1000, PodNetwork: true},
+	}
+
+	networks, interfaces := GenerateNetworkInterfaceConfigs(networkInfos, migration.NetworkInterfaceModelVirtio)
+	assert.Len(networks, 2)
+	assert.Len(interfaces, 2)
+	assert.Equal("default/mgmt", networks[0].Multus.NetworkName)
+	assert.NotNil(interfaces[0].Bridge)
+	assert.Equal(ptr.To(uint(2)), interfaces[0].BootOrder)
+	assert.Nil(interfaces[1].BootOrder)
+	assert.Equal(podNetworkName, networks[1].Name)
+	assert.NotNil(networks[1].Pod)
+	assert.Equal("00:50:56:00:00:03", interfaces[1].MacAddress)
+	assert.NotNil(interfaces[1].Masquerade)
+
+	networks, interfaces = GenerateNetworkInterfaceConfigs(networkInfos[1:2], migration.NetworkInterfaceModelVirtio)
+	assert.Len(networks, 1)
+	assert.Len(interfaces, 1)
+	assert.NotNil(networks[0].Pod)
+	assert.Empty(interfaces[0].MacAddress)
+}
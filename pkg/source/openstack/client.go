@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,8 +21,10 @@ import (
 	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/snapshots"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/tags"
 	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/imagedata"
 	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/images"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/provider"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/v2/openstack/utils"
 	"github.com/sirupsen/logrus"
@@ -30,17 +34,35 @@ import (
 	kubevirt "kubevirt.io/api/core/v1"
 
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/metrics"
 	"github.com/harvester/vm-import-controller/pkg/server"
 	"github.com/harvester/vm-import-controller/pkg/source"
 	"github.com/harvester/vm-import-controller/pkg/util"
 )
 
 const (
-	NotUniqueName         = "notUniqueName"
-	NotServerFound        = "noServerFound"
-	pollingTimeout        = 2 * 60 * 60 * time.Second
-	annotationDescription = "field.cattle.io/description"
-	computeMicroversion   = "2.19"
+	NotUniqueName       = "notUniqueName"
+	NotServerFound      = "noServerFound"
+	pollingTimeout      = 2 * 60 * 60 * time.Second
+	computeMicroversion = "2.19"
+	// Server tags require the compute microversion 2.26.
+	tagsMicroversion = "2.26"
+)
+
+// The flavor extra specs that describe the CPU topology of an instance.
+// See https://docs.openstack.org/nova/latest/admin/cpu-topologies.html
+const (
+	extraSpecCPUSockets = "hw:cpu_sockets"
+	extraSpecCPUCores   = "hw:cpu_cores"
+	extraSpecCPUThreads = "hw:cpu_threads"
+)
+
+// The flavor extra specs that describe the CPU placement and guest NUMA
+// topology of an instance.
+const (
+	extraSpecCPUPolicy   = "hw:cpu_policy"
+	extraSpecNUMANodes   = "hw:numa_nodes"
+	extraSpecMemPageSize = "hw:mem_page_size"
 )
 
 type Client struct {
@@ -203,6 +225,195 @@ func (c *Client) Verify() error {
 	return nil
 }
 
+// Version returns the version of the Keystone identity API, e.g. "v3.14".
+func (c *Client) Version() (string, error) {
+	identityClient, err := openstack.NewIdentityV3(c.pClient, c.opts)
+	if err != nil {
+		return "", fmt.Errorf("error generating identity client: %v", err)
+	}
+
+	var body struct {
+		Version struct {
+			ID string `json:"id"`
+		} `json:"version"`
+	}
+	if _, err := identityClient.Get(c.ctx, identityClient.Endpoint, &body, nil); err != nil {
+		return "", fmt.Errorf("error fetching identity version: %v", err)
+	}
+
+	return body.Version.ID, nil
+}
+
+// ListNetworks returns the Neutron networks that are visible to the project.
+// The provider attributes, i.e. the network type and segmentation ID, are
+// only reported if the policy of the cloud allows to read them.
+func (c *Client) ListNetworks() ([]migration.SourceNetwork, error) {
+	allPgs, err := networks.List(c.networkClient, networks.ListOpts{}).AllPages(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing networks: %v", err)
+	}
+	allNetworks, err := networks.ExtractNetworks(allPgs)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting networks: %v", err)
+	}
+	var allProviderExts []provider.NetworkProviderExt
+	if err := networks.ExtractNetworksInto(allPgs, &allProviderExts); err != nil {
+		return nil, fmt.Errorf("error extracting network provider attributes: %v", err)
+	}
+
+	result := make([]migration.SourceNetwork, 0, len(allNetworks))
+	for i, n := range allNetworks {
+		sn := migration.SourceNetwork{
+			Name: n.Name,
+			ID:   n.ID,
+		}
+
+		if i < len(allProviderExts) {
+			ext := allProviderExts[i]
+			sn.Type = ext.NetworkType
+			if id, err := strconv.ParseInt(ext.SegmentationID, 10, 32); err == nil {
+				sn.SegmentationID = int32(id)
+				if ext.NetworkType == "vlan" {
+					sn.VlanID = int32(id)
+				}
+			}
+		}
+
+		result = append(result, sn)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// ListVirtualMachines returns the inventory of all servers of the project.
+// Flavors, volumes and images are fetched once for all servers to keep the
+// number of API requests low.
+func (c *Client) ListVirtualMachines() ([]migration.InventoryVirtualMachine, error) {
+	allPg, err := servers.List(c.computeClient, servers.ListOpts{}).AllPages(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers: %v", err)
+	}
+	allServers, err := servers.ExtractServers(allPg)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting servers: %v", err)
+	}
+
+	flavorPg, err := flavors.ListDetail(c.computeClient, flavors.ListOpts{AccessType: flavors.AllAccess}).AllPages(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing flavors: %v", err)
+	}
+	allFlavors, err := flavors.ExtractFlavors(flavorPg)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting flavors: %v", err)
+	}
+	flavorMap := make(map[string]flavors.Flavor, len(allFlavors))
+	for _, f := range allFlavors {
+		flavorMap[f.ID] = f
+	}
+
+	volumePg, err := volumes.List(c.storageClient, volumes.ListOpts{}).AllPages(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %v", err)
+	}
+	allVolumes, err := volumes.ExtractVolumes(volumePg)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting volumes: %v", err)
+	}
+	var allVolumeStatus []ExtendedVolume
+	if err := volumes.ExtractVolumesInto(volumePg, &allVolumeStatus); err != nil {
+		return nil, fmt.Errorf("error extracting volume status: %v", err)
+	}
+	volumeMap := make(map[string]volumes.Volume, len(allVolumes))
+	volumeImageMap := make(map[string]string, len(allVolumes))
+	for i, v := range allVolumes {
+		volumeMap[v.ID] = v
+		if i < len(allVolumeStatus) {
+			volumeImageMap[v.ID] = allVolumeStatus[i].VolumeImageMetadata["image_id"]
+		}
+	}
+
+	imagePg, err := images.List(c.imageClient, images.ListOpts{}).AllPages(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing images: %v", err)
+	}
+	allImages, err := images.ExtractImages(imagePg)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting images: %v", err)
+	}
+	imageMap := make(map[string]images.Image, len(allImages))
+	for _, i := range allImages {
+		imageMap[i.ID] = i
+	}
+
+	result := make([]migration.InventoryVirtualMachine, 0, len(allServers))
+	for _, s := range allServers {
+		ivm := migration.InventoryVirtualMachine{
+			Name:       s.Name,
+			ID:         s.ID,
+			Folder:     s.TenantID,
+			PowerState: s.Status,
+		}
+
+		if flavorID, ok := s.Flavor["id"].(string); ok {
+			if f, ok := flavorMap[flavorID]; ok {
+				ivm.CPUs = int32(f.VCPUs) // nolint:gosec
+				ivm.MemoryMiB = int64(f.RAM)
+			}
+		}
+
+		// The image of the boot volume determines the firmware and guest OS.
+		var imageID string
+		for _, av := range s.AttachedVolumes {
+			v, ok := volumeMap[av.ID]
+			if !ok {
+				continue
+			}
+			name := v.Name
+			if name == "" {
+				name = v.ID
+			}
+			ivm.Disks = append(ivm.Disks, migration.InventoryDisk{
+				Name:      name,
+				SizeBytes: int64(v.Size) * 1024 * 1024 * 1024,
+			})
+			if v.Bootable == "true" {
+				imageID = volumeImageMap[v.ID]
+			}
+		}
+		if imageID == "" {
+			imageID, _ = s.Image["id"].(string)
+		}
+		if img, ok := imageMap[imageID]; ok {
+			ivm.Firmware = migration.FirmwareBIOS
+			if firmwareType, ok := img.Properties["hw_firmware_type"]; ok && firmwareType == "uefi" {
+				ivm.Firmware = migration.FirmwareEFI
+			}
+			ivm.GuestOS = img.Name
+			if distro, ok := img.Properties["os_distro"].(string); ok && distro != "" {
+				ivm.GuestOS = distro
+			}
+		}
+
+		// The interface model is not provided via the OpenStack Nova API.
+		networkInfos, err := generateNetworkInfos(s.Addresses, "")
+		if err != nil {
+			return nil, fmt.Errorf("error generating network infos of server %s: %w", s.ID, err)
+		}
+		sort.Slice(networkInfos, func(i, j int) bool {
+			return networkInfos[i].MAC < networkInfos[j].MAC
+		})
+		ivm.NetworkInterfaces = source.InventoryNetworkInterfaces(networkInfos)
+
+		result = append(result, ivm)
+	}
+
+	return result, nil
+}
+
 func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error) {
 	if ptr.Deref(vm.Spec.ForcePowerOff, false) {
 		logrus.WithFields(logrus.Fields{
@@ -214,6 +425,11 @@ func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error)
 
 	// Check the source network mappings.
 	for _, nm := range vm.Spec.Mapping {
+		// Patterns do not have to match any network.
+		if nm.GetMatchType() != migration.NetworkMatchTypeExact {
+			continue
+		}
+
 		logrus.WithFields(logrus.Fields{
 			"name":          vm.Name,
 			"namespace":     vm.Namespace,
@@ -230,7 +446,7 @@ func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error)
 			return fmt.Errorf("error while checking if pages were empty during querying source network '%s': %v", nm.SourceNetwork, err)
 		}
 		if ok {
-			return fmt.Errorf("source network '%s' not found", nm.SourceNetwork)
+			return fmt.Errorf("%w: '%s'", util.ErrSourceNetworkNotFound, nm.SourceNetwork)
 		}
 	}
 
@@ -252,12 +468,24 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) error
 	// Helper function to do the export.
 	// This is necessary so that the defer functions are executed at the right
 	// time.
-	exportFn := func(index int, av servers.AttachedVolume) error {
+	exportFn := func(di migration.DiskInfo) error {
 		var snapshot *snapshots.Snapshot
 		var volume *volumes.Volume
 		var volumeImage volumes.VolumeImage
 
-		imageName := fmt.Sprintf("import-controller-%s-%d", vm.Spec.VirtualMachineName, index)
+		if util.IsDiskSkipped(vm, di) {
+			logrus.WithFields(logrus.Fields{
+				"name":                    vm.Name,
+				"namespace":               vm.Namespace,
+				"spec.virtualMachineName": vm.Spec.VirtualMachineName,
+				"volume.id":               di.VolumeID,
+				"volume.type":             di.VolumeType,
+				"index":                   di.Index,
+			}).Info("Skipping a volume that is excluded from the import")
+			return nil
+		}
+
+		imageName := fmt.Sprintf("import-controller-%s-%d", vm.Spec.VirtualMachineName, di.Index)
 
 		// Make sure the snapshot, volume and volume image are cleaned up in any case.
 		defer func() {
@@ -311,13 +539,13 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) error
 			"namespace":               vm.Namespace,
 			"spec.virtualMachineName": vm.Spec.VirtualMachineName,
 			"opts.name":               imageName,
-			"opts.volumeID":           av.ID,
+			"opts.volumeID":           di.VolumeID,
 		}).Info("Creating a new snapshot")
 
 		// create snapshot for volume
 		snapshot, err = snapshots.Create(c.ctx, c.storageClient, snapshots.CreateOpts{
 			Name:     imageName,
-			VolumeID: av.ID,
+			VolumeID: di.VolumeID,
 			Force:    true,
 		}).Extract()
 		// snapshot creation is async, so call returns a 202 error when successful.
@@ -440,7 +668,7 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) error
 			return fmt.Errorf("error downloading image %s: %w", volumeImage.ImageID, err)
 		}
 
-		rawImageFileName := generateRawImageFileName(vm.Status.ImportedVirtualMachineName, index)
+		rawImageFileName := generateRawImageFileName(vm.Status.ImportedVirtualMachineName, int(di.Index))
 
 		logrus.WithFields(logrus.Fields{
 			"name":                    vm.Name,
@@ -455,18 +683,21 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) error
 			return fmt.Errorf("error downloading RAW image %s: %w", rawImageFileName, err)
 		}
 
-		vm.Status.DiskImportStatus = append(vm.Status.DiskImportStatus, migration.DiskInfo{
-			Name:          rawImageFileName,
-			DiskSize:      int64(volume.Size),
-			DiskLocalPath: server.TempDir(),
-			BusType:       vm.GetDefaultDiskBusType(),
-		})
+		di.Name = rawImageFileName
+		di.DiskSize = int64(volume.Size)
+		di.DiskLocalPath = server.TempDir()
+		vm.Status.DiskImportStatus = append(vm.Status.DiskImportStatus, di)
 
 		return nil
 	}
 
-	for index, av := range vmObj.AttachedVolumes {
-		err := exportFn(index, av)
+	diskInfos, err := c.generateDiskInfos(vmObj, vm.GetDefaultDiskBusType())
+	if err != nil {
+		return err
+	}
+
+	for _, di := range diskInfos {
+		err := exportFn(di)
 		if err != nil {
 			return err
 		}
@@ -518,6 +749,88 @@ func (c *Client) IsPoweredOff(vm *migration.VirtualMachineImport) (bool, error)
 	return false, nil
 }
 
+// GetNetworkInfos returns the network interfaces of the source VM.
+func (c *Client) GetNetworkInfos(vm *migration.VirtualMachineImport) ([]source.NetworkInfo, error) {
+	vmObj, err := c.findVM(vm.Spec.VirtualMachineName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
+	}
+
+	return generateNetworkInfos(vmObj.Addresses, vm.GetDefaultNetworkInterfaceModel())
+}
+
+// GetDiskInfos returns the disks of the source VM.
+func (c *Client) GetDiskInfos(vm *migration.VirtualMachineImport) ([]migration.DiskInfo, error) {
+	vmObj, err := c.findVM(vm.Spec.VirtualMachineName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
+	}
+
+	return c.generateDiskInfos(vmObj, vm.GetDefaultDiskBusType())
+}
+
+// GetVirtualMachineIdentity returns the server ID of the source VM.
+func (c *Client) GetVirtualMachineIdentity(vm *migration.VirtualMachineImport) (*migration.SourceVirtualMachineIdentity, error) {
+	vmObj, err := c.findVM(vm.Spec.VirtualMachineName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
+	}
+
+	return &migration.SourceVirtualMachineIdentity{
+		ID: vmObj.ID,
+	}, nil
+}
+
+// generateDiskInfos returns the attached volumes of the given server in the
+// order they are attached. The volume attached as root device of the server
+// is marked as boot disk and is the first in the boot order. If the root
+// device is unknown, e.g. because the policy of the cloud hides it from
+// non-admin users, the first bootable volume is used. If no volume is
+// bootable, the first volume is marked as boot disk, but no boot order is
+// recorded.
+func (c *Client) generateDiskInfos(server *ExtendedServer, defaultDiskBusType kubevirt.DiskBus) ([]migration.DiskInfo, error) {
+	dis := make([]migration.DiskInfo, 0, len(server.AttachedVolumes))
+	rootDevice := ptr.Deref(server.RootDeviceName, "")
+	bootDiskIndex := -1
+
+	for index, av := range server.AttachedVolumes {
+		volume, err := volumes.Get(c.ctx, c.storageClient, av.ID).Extract()
+		if err != nil {
+			return nil, fmt.Errorf("error getting volume %s: %w", av.ID, err)
+		}
+
+		di := migration.DiskInfo{
+			BusType:    defaultDiskBusType,
+			Index:      int32(index), // nolint:gosec
+			VolumeID:   av.ID,
+			VolumeType: volume.VolumeType,
+		}
+		for _, attachment := range volume.Attachments {
+			if attachment.ServerID == server.ID {
+				di.DeviceID = attachment.Device
+				break
+			}
+		}
+		switch {
+		case rootDevice != "" && di.DeviceID == rootDevice:
+			bootDiskIndex = len(dis)
+		case bootDiskIndex < 0 && volume.Bootable == "true":
+			bootDiskIndex = len(dis)
+		}
+
+		dis = append(dis, di)
+	}
+
+	if bootDiskIndex >= 0 {
+		dis[bootDiskIndex].BootDisk = true
+		dis[bootDiskIndex].BootOrder = 1
+	} else if len(dis) > 0 {
+		dis[0].BootDisk = true
+	}
+
+	return dis, nil
+}
+
 func (c *Client) GenerateVirtualMachine(vm *migration.VirtualMachineImport) (*kubevirt.VirtualMachine, error) {
 	vmObj, err := c.findVM(vm.Spec.VirtualMachineName)
 	if err != nil {
@@ -554,28 +867,47 @@ func (c *Client) GenerateVirtualMachine(vm *migration.VirtualMachineImport) (*ku
 		},
 	}
 
-	if vmObj.Description != "" {
-		if newVM.Annotations == nil {
-			newVM.Annotations = make(map[string]string)
-		}
-		newVM.Annotations[annotationDescription] = vmObj.Description
+	serverTags, err := c.listTags(vmObj.ID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":      vm.Name,
+			"namespace": vm.Namespace,
+		}).WithError(err).Warn("Failed to get the tags of the server, they are not added to the VM")
+	}
+	source.ApplyMetadata(newVM, vm, generateMetadata(vmObj, serverTags))
+
+	hw := source.Hardware{
+		NumCPU:   uint32(flavorObj.VCPUs), // nolint:gosec
+		MemoryMB: int64(flavorObj.RAM),    // nolint:gosec
+	}
+	extraSpecs, err := flavors.ListExtraSpecs(c.ctx, c.computeClient, flavorObj.ID).Extract()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":      vm.Name,
+			"namespace": vm.Namespace,
+			"flavor":    flavorObj.ID,
+		}).WithError(err).Warn("Failed to get the extra specs of the flavor, the CPU topology is derived from the number of vCPUs")
+	} else {
+		applyCPUTopology(&hw, extraSpecs)
 	}
 
 	vmSpec := source.NewVirtualMachineSpec(source.VirtualMachineSpecConfig{
-		Name: vm.Status.ImportedVirtualMachineName,
-		Hardware: source.Hardware{
-			NumCPU:            uint32(flavorObj.VCPUs), // nolint:gosec
-			NumCoresPerSocket: 1,
-			MemoryMB:          int64(flavorObj.RAM), // nolint:gosec
-		},
+		Name:     vm.Status.ImportedVirtualMachineName,
+		Hardware: hw,
 	})
 
-	mappedNetwork := source.MapNetworks(networkInfos, vm.Spec.Mapping)
+	mappedNetwork, err := source.MapNetworkInterfaces(networkInfos, vm)
+	if err != nil {
+		return nil, err
+	}
 	networkConfig, interfaceConfig := source.GenerateNetworkInterfaceConfigs(mappedNetwork, vm.GetDefaultNetworkInterfaceModel())
 
 	// Setup BIOS/EFI, SecureBoot and TPM settings.
 	source.ApplyFirmwareSettings(vmSpec, fw)
 
+	// Setup the CPU model, placement and guest NUMA topology.
+	source.ApplyCPUSettings(vmSpec, vm, getCPUSettings(extraSpecs))
+
 	vmSpec.Template.Spec.Networks = networkConfig
 	vmSpec.Template.Spec.Domain.Devices.Interfaces = interfaceConfig
 	newVM.Spec = *vmSpec
@@ -584,6 +916,67 @@ func (c *Client) GenerateVirtualMachine(vm *migration.VirtualMachineImport) (*ku
 	return newVM, nil
 }
 
+// listTags returns the tags of the given server.
+func (c *Client) listTags(serverID string) ([]string, error) {
+	tagsClient := *c.computeClient
+	tagsClient.Microversion = tagsMicroversion
+	return tags.List(c.ctx, &tagsClient, serverID).Extract()
+}
+
+// generateMetadata returns the description, the metadata and the tags of
+// the given server.
+func generateMetadata(server *ExtendedServer, serverTags []string) source.Metadata {
+	md := source.Metadata{}
+	if server.Description != "" {
+		md[source.MetadataKeyDescription] = server.Description
+	}
+	for k, v := range server.Metadata {
+		md[source.MetadataPrefixMetadata+k] = v
+	}
+	for _, t := range serverTags {
+		md[source.MetadataPrefixTag+t] = ""
+	}
+	return md
+}
+
+// applyCPUTopology sets the CPU topology of the hardware from the given
+// flavor extra specs. Missing and invalid values are left unset, so they
+// are derived from the number of vCPUs.
+func applyCPUTopology(hw *source.Hardware, extraSpecs map[string]string) {
+	parse := func(key string) uint32 {
+		v, err := strconv.ParseUint(extraSpecs[key], 10, 32)
+		if err != nil {
+			return 0
+		}
+		return uint32(v)
+	}
+
+	hw.NumSockets = parse(extraSpecCPUSockets)
+	hw.NumCoresPerSocket = parse(extraSpecCPUCores)
+	hw.NumThreadsPerCore = parse(extraSpecCPUThreads)
+}
+
+// getCPUSettings returns the CPU settings described by the given flavor
+// extra specs. Sizes of small pages and `any` do not require hugepages.
+func getCPUSettings(extraSpecs map[string]string) source.CPUSettings {
+	cpu := source.CPUSettings{
+		DedicatedCPUs: extraSpecs[extraSpecCPUPolicy] == "dedicated",
+	}
+
+	if v, err := strconv.ParseUint(extraSpecs[extraSpecNUMANodes], 10, 32); err == nil {
+		cpu.NUMANodes = uint32(v)
+	}
+
+	switch strings.ToLower(extraSpecs[extraSpecMemPageSize]) {
+	case "large", "2mb", "2048", "2048kb":
+		cpu.HugepageSize = "2Mi"
+	case "1gb", "1048576", "1048576kb":
+		cpu.HugepageSize = "1Gi"
+	}
+
+	return cpu
+}
+
 func (c *Client) Cleanup(vm *migration.VirtualMachineImport) error {
 	return source.RemoveTempImageFiles(vm.Status.DiskImportStatus)
 }
@@ -797,7 +1190,7 @@ func (c *Client) SanitizeVirtualMachineImport(vm *migration.VirtualMachineImport
 	if err == nil {
 		vmObj, err := c.findVM(parsedUUID.String())
 		if err != nil {
-			return err
+			return fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
 		}
 		vm.Status.ImportedVirtualMachineName = vmObj.Name
 	} else {
@@ -820,7 +1213,8 @@ func writeRawImageFile(name string, src io.ReadCloser) error {
 
 	defer dst.Close() //nolint:errcheck
 
-	_, err = io.Copy(dst, src)
+	n, err := io.Copy(dst, src)
+	metrics.AddBytesTransferred(migration.KindOpenstackSource, n)
 	return err
 }
 
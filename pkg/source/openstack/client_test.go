@@ -14,6 +14,7 @@ import (
 
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
 	"github.com/harvester/vm-import-controller/pkg/server"
+	"github.com/harvester/vm-import-controller/pkg/source"
 )
 
 var (
@@ -64,6 +65,9 @@ func Test_NewClient(t *testing.T) {
 	assert := require.New(t)
 	err := c.Verify()
 	assert.NoError(err, "expect no error during verify of client")
+	version, err := c.Version()
+	assert.NoError(err, "expect no error during lookup of identity version")
+	assert.NotEmpty(version, "expect identity version to be reported")
 }
 
 func Test_checkOrGetUUID(t *testing.T) {
@@ -74,6 +78,27 @@ func Test_checkOrGetUUID(t *testing.T) {
 	assert.NoError(err, "expected no error during checkOrGetUUID")
 }
 
+func Test_ListVirtualMachines(t *testing.T) {
+	assert := require.New(t)
+	vmName, ok := os.LookupEnv("OS_VM_NAME")
+	assert.True(ok, "expected env variable VM_NAME to be set")
+	vms, err := c.ListVirtualMachines()
+	assert.NoError(err, "expected no error during listing of servers")
+
+	var names []string
+	for _, vm := range vms {
+		names = append(names, vm.Name)
+	}
+	assert.Contains(names, vmName, "expected VM to be listed")
+}
+
+func Test_ListNetworks(t *testing.T) {
+	assert := require.New(t)
+	networks, err := c.ListNetworks()
+	assert.NoError(err, "expected no error during listing of networks")
+	assert.NotEmpty(networks, "expected networks to be listed")
+}
+
 func Test_IsPoweredOff(t *testing.T) {
 	assert := require.New(t)
 	vmName, ok := os.LookupEnv("OS_VM_NAME")
@@ -169,6 +194,57 @@ func Test_generateNetworkInfo(t *testing.T) {
 	assert.Equal(vmInterfaceDetails[1].Model, migration.NetworkInterfaceModelVirtio, "expected to have a NIC with virtio model")
 }
 
+func Test_applyCPUTopology(t *testing.T) {
+	assert := require.New(t)
+
+	hw := source.Hardware{NumCPU: 8}
+	applyCPUTopology(&hw, map[string]string{
+		extraSpecCPUSockets: "2",
+		extraSpecCPUThreads: "2",
+		"hw:cpu_policy":     "dedicated",
+	})
+	assert.Equal(source.CPUTopology{Sockets: 2, Cores: 2, Threads: 2}, hw.CPUTopology())
+
+	hw = source.Hardware{NumCPU: 4}
+	applyCPUTopology(&hw, map[string]string{extraSpecCPUCores: "foo"})
+	assert.Equal(source.CPUTopology{Sockets: 4, Cores: 1, Threads: 1}, hw.CPUTopology())
+}
+
+func Test_getCPUSettings(t *testing.T) {
+	assert := require.New(t)
+
+	cpu := getCPUSettings(map[string]string{
+		extraSpecCPUPolicy:   "dedicated",
+		extraSpecNUMANodes:   "2",
+		extraSpecMemPageSize: "1GB",
+	})
+	assert.Equal(source.CPUSettings{DedicatedCPUs: true, NUMANodes: 2, HugepageSize: "1Gi"}, cpu)
+
+	cpu = getCPUSettings(map[string]string{
+		extraSpecCPUPolicy:   "shared",
+		extraSpecMemPageSize: "small",
+	})
+	assert.Equal(source.CPUSettings{}, cpu)
+	assert.Equal(source.CPUSettings{}, getCPUSettings(nil))
+}
+
+func Test_generateMetadata(t *testing.T) {
+	assert := require.New(t)
+
+	server := &ExtendedServer{
+		Server: servers.Server{
+			Metadata: map[string]string{"owner": "team-a"},
+		},
+		ServerDescription: ServerDescription{Description: "Web server"},
+	}
+	assert.Equal(source.Metadata{
+		source.MetadataKeyDescription:           "Web server",
+		source.MetadataPrefixMetadata + "owner": "team-a",
+		source.MetadataPrefixTag + "production": "",
+	}, generateMetadata(server, []string{"production"}))
+	assert.Empty(generateMetadata(&ExtendedServer{}, nil))
+}
+
 func Test_ClientOptions(t *testing.T) {
 	assert := require.New(t)
 	assert.Equal(c.options.UploadImageRetryCount, migration.OpenstackDefaultRetryCount)
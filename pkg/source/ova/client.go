@@ -1,13 +1,16 @@
 package ova
 
 import (
+	"archive/tar"
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/sha1" // nolint:gosec
 	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/hex"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"hash"
@@ -16,10 +19,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vmware/govmomi/ovf"
@@ -32,6 +37,7 @@ import (
 	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/metrics"
 	"github.com/harvester/vm-import-controller/pkg/qemu"
 	"github.com/harvester/vm-import-controller/pkg/server"
 	"github.com/harvester/vm-import-controller/pkg/source"
@@ -64,6 +70,18 @@ type Client struct {
 	workingDir string
 }
 
+// bootOrderEnvelope contains the VMware specific boot order of an OVF
+// descriptor, which is not part of `ovf.Envelope`. Each section references
+// an item of the virtual hardware by its instance ID, in the order the VM
+// boots from them, e.g.
+// `<vmw:BootOrderSection vmw:instanceId="8" vmw:type="disk">`.
+type bootOrderEnvelope struct {
+	BootOrder []struct {
+		InstanceID string `xml:"instanceId,attr"`
+		Type       string `xml:"type,attr"`
+	} `xml:"VirtualSystem>BootOrderSection"`
+}
+
 func NewClient(ctx context.Context, url string, secret *corev1.Secret, options migration.OvaSourceOptions) (*Client, error) {
 	httpClient, err := newHttpClient(secret, options)
 	if err != nil {
@@ -110,6 +128,85 @@ func (c *Client) Verify() error {
 	return nil
 }
 
+// ListVirtualMachines returns the inventory of the virtual system described
+// by the OVF envelope of the OVA file. Only the beginning of the archive is
+// downloaded because the OVF descriptor must be its first file.
+func (c *Client) ListVirtualMachines() ([]migration.InventoryVirtualMachine, error) {
+	e, bootOrder, err := c.fetchEnvelope()
+	if err != nil {
+		return nil, err
+	}
+
+	if e.VirtualSystem == nil {
+		return []migration.InventoryVirtualMachine{}, nil
+	}
+
+	fw, hw, nis, dis := parseEnvelope(e, bootOrder, "", "")
+	ivm := migration.InventoryVirtualMachine{
+		Name:              ptr.Deref(e.VirtualSystem.Name, e.VirtualSystem.ID),
+		ID:                e.VirtualSystem.ID,
+		CPUs:              int32(hw.NumCPU), // nolint:gosec
+		MemoryMiB:         hw.MemoryMB,
+		Firmware:          source.InventoryFirmware(fw),
+		NetworkInterfaces: source.InventoryNetworkInterfaces(nis),
+	}
+	if osSection := e.VirtualSystem.OperatingSystem; osSection != nil {
+		ivm.GuestOS = ptr.Deref(osSection.Description, ptr.Deref(osSection.OSType, ""))
+	}
+	for _, di := range dis {
+		ivm.Disks = append(ivm.Disks, migration.InventoryDisk{
+			Name:      di.Name,
+			SizeBytes: di.DiskSize,
+		})
+	}
+
+	return []migration.InventoryVirtualMachine{ivm}, nil
+}
+
+// GetNetworkInfos is required by the `VirtualMachineOperations` interface.
+// The network information is read from the OVF envelope, so the OVA file
+// does not need to be downloaded completely.
+func (c *Client) GetNetworkInfos(vmi *migration.VirtualMachineImport) ([]source.NetworkInfo, error) {
+	e, bootOrder, err := c.fetchEnvelope()
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, nis, _ := parseEnvelope(e, bootOrder, vmi.GetDefaultNetworkInterfaceModel(), vmi.GetDefaultDiskBusType())
+	return nis, nil
+}
+
+// GetDiskInfos is required by the `VirtualMachineOperations` interface.
+// The disk information is read from the OVF envelope, so the OVA file does
+// not need to be downloaded completely.
+func (c *Client) GetDiskInfos(vmi *migration.VirtualMachineImport) ([]migration.DiskInfo, error) {
+	e, bootOrder, err := c.fetchEnvelope()
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, _, dis := parseEnvelope(e, bootOrder, vmi.GetDefaultNetworkInterfaceModel(), vmi.GetDefaultDiskBusType())
+	return dis, nil
+}
+
+// GetVirtualMachineIdentity is required by the `VirtualMachineOperations`
+// interface. The ID of the virtual system of the OVF envelope identifies
+// the VM.
+func (c *Client) GetVirtualMachineIdentity(_ *migration.VirtualMachineImport) (*migration.SourceVirtualMachineIdentity, error) {
+	e, _, err := c.fetchEnvelope()
+	if err != nil {
+		return nil, err
+	}
+
+	if e.VirtualSystem == nil {
+		return nil, fmt.Errorf("no virtual system found in the OVF envelope")
+	}
+
+	return &migration.SourceVirtualMachineIdentity{
+		ID: e.VirtualSystem.ID,
+	}, nil
+}
+
 // PreFlightChecks is required by the `VirtualMachineOperations` interface.
 func (c *Client) PreFlightChecks(_ *migration.VirtualMachineImport) (err error) {
 	return nil
@@ -139,12 +236,12 @@ func (c *Client) ExportVirtualMachine(vmi *migration.VirtualMachineImport) error
 		return err
 	}
 
-	e, err := readEnvelope(tempArchivePath)
+	e, bootOrder, err := readEnvelope(tempArchivePath)
 	if err != nil {
 		return fmt.Errorf("failed to read envelope: %w", err)
 	}
 
-	_, _, _, dis := parseEnvelope(e, vmi.GetDefaultNetworkInterfaceModel(), vmi.GetDefaultDiskBusType())
+	_, _, _, dis := parseEnvelope(e, bootOrder, vmi.GetDefaultNetworkInterfaceModel(), vmi.GetDefaultDiskBusType())
 	logrus.WithFields(util.FieldsToJSON(logrus.Fields{
 		"name":      vmi.Name,
 		"namespace": vmi.Namespace,
@@ -152,6 +249,17 @@ func (c *Client) ExportVirtualMachine(vmi *migration.VirtualMachineImport) error
 	}, []string{"diskInfos"})).Info("Parsed disk information from OVF envelope")
 
 	for _, di := range dis {
+		if util.IsDiskSkipped(vmi, di) {
+			logrus.WithFields(logrus.Fields{
+				"name":      vmi.Name,
+				"namespace": vmi.Namespace,
+				"diskName":  di.Name,
+				"deviceID":  di.DeviceID,
+				"index":     di.Index,
+			}).Info("Skipping a disk that is excluded from the import")
+			continue
+		}
+
 		tempImagePath := c.generateImagePath(vmi, di)
 
 		err = c.extractAndConvertVMDKToRAW(tempArchivePath, di.Name, tempImagePath, true)
@@ -166,6 +274,33 @@ func (c *Client) ExportVirtualMachine(vmi *migration.VirtualMachineImport) error
 		vmi.Status.DiskImportStatus = append(vmi.Status.DiskImportStatus, di)
 	}
 
+	if vmi.GetCDROMImport() == migration.CDROMImportNone {
+		return nil
+	}
+
+	for _, cd := range parseCDROMDrives(e, bootOrder) {
+		if cd.Name == "" || vmi.GetCDROMImport() == migration.CDROMImportEmpty {
+			vmi.Status.EmptyCDROMDrives = append(vmi.Status.EmptyCDROMDrives, migration.CDROMDrive{
+				DeviceID: cd.DeviceID,
+				BusType:  cd.BusType,
+			})
+			continue
+		}
+
+		// CD-ROM images do not need to be converted.
+		tempImagePath := filepath.Join(c.workingDir, fmt.Sprintf("%s-%s", vmi.Status.ImportedVirtualMachineName, filepath.Base(cd.Name)))
+
+		err = c.extractAndConvertVMDKToRAW(tempArchivePath, cd.Name, tempImagePath, false)
+		if err != nil {
+			return err
+		}
+
+		cd.Name = filepath.Base(tempImagePath)
+		cd.DiskLocalPath = filepath.Dir(tempImagePath)
+
+		vmi.Status.DiskImportStatus = append(vmi.Status.DiskImportStatus, cd)
+	}
+
 	return nil
 }
 
@@ -173,12 +308,12 @@ func (c *Client) ExportVirtualMachine(vmi *migration.VirtualMachineImport) error
 func (c *Client) GenerateVirtualMachine(vmi *migration.VirtualMachineImport) (*kubevirtv1.VirtualMachine, error) {
 	tempArchivePath := c.generateArchivePath(vmi)
 
-	e, err := readEnvelope(tempArchivePath)
+	e, bootOrder, err := readEnvelope(tempArchivePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read envelope: %w", err)
 	}
 
-	fw, hw, nis, dis := parseEnvelope(e, vmi.GetDefaultNetworkInterfaceModel(), vmi.GetDefaultDiskBusType())
+	fw, hw, nis, dis := parseEnvelope(e, bootOrder, vmi.GetDefaultNetworkInterfaceModel(), vmi.GetDefaultDiskBusType())
 	logrus.WithFields(util.FieldsToJSON(logrus.Fields{
 		"name":         vmi.Name,
 		"namespace":    vmi.Namespace,
@@ -195,21 +330,25 @@ func (c *Client) GenerateVirtualMachine(vmi *migration.VirtualMachineImport) (*k
 		},
 	}
 
+	source.ApplyMetadata(newVM, vmi, generateMetadata(e))
+
 	vmSpec := source.NewVirtualMachineSpec(source.VirtualMachineSpecConfig{
-		Name: vmi.Status.ImportedVirtualMachineName,
-		Hardware: source.Hardware{
-			NumCPU:            hw.NumCPU,
-			NumCoresPerSocket: 1, // OVF does not provide cores per socket information.
-			MemoryMB:          hw.MemoryMB,
-		},
+		Name:     vmi.Status.ImportedVirtualMachineName,
+		Hardware: *hw,
 	})
 
-	mappedNetwork := source.MapNetworks(nis, vmi.Spec.Mapping)
+	mappedNetwork, err := source.MapNetworkInterfaces(nis, vmi)
+	if err != nil {
+		return nil, err
+	}
 	networkConfig, interfaceConfig := source.GenerateNetworkInterfaceConfigs(mappedNetwork, vmi.GetDefaultNetworkInterfaceModel())
 
 	// Setup BIOS/EFI, SecureBoot and TPM settings.
 	source.ApplyFirmwareSettings(vmSpec, fw)
 
+	// Setup the CPU model. OVF does not describe the CPU placement.
+	source.ApplyCPUSettings(vmSpec, vmi, source.CPUSettings{})
+
 	vmSpec.Template.Spec.Networks = networkConfig
 	vmSpec.Template.Spec.Domain.Devices.Interfaces = interfaceConfig
 	newVM.Spec = *vmSpec
@@ -264,6 +403,27 @@ func (c *Client) Cleanup(vmi *migration.VirtualMachineImport) error {
 	return nil
 }
 
+// fetchEnvelope reads the OVF envelope and its boot order from the
+// beginning of the OVA file.
+func (c *Client) fetchEnvelope() (*ovf.Envelope, map[string]int32, error) {
+	req, err := newHttpRequest("GET", c.url, c.secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.httpClient.Do(req) // nolint:gosec
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to make GET request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed %s request (code=%d): %s", req.Method, resp.StatusCode, resp.Status)
+	}
+
+	return readEnvelopeFromStream(resp.Body)
+}
+
 // downloadArchive downloads the OVA file to /tmp.
 func (c *Client) downloadArchive(dstPath string) error {
 	logrus.WithFields(logrus.Fields{
@@ -291,7 +451,8 @@ func (c *Client) downloadArchive(dstPath string) error {
 	}
 	defer dst.Close() //nolint:errcheck
 
-	_, err = io.Copy(dst, resp.Body)
+	n, err := io.Copy(dst, resp.Body)
+	metrics.AddBytesTransferred(migration.KindOvaSource, n)
 	if err != nil {
 		return fmt.Errorf("failed to write archive file %q: %w", dstPath, err)
 	}
@@ -328,26 +489,94 @@ func readManifest(r io.Reader) (map[string]*library.Checksum, error) {
 	return csums, scanner.Err()
 }
 
-// readEnvelope reads the OVF envelope from the OVA archive.
-func readEnvelope(archivePath string) (*ovf.Envelope, error) {
+// readEnvelope reads the OVF envelope and its boot order from the OVA
+// archive.
+func readEnvelope(archivePath string) (*ovf.Envelope, map[string]int32, error) {
 	opener := importer.Opener{}
 	archive := importer.TapeArchive{Path: archivePath, Opener: opener}
 
 	o, err := importer.ReadOvf("*.ovf", &archive)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read OVF from archive %q: %w", archivePath, err)
+		return nil, nil, fmt.Errorf("failed to read OVF from archive %q: %w", archivePath, err)
 	}
 
 	e, err := importer.ReadEnvelope(o)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read envelope from archive %q: %w", archivePath, err)
+		return nil, nil, fmt.Errorf("failed to read envelope from archive %q: %w", archivePath, err)
 	}
 
-	return e, nil
+	return e, parseBootOrder(o), nil
+}
+
+// readEnvelopeFromStream reads the OVF envelope and its boot order from an
+// OVA archive stream. Reading stops at the OVF descriptor, the remaining
+// files are skipped.
+func readEnvelopeFromStream(r io.Reader) (*ovf.Envelope, map[string]int32, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("no OVF descriptor found in archive")
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if matched, _ := filepath.Match("*.ovf", path.Base(hdr.Name)); !matched {
+			continue
+		}
+
+		o, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %q: %w", hdr.Name, err)
+		}
+
+		e, err := ovf.Unmarshal(bytes.NewReader(o))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read envelope from %q: %w", hdr.Name, err)
+		}
+		return e, parseBootOrder(o), nil
+	}
+}
+
+// cdromBootOrderKey is the key of the CD-ROM entry in the boot order. The
+// CD-ROM section of the boot order does not reference an item, and instance
+// IDs are numeric, so the key cannot clash with the key of an item.
+const cdromBootOrderKey = "cdrom"
+
+// parseBootOrder returns the position of the disks and network interfaces
+// in the boot order of the OVF descriptor, starting at 1, by the instance
+// ID of their items. The position of the CD-ROM entry is stored under
+// `cdromBootOrderKey`. Floppy drives are not part of the result.
+// Descriptors without boot order, e.g. those not exported by VMware, result
+// in an empty map.
+func parseBootOrder(data []byte) map[string]int32 {
+	bootOrder := map[string]int32{}
+
+	var e bootOrderEnvelope
+	if err := xml.Unmarshal(data, &e); err != nil {
+		return bootOrder
+	}
+
+	for _, section := range e.BootOrder {
+		switch section.Type {
+		case "disk", "net":
+			if _, ok := bootOrder[section.InstanceID]; !ok {
+				bootOrder[section.InstanceID] = int32(len(bootOrder) + 1) // nolint:gosec
+			}
+		case "cdrom":
+			if _, ok := bootOrder[cdromBootOrderKey]; !ok {
+				bootOrder[cdromBootOrderKey] = int32(len(bootOrder) + 1) // nolint:gosec
+			}
+		}
+	}
+
+	return bootOrder
 }
 
 // extractAndConvertVMDKToRAW extracts the VMDK file from the OVA archive,
-// verifies its checksum and converts it to RAW format.
+// verifies its checksum and converts it to RAW format. If convert is false,
+// the extracted file is moved to dstPath as it is, e.g. for ISO images.
 func (c *Client) extractAndConvertVMDKToRAW(archivePath, name, dstPath string, convert bool) error {
 	opener := importer.Opener{}
 	archive := importer.TapeArchive{Path: archivePath, Opener: opener}
@@ -414,10 +643,16 @@ func (c *Client) extractAndConvertVMDKToRAW(archivePath, name, dstPath string, c
 	}
 
 	if convert {
+		startTime := time.Now()
 		err = qemu.ConvertVMDKtoRAW(vmdkFile.Name(), dstPath)
 		if err != nil {
 			return fmt.Errorf("failed to convert VMDK file %q to RAW %q: %w", vmdkFile.Name(), dstPath, err)
 		}
+		metrics.ObserveConversionDuration(migration.KindOvaSource, time.Since(startTime))
+	} else if dstPath != "" {
+		if err := util.MoveFile(vmdkFile.Name(), dstPath); err != nil {
+			return fmt.Errorf("failed to move file %q to %q: %w", vmdkFile.Name(), dstPath, err)
+		}
 	}
 
 	return nil
@@ -558,8 +793,39 @@ func detectDiskBusType(rasd *ovf.ResourceAllocationSettingData) (kubevirtv1.Disk
 	return busType, busType != ""
 }
 
+// generateMetadata returns the annotation and the product properties of
+// the virtual system of the envelope. Properties that hold passwords are
+// omitted.
+func generateMetadata(e *ovf.Envelope) source.Metadata {
+	md := source.Metadata{}
+
+	if e.VirtualSystem == nil {
+		return md
+	}
+
+	if e.VirtualSystem.Annotation != nil && e.VirtualSystem.Annotation.Annotation != "" {
+		md[source.MetadataKeyDescription] = e.VirtualSystem.Annotation.Annotation
+	}
+
+	for _, p := range e.VirtualSystem.Product {
+		for _, prop := range p.Property {
+			if ptr.Deref(prop.Password, false) {
+				continue
+			}
+			value := ptr.Deref(prop.Default, "")
+			if len(prop.Values) > 0 {
+				value = prop.Values[0].Value
+			}
+			md[source.MetadataPrefixProperty+p.Key(prop)] = value
+		}
+	}
+
+	return md
+}
+
 // parseEnvelope retrieves the firmware, virtual hardware and network settings from the OVF envelope.
-func parseEnvelope(e *ovf.Envelope, defaultInterfaceModel string, defaultDiskBusType kubevirtv1.DiskBus) (*source.Firmware, *source.Hardware, []source.NetworkInfo, []migration.DiskInfo) {
+// The boot order maps the instance IDs of the items to their position in the boot order, see `parseBootOrder`.
+func parseEnvelope(e *ovf.Envelope, bootOrder map[string]int32, defaultInterfaceModel string, defaultDiskBusType kubevirtv1.DiskBus) (*source.Firmware, *source.Hardware, []source.NetworkInfo, []migration.DiskInfo) {
 	fw := source.NewFirmware(false, false, false)
 	hw := source.NewHardware(0, 0, 0)
 	nis := make([]source.NetworkInfo, 0)
@@ -578,6 +844,10 @@ func parseEnvelope(e *ovf.Envelope, defaultInterfaceModel string, defaultDiskBus
 					switch *item.ResourceType {
 					case ovf.Processor: // Number of Virtual CPUs
 						hw.NumCPU = uint32(*item.VirtualQuantity) // nolint:gosec
+						// VMware extension, e.g. `<vmw:CoresPerSocket ovf:required="false">2</vmw:CoresPerSocket>`
+						if item.CoresPerSocket != nil && item.CoresPerSocket.Value > 0 {
+							hw.NumCoresPerSocket = uint32(item.CoresPerSocket.Value) // nolint:gosec
+						}
 					case ovf.Memory:
 						bytes := parseVirtualQuantity(item)
 						hw.MemoryMB = bytes / (1024 * 1024)
@@ -609,6 +879,7 @@ func parseEnvelope(e *ovf.Envelope, defaultInterfaceModel string, defaultDiskBus
 							NetworkName: item.Connection[0],
 							MAC:         macAddress,
 							Model:       model,
+							BootOrder:   bootOrder[item.InstanceID],
 						})
 					case ovf.DiskDrive:
 						diskId := filepath.Base(item.HostResource[0])
@@ -628,9 +899,13 @@ func parseEnvelope(e *ovf.Envelope, defaultInterfaceModel string, defaultDiskBus
 									}
 
 									dis = append(dis, migration.DiskInfo{
-										Name:     ref.Href,
-										DiskSize: parseCapacity(disk),
-										BusType:  busType,
+										Name:      ref.Href,
+										DiskSize:  parseCapacity(disk),
+										BusType:   busType,
+										Index:     int32(len(dis)), // nolint:gosec
+										DeviceID:  disk.DiskID,
+										FileName:  ref.Href,
+										BootOrder: bootOrder[item.InstanceID],
 									})
 								}
 							}
@@ -685,9 +960,13 @@ func parseEnvelope(e *ovf.Envelope, defaultInterfaceModel string, defaultDiskBus
 									}
 
 									dis = append(dis, migration.DiskInfo{
-										Name:     ref.Href,
-										DiskSize: parseCapacity(disk),
-										BusType:  busType,
+										Name:      ref.Href,
+										DiskSize:  parseCapacity(disk),
+										BusType:   busType,
+										Index:     int32(len(dis)), // nolint:gosec
+										DeviceID:  disk.DiskID,
+										FileName:  ref.Href,
+										BootOrder: bootOrder[item.InstanceID],
 									})
 								}
 							}
@@ -701,9 +980,91 @@ func parseEnvelope(e *ovf.Envelope, defaultInterfaceModel string, defaultDiskBus
 		}
 	}
 
+	// The first disk in the boot order is the boot disk. If the OVF
+	// descriptor has no boot order, the first disk is assumed to be the
+	// boot disk.
+	bootDiskIndex := 0
+	for i, di := range dis {
+		if di.BootOrder > 0 && (dis[bootDiskIndex].BootOrder == 0 || di.BootOrder < dis[bootDiskIndex].BootOrder) {
+			bootDiskIndex = i
+		}
+	}
+	if len(dis) > 0 {
+		dis[bootDiskIndex].BootDisk = true
+	}
+
 	return fw, hw, nis, dis
 }
 
+// parseCDROMDrives returns the CD-ROM drives described in the OVF envelope.
+// The name of the ISO image is set for drives that reference a file of the
+// OVA archive. The CD-ROM position of the boot order is assigned to the
+// first drive with an ISO image, or the first drive if there is none.
+func parseCDROMDrives(e *ovf.Envelope, bootOrder map[string]int32) []migration.DiskInfo {
+	cds := make([]migration.DiskInfo, 0)
+
+	if e.VirtualSystem == nil {
+		return cds
+	}
+
+	for _, vh := range e.VirtualSystem.VirtualHardware {
+		add := func(resourceType *ovf.CIMResourceType, instanceID string, hostResource []string, parent *string) {
+			if resourceType == nil || (*resourceType != ovf.CdDrive && *resourceType != ovf.DvdDrive) {
+				return
+			}
+
+			busType := kubevirtv1.DiskBusSATA
+			if parent != nil {
+				parentItem := findResourceAllocationSettingData(&vh, *parent)
+				if parentItem != nil {
+					if detectedBusType, ok := detectDiskBusType(parentItem); ok {
+						busType = detectedBusType
+					}
+				}
+			}
+
+			cd := migration.DiskInfo{
+				BusType:  util.CDROMBusType(busType),
+				Index:    int32(len(cds)), // nolint:gosec
+				DeviceID: instanceID,
+				CDROM:    true,
+			}
+			if len(hostResource) > 0 {
+				if ref := resolveReference(e, filepath.Base(hostResource[0])); ref != nil {
+					cd.Name = ref.Href
+					cd.FileName = ref.Href
+					cd.DiskSize = int64(ref.Size) // nolint:gosec
+				}
+			}
+
+			cds = append(cds, cd)
+		}
+
+		// OVF v1.0 - <Item>
+		for _, item := range vh.Item {
+			add(item.ResourceType, item.InstanceID, item.HostResource, item.Parent)
+		}
+
+		// OVF v2.0 - <StorageItem>
+		for _, item := range vh.StorageItem {
+			add(item.ResourceType, item.InstanceID, item.HostResource, item.Parent)
+		}
+	}
+
+	if order, ok := bootOrder[cdromBootOrderKey]; ok && len(cds) > 0 {
+		bootIndex := 0
+		for i := range cds {
+			if cds[i].Name != "" {
+				bootIndex = i
+				break
+			}
+		}
+		cds[bootIndex].BootOrder = order
+	}
+
+	return cds
+}
+
 // newHttpRequest creates a new HTTP request with optional basic authentication.
 func newHttpRequest(method, url string, secret *corev1.Secret) (*http.Request, error) {
 	req, err := http.NewRequest(method, url, nil) // nolint:gosec
@@ -115,7 +115,7 @@ var ovfData2 = `<?xml version="1.0" encoding="UTF-8"?>
         <rasd:InstanceID>1</rasd:InstanceID>
         <rasd:ResourceType>3</rasd:ResourceType>
         <rasd:VirtualQuantity>4</rasd:VirtualQuantity>
-        <vmw:CoresPerSocket ovf:required="false">1</vmw:CoresPerSocket>
+        <vmw:CoresPerSocket ovf:required="false">2</vmw:CoresPerSocket>
       </Item>
       <Item>
         <rasd:AllocationUnits>byte * 2^20</rasd:AllocationUnits>
@@ -160,6 +160,25 @@ var ovfData2 = `<?xml version="1.0" encoding="UTF-8"?>
       <vmw:Config ovf:required="false" vmw:key="bootOptions.efiSecureBootEnabled" vmw:value="true"/>
       <vmw:Config ovf:required="false" vmw:key="firmware" vmw:value="efi"/>
     </VirtualHardwareSection>
+    <vmw:BootOrderSection vmw:instanceId="8" vmw:type="net">
+      <Info>Virtual hardware device boot order</Info>
+    </vmw:BootOrderSection>
+    <vmw:BootOrderSection vmw:type="cdrom">
+      <Info>Virtual hardware device boot order</Info>
+    </vmw:BootOrderSection>
+    <vmw:BootOrderSection vmw:instanceId="5" vmw:type="disk">
+      <Info>Virtual hardware device boot order</Info>
+    </vmw:BootOrderSection>
+    <AnnotationSection>
+      <Info>A human-readable annotation</Info>
+      <Annotation>Ubuntu test VM</Annotation>
+    </AnnotationSection>
+    <ProductSection ovf:class="app">
+      <Info>Information about the installed software</Info>
+      <Product>Test App</Product>
+      <Property ovf:key="owner" ovf:type="string" ovf:value="team-a"/>
+      <Property ovf:key="password" ovf:type="string" ovf:password="true" ovf:value="secret"/>
+    </ProductSection>
   </VirtualSystem>
 </Envelope>`
 
@@ -402,7 +421,7 @@ func Test_readEnvelope(t *testing.T) {
 	_, currentFile, _, _ := runtime.Caller(0)
 	pwd := filepath.Dir(currentFile)
 
-	e, err := readEnvelope(filepath.Join(pwd, "test.ova"))
+	e, _, err := readEnvelope(filepath.Join(pwd, "test.ova"))
 	assert.NoError(err, "expected no error during reading envelope")
 	assert.NotNil(e, "expected envelope to be returned")
 	assert.Equal("ubuntu.2.0-disk1.vmdk", e.References[0].Href, "expected href to match")
@@ -554,7 +573,7 @@ func Test_extractAndConvertVMDKToRAW(t *testing.T) {
 func Test_parseEnvelope_DiskInfo_empty(t *testing.T) {
 	assert := require.New(t)
 	e := &ovf.Envelope{}
-	_, _, _, dis := parseEnvelope(e, "", "foo")
+	_, _, _, dis := parseEnvelope(e, nil, "", "foo")
 	assert.Len(dis, 0, "expected no disk info")
 }
 
@@ -562,24 +581,79 @@ func Test_parseEnvelope_DiskInfo_ovf_v1(t *testing.T) {
 	assert := require.New(t)
 	e, err := importer.ReadEnvelope([]byte(ovfData2))
 	assert.NoError(err, "expected no error during reading envelope")
-	_, _, _, dis := parseEnvelope(e, "", "buz")
+	_, _, _, dis := parseEnvelope(e, nil, "", "buz")
 	assert.Len(dis, 1, "expected one disk info")
 	assert.Equal("gm-ubuntu-test-1.vmdk", dis[0].Name, "expected name to match")
 	assert.Equal(int64(42949672960), dis[0].DiskSize, "expected size to match")
 	assert.Equal(kubevirtv1.DiskBusSCSI, dis[0].BusType, "expected bus type to match")
+	assert.Equal("gm-ubuntu-test-1.vmdk", dis[0].FileName, "expected file name to match")
+	assert.True(dis[0].BootDisk, "expected the first disk to be the boot disk")
 }
 
 func Test_parseEnvelope_DiskInfo_ovf_v2(t *testing.T) {
 	assert := require.New(t)
 	e, err := importer.ReadEnvelope([]byte(ovfData3))
 	assert.NoError(err, "expected no error during reading envelope")
-	_, _, _, dis := parseEnvelope(e, "", "buz")
+	_, _, _, dis := parseEnvelope(e, nil, "", "buz")
 	assert.Len(dis, 1, "expected one disk info")
 	assert.Equal("ubuntu.2.0-disk1.vmdk", dis[0].Name, "expected name to match")
 	assert.Equal(int64(8589934592), dis[0].DiskSize, "expected size to match")
 	assert.Equal(kubevirtv1.DiskBusSATA, dis[0].BusType, "expected bus type to match")
 }
 
+func Test_parseBootOrder(t *testing.T) {
+	assert := require.New(t)
+
+	bootOrder := parseBootOrder([]byte(ovfData2))
+	assert.Equal(map[string]int32{"8": 1, cdromBootOrderKey: 2, "5": 3}, bootOrder, "expected boot order to match")
+	assert.Empty(parseBootOrder([]byte(ovfData3)), "expected no boot order")
+
+	e, err := importer.ReadEnvelope([]byte(ovfData2))
+	assert.NoError(err, "expected no error during reading envelope")
+	_, _, nis, dis := parseEnvelope(e, bootOrder, "", "buz")
+	assert.Len(nis, 1, "expected one network info")
+	assert.Equal(int32(1), nis[0].BootOrder, "expected the network interface to be booted from first")
+	assert.Len(dis, 1, "expected one disk info")
+	assert.Equal(int32(3), dis[0].BootOrder, "expected the disk to be booted from third")
+	assert.True(dis[0].BootDisk, "expected the disk to be the boot disk")
+}
+
+func Test_generateMetadata(t *testing.T) {
+	assert := require.New(t)
+
+	e, err := importer.ReadEnvelope([]byte(ovfData2))
+	assert.NoError(err, "expected no error during reading envelope")
+	assert.Equal(source.Metadata{
+		source.MetadataKeyDescription:               "Ubuntu test VM",
+		source.MetadataPrefixProperty + "app.owner": "team-a",
+	}, generateMetadata(e), "expected password properties to be omitted")
+
+	e, err = importer.ReadEnvelope([]byte(ovfData3))
+	assert.NoError(err, "expected no error during reading envelope")
+	assert.Empty(generateMetadata(e), "expected no metadata")
+}
+
+func Test_parseCDROMDrives(t *testing.T) {
+	assert := require.New(t)
+
+	e, err := importer.ReadEnvelope([]byte(ovfData3))
+	assert.NoError(err, "expected no error during reading envelope")
+	cds := parseCDROMDrives(e, map[string]int32{"5": 1, cdromBootOrderKey: 2})
+	assert.Len(cds, 1, "expected one CD-ROM drive")
+	assert.True(cds[0].CDROM, "expected the drive to be a CD-ROM")
+	assert.Equal("9", cds[0].DeviceID, "expected device ID to match")
+	assert.Equal(kubevirtv1.DiskBusSATA, cds[0].BusType, "expected bus type to match")
+	assert.Empty(cds[0].Name, "expected the drive to have no media")
+	assert.Equal(int32(2), cds[0].BootOrder, "expected the drive to be booted from second")
+
+	cds = parseCDROMDrives(e, map[string]int32{"5": 1})
+	assert.Zero(cds[0].BootOrder, "expected the drive not to be booted from")
+
+	e, err = importer.ReadEnvelope([]byte(ovfData2))
+	assert.NoError(err, "expected no error during reading envelope")
+	assert.Empty(parseCDROMDrives(e, nil), "expected no CD-ROM drive")
+}
+
 func Test_parseEnvelope_Firmware(t *testing.T) {
 	assert := require.New(t)
 	testCases := []struct {
@@ -602,7 +676,7 @@ func Test_parseEnvelope_Firmware(t *testing.T) {
 	for _, tc := range testCases {
 		e, err := importer.ReadEnvelope(tc.envelope)
 		assert.NoError(err, "expected no error during reading envelope")
-		fw, _, _, _ := parseEnvelope(e, "foo", "buz")
+		fw, _, _, _ := parseEnvelope(e, nil, "foo", "buz")
 		assert.Equal(tc.expected.UEFI, fw.UEFI, "expected UEFI flag to match")
 		assert.Equal(tc.expected.TPM, fw.TPM, "expected TPM flag to match")
 		assert.Equal(tc.expected.SecureBoot, fw.SecureBoot, "expected SecureBoot flag to match")
@@ -630,7 +704,7 @@ func Test_parseEnvelope_Hardware(t *testing.T) {
 			envelope: []byte(ovfData2),
 			expected: source.Hardware{
 				NumCPU:            4,
-				NumCoresPerSocket: 0,
+				NumCoresPerSocket: 2,
 				MemoryMB:          8192,
 			},
 		},
@@ -639,7 +713,7 @@ func Test_parseEnvelope_Hardware(t *testing.T) {
 	for _, tc := range testCases {
 		e, err := importer.ReadEnvelope(tc.envelope)
 		assert.NoError(err, "expected no error during reading envelope")
-		_, hw, _, _ := parseEnvelope(e, "bar", "buz")
+		_, hw, _, _ := parseEnvelope(e, nil, "bar", "buz")
 		assert.Equal(tc.expected.NumCPU, hw.NumCPU, "expected CPU count to match")
 		assert.Equal(tc.expected.NumCoresPerSocket, hw.NumCoresPerSocket, "expected number of cores per socket to match")
 		assert.Equal(tc.expected.MemoryMB, hw.MemoryMB, "expected memory size to match")
@@ -650,7 +724,7 @@ func Test_parseEnvelope_NetworkInfos_v1(t *testing.T) {
 	assert := require.New(t)
 	e, err := importer.ReadEnvelope([]byte(ovfData2))
 	assert.NoError(err, "expected no error during reading envelope")
-	_, _, ni, _ := parseEnvelope(e, "baz", "buz")
+	_, _, ni, _ := parseEnvelope(e, nil, "baz", "buz")
 	assert.Len(ni, 1)
 	assert.Equal("DSwitch-vCenter-HA-VM-Network", ni[0].NetworkName, "expected network name to match")
 	assert.Empty(ni[0].MAC, "expected MAC address to be empty")
@@ -726,3 +800,37 @@ func Test_downloadArchive(t *testing.T) {
 		_ = os.Remove(dstFile.Name()) // nolint:gosec
 	}
 }
+
+func Test_ListVirtualMachines(t *testing.T) {
+	assert := require.New(t)
+
+	_, currentFile, _, _ := runtime.Caller(0)
+	pwd := filepath.Dir(currentFile)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join(pwd, "test.ova"))
+	})
+
+	httpServer := httptest.NewTLSServer(handler)
+	defer httpServer.Close()
+
+	c, err := NewClient(context.TODO(), httpServer.URL+"/test.ova", nil, migration.OvaSourceOptions{})
+	assert.NoError(err, "expected no error during creation of client")
+
+	vms, err := c.ListVirtualMachines()
+	assert.NoError(err, "expected no error during listing of VMs")
+	assert.Len(vms, 1, "expected one VM")
+	assert.NotEmpty(vms[0].Name, "expected VM name to be set")
+	assert.Equal("Ubuntu_64", vms[0].GuestOS, "expected guest OS to match")
+	assert.Len(vms[0].Disks, 1, "expected one disk")
+	assert.Equal("ubuntu.2.0-disk1.vmdk", vms[0].Disks[0].Name, "expected disk name to match")
+	assert.NotZero(vms[0].CPUs, "expected CPUs to be set")
+	assert.NotZero(vms[0].MemoryMiB, "expected memory to be set")
+}
+
+func Test_readEnvelopeFromStream(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, err := readEnvelopeFromStream(strings.NewReader(""))
+	assert.Error(err, "expected error for an empty archive")
+}
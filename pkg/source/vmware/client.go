@@ -2,30 +2,41 @@ package vmware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/nfc"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	kubevirt "kubevirt.io/api/core/v1"
 
 	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/metrics"
 	"github.com/harvester/vm-import-controller/pkg/qemu"
 	"github.com/harvester/vm-import-controller/pkg/server"
 	"github.com/harvester/vm-import-controller/pkg/source"
@@ -38,6 +49,7 @@ type Client struct {
 	tmpCerts       string
 	dc             string
 	networkMapping map[string]string
+	userinfo       *url.Userinfo
 }
 
 func NewClient(ctx context.Context, endpoint string, dc string, secret *corev1.Secret) (*Client, error) {
@@ -88,12 +100,14 @@ func NewClient(ctx context.Context, endpoint string, dc string, secret *corev1.S
 		SessionManager: session.NewManager(vc),
 	}
 
-	err = c.Login(ctx, url.UserPassword(string(username), string(password)))
+	userinfo := url.UserPassword(string(username), string(password))
+	err = c.Login(ctx, userinfo)
 	if err != nil {
 		return nil, fmt.Errorf("error during login :%v", err)
 	}
 
 	vmwareClient.ctx = ctx
+	vmwareClient.userinfo = userinfo
 	vmwareClient.Client = c
 	vmwareClient.dc = dc
 
@@ -134,6 +148,82 @@ func (c *Client) Verify() error {
 	return nil
 }
 
+// Version returns the full name and version of the vCenter or ESXi host,
+// e.g. "VMware vCenter Server 8.0.2 build-22385739".
+func (c *Client) Version() string {
+	return c.Client.ServiceContent.About.FullName
+}
+
+// ListVirtualMachines returns the inventory of all VMs in the datacenter.
+// Templates are skipped because they cannot be imported.
+func (c *Client) ListVirtualMachines() ([]migration.InventoryVirtualMachine, error) {
+	f := find.NewFinder(c.Client.Client, true)
+	dc := c.dc
+	if !strings.HasPrefix(c.dc, "/") {
+		dc = fmt.Sprintf("/%s", c.dc)
+	}
+	vmFolder := path.Join(dc, "vm")
+
+	vms, err := f.VirtualMachineList(c.ctx, path.Join(vmFolder, "..."))
+	if err != nil {
+		var notFoundErr *find.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return []migration.InventoryVirtualMachine{}, nil
+		}
+		return nil, fmt.Errorf("error listing VMs: %w", err)
+	}
+
+	// The folder of a VM is derived from the inventory path set by the finder.
+	refs := make([]types.ManagedObjectReference, 0, len(vms))
+	folders := make(map[types.ManagedObjectReference]string, len(vms))
+	for _, vm := range vms {
+		refs = append(refs, vm.Reference())
+		folders[vm.Reference()] = strings.TrimPrefix(path.Dir(vm.InventoryPath), vmFolder)
+	}
+
+	var vmObjs []mo.VirtualMachine
+	pc := property.DefaultCollector(c.Client.Client)
+	if err := pc.Retrieve(c.ctx, refs, []string{"name", "config", "runtime.powerState", "summary.config"}, &vmObjs); err != nil {
+		return nil, fmt.Errorf("error retrieving VM properties: %w", err)
+	}
+
+	result := make([]migration.InventoryVirtualMachine, 0, len(vmObjs))
+	for i := range vmObjs {
+		o := &vmObjs[i]
+		// The config is not available for inaccessible VMs.
+		if o.Config == nil || o.Config.Template {
+			continue
+		}
+
+		ivm := migration.InventoryVirtualMachine{
+			Name:              o.Name,
+			ID:                o.Config.InstanceUuid,
+			Folder:            folders[o.Reference()],
+			PowerState:        string(o.Runtime.PowerState),
+			CPUs:              o.Config.Hardware.NumCPU,
+			MemoryMiB:         int64(o.Config.Hardware.MemoryMB),
+			Firmware:          source.InventoryFirmware(getFirmwareSettings(o)),
+			GuestOS:           o.Config.GuestFullName,
+			NetworkInterfaces: source.InventoryNetworkInterfaces(generateNetworkInfos(c.networkMapping, o.Config.Hardware.Device, nil)),
+		}
+
+		for _, d := range o.Config.Hardware.Device {
+			disk, ok := d.(*types.VirtualDisk)
+			if !ok {
+				continue
+			}
+			ivm.Disks = append(ivm.Disks, migration.InventoryDisk{
+				Name:      disk.DeviceInfo.GetDescription().Label,
+				SizeBytes: disk.CapacityInBytes,
+			})
+		}
+
+		result = append(result, ivm)
+	}
+
+	return result, nil
+}
+
 func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error) {
 	// Check the source network mappings.
 	networkMap, err := GenerateNetworkMapByName(c.ctx, c.Client.Client)
@@ -141,6 +231,11 @@ func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error)
 		return fmt.Errorf("error generating network map: %v", err)
 	}
 	for _, nm := range vm.Spec.Mapping {
+		// Patterns do not have to match any network.
+		if nm.GetMatchType() != migration.NetworkMatchTypeExact {
+			continue
+		}
+
 		logrus.WithFields(logrus.Fields{
 			"name":          vm.Name,
 			"namespace":     vm.Namespace,
@@ -149,7 +244,7 @@ func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error)
 
 		elements := strings.Split(nm.SourceNetwork, "/")
 		if _, ok := networkMap[elements[len(elements)-1]]; !ok {
-			return fmt.Errorf("source network '%s' not found", nm.SourceNetwork)
+			return fmt.Errorf("%w: '%s'", util.ErrSourceNetworkNotFound, nm.SourceNetwork)
 		}
 	}
 
@@ -159,7 +254,7 @@ func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error)
 	if !vm.GetForcePowerOff() {
 		vmObj, err := c.findVM(vm.Spec.Folder, vm.Spec.VirtualMachineName)
 		if err != nil {
-			return fmt.Errorf("failed to find VM: %w", err)
+			return fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
 		}
 
 		poweredOff, err := isPoweredOff(c.ctx, vmObj)
@@ -179,7 +274,7 @@ func (c *Client) PreFlightChecks(vm *migration.VirtualMachineImport) (err error)
 			}
 
 			if !toolsRunning {
-				return fmt.Errorf("cannot shutdown guest OS, VMware Tools is not running in the VM %s. Shut down the VM manually or use the 'spec.forcePowerOff' field", vm.Spec.VirtualMachineName)
+				return fmt.Errorf("%w: cannot shutdown guest OS of the VM %s. Shut down the VM manually or use the 'spec.forcePowerOff' field", util.ErrToolsNotRunning, vm.Spec.VirtualMachineName)
 			}
 		}
 	}
@@ -226,63 +321,39 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) (err e
 
 	var vmMo mo.VirtualMachine
 	err = vmObj.Properties(c.ctx, vmObj.Reference(),
-		[]string{"config.hardware.device"},
+		[]string{"config.hardware.device", "config.bootOptions"},
 		&vmMo)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve VM hardware devices: %w", err)
 	}
 
-	diskByBusUnit := map[diskKey]*types.VirtualDisk{}
-	controllerBus := map[int32]int32{}
-
-	// by building this map, we can later match each lease disk entry to the corresponding
-	// VirtualDisk device in the VM hardware and retrieve the correct disk
-	// capacity from the VM configuration instead of relying on the incorrect LeaseInfo.Items[n].Size value.
-	for _, dev := range vmMo.Config.Hardware.Device {
-		if d, ok := dev.(types.BaseVirtualController); ok {
-			ctrl := d.GetVirtualController()
-			controllerBus[dev.GetVirtualDevice().Key] = ctrl.BusNumber
-		}
-	}
+	// by building the disk infos from the VM hardware, we can later match each lease disk entry
+	// to the corresponding VirtualDisk device and retrieve the correct disk capacity from the
+	// VM configuration instead of relying on the incorrect LeaseInfo.Items[n].Size value.
+	diskInfos, diskByBusUnit := generateDiskInfos(&vmMo, vm.GetDefaultDiskBusType())
+	unmatchedDiskIndex := int32(len(diskInfos)) // nolint:gosec
 
-	// it's rare, but we cannot ensure the controller will always be before the disk in the list of devices
-	// so we need to loop through the devices twice to build a map of disks by their bus and unit numbers
-	for _, dev := range vmMo.Config.Hardware.Device {
-		if d, ok := dev.(*types.VirtualDisk); ok {
-			if d.UnitNumber == nil {
-				continue
-			}
-
-			bus, ok := controllerBus[d.ControllerKey]
-			if !ok {
-				logrus.WithFields(logrus.Fields{
-					"controllerKey": d.ControllerKey,
-					"diskKey":       d.Key,
-				}).Warn("Controller not found for disk")
-				continue
-			}
-
-			key := diskKey{
-				bus:  bus,
-				unit: *d.UnitNumber,
-			}
-			diskByBusUnit[key] = d
-		}
-	}
+	cdromInfos, cdromByBusUnit := generateCDROMInfos(&vmMo)
+	exportedCDROMs := make(map[int]bool)
 
 	for _, i := range info.Items {
 		// ignore iso and nvram disks
 		if strings.HasSuffix(i.Path, ".vmdk") {
-			var diskSize int64
+			var di migration.DiskInfo
 
 			bus, unit, ok := parseDeviceId(i.DeviceId)
-			if ok {
-				if disk, ok := diskByBusUnit[diskKey{bus: bus, unit: unit}]; ok {
-					diskSize = disk.CapacityInKB * 1024
+			if idx, found := diskByBusUnit[diskKey{bus: bus, unit: unit}]; ok && found {
+				di = diskInfos[idx]
+			} else {
+				di = migration.DiskInfo{
+					BusType:  detectDiskBusType(i.DeviceId, vm.GetDefaultDiskBusType()),
+					Index:    unmatchedDiskIndex,
+					DeviceID: path.Base(i.DeviceId),
 				}
+				unmatchedDiskIndex++
 			}
 
-			if diskSize == 0 {
+			if di.DiskSize == 0 {
 				// fallback to lease size if mapping failed
 				logrus.WithFields(logrus.Fields{
 					"name": vm.Name, "namespace": vm.Namespace,
@@ -290,14 +361,25 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) (err e
 					"deviceId": i.DeviceId,
 					"size":     i.Size,
 				}).Warn("Failed to map lease VMDK to VirtualDisk capacity; falling back to lease item size")
-				diskSize = i.Size
+				di.DiskSize = i.Size
 			}
 
 			if !strings.HasPrefix(i.Path, vm.Spec.VirtualMachineName) {
 				i.Path = vm.Name + "-" + vm.Namespace + "-" + i.Path
 			}
 
-			busType := detectDiskBusType(i.DeviceId, vm.GetDefaultDiskBusType())
+			di.Name = i.Path
+
+			if util.IsDiskSkipped(vm, di) {
+				logrus.WithFields(logrus.Fields{
+					"name":      vm.Name,
+					"namespace": vm.Namespace,
+					"deviceId":  i.DeviceId,
+					"path":      i.Path,
+					"index":     di.Index,
+				}).Info("Skipping an image that is excluded from the import")
+				continue
+			}
 
 			logrus.WithFields(logrus.Fields{
 				"name":                    vm.Name,
@@ -307,8 +389,9 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) (err e
 				"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
 				"deviceId":                i.DeviceId,
 				"path":                    i.Path,
-				"busType":                 busType,
-				"size":                    diskSize,
+				"busType":                 di.BusType,
+				"datastore":               di.Datastore,
+				"size":                    di.DiskSize,
 			}).Info("Downloading an image")
 
 			exportPath := filepath.Join(tmpPath, i.Path)
@@ -317,11 +400,37 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) (err e
 				return err
 			}
 
-			vm.Status.DiskImportStatus = append(vm.Status.DiskImportStatus, migration.DiskInfo{
-				Name:     i.Path,
-				DiskSize: diskSize,
-				BusType:  busType,
-			})
+			vm.Status.DiskImportStatus = append(vm.Status.DiskImportStatus, di)
+		} else if idx, ok := findCDROM(cdromByBusUnit, i.DeviceId); ok && strings.HasSuffix(i.Path, ".iso") &&
+			vm.GetCDROMImport() == migration.CDROMImportImage {
+			if !strings.HasPrefix(i.Path, vm.Spec.VirtualMachineName) {
+				i.Path = vm.Name + "-" + vm.Namespace + "-" + i.Path
+			}
+
+			di := cdromInfos[idx]
+			di.Name = i.Path
+			di.DiskSize = i.Size
+
+			logrus.WithFields(logrus.Fields{
+				"name":                    vm.Name,
+				"namespace":               vm.Namespace,
+				"spec.virtualMachineName": vm.Spec.VirtualMachineName,
+				"spec.sourceCluster.name": vm.Spec.SourceCluster.Name,
+				"spec.sourceCluster.kind": vm.Spec.SourceCluster.Kind,
+				"deviceId":                i.DeviceId,
+				"path":                    i.Path,
+				"busType":                 di.BusType,
+				"size":                    i.Size,
+			}).Info("Downloading a CD-ROM image")
+
+			exportPath := filepath.Join(tmpPath, i.Path)
+			err = lease.DownloadFile(c.ctx, exportPath, i, soap.DefaultDownload)
+			if err != nil {
+				return err
+			}
+
+			exportedCDROMs[idx] = true
+			vm.Status.DiskImportStatus = append(vm.Status.DiskImportStatus, di)
 		} else {
 			logrus.WithFields(logrus.Fields{
 				"name":                    vm.Name,
@@ -336,6 +445,18 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) (err e
 		}
 	}
 
+	// The CD-ROM drives whose image has not been imported are added without media.
+	if vm.GetCDROMImport() != migration.CDROMImportNone {
+		for idx, cd := range cdromInfos {
+			if !exportedCDROMs[idx] {
+				vm.Status.EmptyCDROMDrives = append(vm.Status.EmptyCDROMDrives, migration.CDROMDrive{
+					DeviceID: cd.DeviceID,
+					BusType:  cd.BusType,
+				})
+			}
+		}
+	}
+
 	u.Done()
 	// complete lease since disks have been downloaded
 	// and all subsequence processing is local
@@ -360,10 +481,26 @@ func (c *Client) ExportVirtualMachine(vm *migration.VirtualMachineImport) (err e
 		rawDiskName := util.BaseName(d.Name) + ".img"
 		destFile := filepath.Join(server.TempDir(), rawDiskName)
 
+		if info, err := os.Stat(sourceFile); err == nil {
+			metrics.AddBytesTransferred(migration.KindVmwareSource, info.Size())
+		}
+
+		// CD-ROM images do not need to be converted.
+		if d.CDROM {
+			err = util.MoveFile(sourceFile, filepath.Join(server.TempDir(), d.Name))
+			if err != nil {
+				return fmt.Errorf("error moving CD-ROM image: %v", err)
+			}
+			vm.Status.DiskImportStatus[i].DiskLocalPath = server.TempDir()
+			continue
+		}
+
+		startTime := time.Now()
 		err = qemu.ConvertVMDKtoRAW(sourceFile, destFile)
 		if err != nil {
 			return fmt.Errorf("error during conversion of VMDK to RAW disk: %v", err)
 		}
+		metrics.ObserveConversionDuration(migration.KindVmwareSource, time.Since(startTime))
 
 		// update fields to reflect final location of raw image file
 		vm.Status.DiskImportStatus[i].DiskLocalPath = server.TempDir()
@@ -426,6 +563,71 @@ func (c *Client) IsPoweredOff(vm *migration.VirtualMachineImport) (bool, error)
 	return isPoweredOff(c.ctx, vmObj)
 }
 
+// GetNetworkInfos returns the network interfaces of the source VM.
+func (c *Client) GetNetworkInfos(vm *migration.VirtualMachineImport) ([]source.NetworkInfo, error) {
+	vmObj, err := c.findVM(vm.Spec.Folder, vm.Spec.VirtualMachineName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
+	}
+
+	var o mo.VirtualMachine
+	err = vmObj.Properties(c.ctx, vmObj.Reference(), []string{"config.hardware.device", "config.bootOptions"}, &o)
+	if err != nil {
+		return nil, err
+	}
+	if o.Config == nil {
+		return nil, nil
+	}
+
+	return generateNetworkInfos(c.networkMapping, o.Config.Hardware.Device, getBootOrder(&o)), nil
+}
+
+// GetDiskInfos returns the disks of the source VM.
+func (c *Client) GetDiskInfos(vm *migration.VirtualMachineImport) ([]migration.DiskInfo, error) {
+	vmObj, err := c.findVM(vm.Spec.Folder, vm.Spec.VirtualMachineName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
+	}
+
+	var o mo.VirtualMachine
+	err = vmObj.Properties(c.ctx, vmObj.Reference(), []string{"config.hardware.device", "config.bootOptions"}, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	dis, _ := generateDiskInfos(&o, vm.GetDefaultDiskBusType())
+	return dis, nil
+}
+
+// GetVirtualMachineIdentity returns the instance UUID, the BIOS UUID and the
+// managed object reference of the source VM.
+func (c *Client) GetVirtualMachineIdentity(vm *migration.VirtualMachineImport) (*migration.SourceVirtualMachineIdentity, error) {
+	vmObj, err := c.findVM(vm.Spec.Folder, vm.Spec.VirtualMachineName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", util.ErrVirtualMachineNotFound, err)
+	}
+
+	var o mo.VirtualMachine
+	err = vmObj.Properties(c.ctx, vmObj.Reference(), []string{"config.uuid", "config.instanceUuid"}, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateIdentity(&o, vmObj.Reference()), nil
+}
+
+// generateIdentity returns the identity of the given VM.
+func generateIdentity(o *mo.VirtualMachine, ref types.ManagedObjectReference) *migration.SourceVirtualMachineIdentity {
+	identity := &migration.SourceVirtualMachineIdentity{
+		Reference: ref.Value,
+	}
+	if o.Config != nil {
+		identity.ID = o.Config.InstanceUuid
+		identity.UUID = o.Config.Uuid
+	}
+	return identity
+}
+
 func (c *Client) GenerateVirtualMachine(vm *migration.VirtualMachineImport) (*kubevirt.VirtualMachine, error) {
 	vmObj, err := c.findVM(vm.Spec.Folder, vm.Spec.VirtualMachineName)
 	if err != nil {
@@ -455,7 +657,7 @@ func (c *Client) GenerateVirtualMachine(vm *migration.VirtualMachineImport) (*ku
 	}, []string{"spec"})).Info("Origin spec of the VM to be imported")
 
 	// Need CPU, Socket, Memory, VirtualNIC information to perform the mapping
-	networkInfos := generateNetworkInfos(c.networkMapping, o.Config.Hardware.Device)
+	networkInfos := generateNetworkInfos(c.networkMapping, o.Config.Hardware.Device, getBootOrder(&o))
 
 	vmSpec := source.NewVirtualMachineSpec(source.VirtualMachineSpecConfig{
 		Name: vm.Status.ImportedVirtualMachineName,
@@ -466,13 +668,33 @@ func (c *Client) GenerateVirtualMachine(vm *migration.VirtualMachineImport) (*ku
 		},
 	})
 
-	mappedNetwork := source.MapNetworks(networkInfos, vm.Spec.Mapping)
+	mappedNetwork, err := source.MapNetworkInterfaces(networkInfos, vm)
+	if err != nil {
+		return nil, err
+	}
 	networkConfig, interfaceConfig := source.GenerateNetworkInterfaceConfigs(mappedNetwork, vm.GetDefaultNetworkInterfaceModel())
 
 	// Setup BIOS/EFI, SecureBoot and TPM settings.
 	fw := getFirmwareSettings(&o)
 	source.ApplyFirmwareSettings(vmSpec, fw)
 
+	// Add the notes, custom attributes and tags of the VM.
+	vmTags, err := c.getTags(vmObj.Reference())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":      vm.Name,
+			"namespace": vm.Namespace,
+		}).WithError(err).Warn("Failed to get the tags of the VM, they are not added to the VM")
+	}
+	source.ApplyMetadata(newVM, vm, generateMetadata(&o, vmTags))
+
+	// Setup the CPU model, features, placement and reservations.
+	var hostCPUMhz int32
+	if vm.PreserveCPUReservations() {
+		hostCPUMhz = c.getHostCPUMhz(&o)
+	}
+	source.ApplyCPUSettings(vmSpec, vm, getCPUSettings(&o, hostCPUMhz))
+
 	vmSpec.Template.Spec.Networks = networkConfig
 	vmSpec.Template.Spec.Domain.Devices.Interfaces = interfaceConfig
 	newVM.Spec = *vmSpec
@@ -491,7 +713,10 @@ func (c *Client) findVM(path, name string) (*object.VirtualMachine, error) {
 	return f.VirtualMachine(c.ctx, vmPath)
 }
 
-func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirtualDevice) []source.NetworkInfo {
+// generateNetworkInfos returns the network interfaces of the given devices.
+// The boot order maps the device keys to their position in the boot order
+// of the VM, see `getBootOrder`.
+func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirtualDevice, bootOrder map[int32]int32) []source.NetworkInfo {
 	result := make([]source.NetworkInfo, 0, len(devices))
 
 	for _, d := range devices {
@@ -505,6 +730,7 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 			result = append(result, source.NetworkInfo{
 				NetworkName: summary,
 				MAC:         obj.MacAddress,
+				BootOrder:   bootOrder[obj.Key],
 				Model:       migration.NetworkInterfaceModelVirtio,
 			})
 		case *types.VirtualE1000e:
@@ -516,6 +742,7 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 			result = append(result, source.NetworkInfo{
 				NetworkName: summary,
 				MAC:         obj.MacAddress,
+				BootOrder:   bootOrder[obj.Key],
 				Model:       migration.NetworkInterfaceModelE1000e,
 			})
 		case *types.VirtualE1000:
@@ -527,6 +754,7 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 			result = append(result, source.NetworkInfo{
 				NetworkName: summary,
 				MAC:         obj.MacAddress,
+				BootOrder:   bootOrder[obj.Key],
 				Model:       migration.NetworkInterfaceModelE1000,
 			})
 		case *types.VirtualVmxnet3:
@@ -538,6 +766,7 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 			result = append(result, source.NetworkInfo{
 				NetworkName: summary,
 				MAC:         obj.MacAddress,
+				BootOrder:   bootOrder[obj.Key],
 				Model:       migration.NetworkInterfaceModelVirtio,
 			})
 		case *types.VirtualVmxnet2:
@@ -549,6 +778,7 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 			result = append(result, source.NetworkInfo{
 				NetworkName: summary,
 				MAC:         obj.MacAddress,
+				BootOrder:   bootOrder[obj.Key],
 				Model:       migration.NetworkInterfaceModelVirtio,
 			})
 		case *types.VirtualPCNet32:
@@ -560,6 +790,7 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 			result = append(result, source.NetworkInfo{
 				NetworkName: summary,
 				MAC:         obj.MacAddress,
+				BootOrder:   bootOrder[obj.Key],
 				Model:       migration.NetworkInterfaceModelPcnet,
 			})
 		}
@@ -568,6 +799,215 @@ func generateNetworkInfos(networkMap map[string]string, devices []types.BaseVirt
 	return result
 }
 
+// getDiskDatastorePath returns the datastore path of the file backing the
+// given disk, or false if it is unknown.
+func getDiskDatastorePath(disk *types.VirtualDisk) (object.DatastorePath, bool) {
+	var dsPath object.DatastorePath
+
+	backing, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo)
+	if !ok {
+		return dsPath, false
+	}
+
+	return dsPath, dsPath.FromString(backing.GetVirtualDeviceFileBackingInfo().FileName)
+}
+
+// getControllers returns the controllers of the VM by their device key.
+func getControllers(o *mo.VirtualMachine) map[int32]types.BaseVirtualDevice {
+	controllers := map[int32]types.BaseVirtualDevice{}
+	for _, dev := range o.Config.Hardware.Device {
+		if _, ok := dev.(types.BaseVirtualController); ok {
+			controllers[dev.GetVirtualDevice().Key] = dev
+		}
+	}
+	return controllers
+}
+
+// getDeviceAddress returns the controller bus and unit number of the given
+// device, together with its device ID in the same format as in the export
+// lease, e.g. `VirtualLsiLogicController0:0`.
+func getDeviceAddress(controllers map[int32]types.BaseVirtualDevice, dev types.BaseVirtualDevice) (diskKey, string, bool) {
+	vd := dev.GetVirtualDevice()
+	if vd.UnitNumber == nil {
+		return diskKey{}, "", false
+	}
+
+	ctrl, ok := controllers[vd.ControllerKey]
+	if !ok {
+		logrus.WithFields(logrus.Fields{
+			"controllerKey": vd.ControllerKey,
+			"deviceKey":     vd.Key,
+		}).Warn("Controller not found for device")
+		return diskKey{}, "", false
+	}
+
+	key := diskKey{
+		bus:  ctrl.(types.BaseVirtualController).GetVirtualController().BusNumber,
+		unit: *vd.UnitNumber,
+	}
+	deviceID := fmt.Sprintf("%s%d:%d", reflect.TypeOf(ctrl).Elem().Name(), key.bus, key.unit)
+
+	return key, deviceID, true
+}
+
+// getBootOrder returns the position of the disks, CD-ROM drives and network
+// interfaces in the boot order of the VM, starting at 1, by device key.
+// The CD-ROM entry of the boot order does not reference a device, so it is
+// assigned to the first CD-ROM drive that is backed by an ISO image, or the
+// first CD-ROM drive if there is none. Floppy drives are not part of the
+// result.
+func getBootOrder(o *mo.VirtualMachine) map[int32]int32 {
+	bootOrder := map[int32]int32{}
+
+	if o.Config == nil || o.Config.BootOptions == nil {
+		return bootOrder
+	}
+
+	for _, bd := range o.Config.BootOptions.BootOrder {
+		switch d := bd.(type) {
+		case *types.VirtualMachineBootOptionsBootableDiskDevice:
+			bootOrder[d.DeviceKey] = int32(len(bootOrder) + 1) // nolint:gosec
+		case *types.VirtualMachineBootOptionsBootableEthernetDevice:
+			bootOrder[d.DeviceKey] = int32(len(bootOrder) + 1) // nolint:gosec
+		case *types.VirtualMachineBootOptionsBootableCdromDevice:
+			if key, ok := getBootCDROMKey(o); ok {
+				if _, found := bootOrder[key]; !found {
+					bootOrder[key] = int32(len(bootOrder) + 1) // nolint:gosec
+				}
+			}
+		}
+	}
+
+	return bootOrder
+}
+
+// getBootCDROMKey returns the device key of the CD-ROM drive the VM boots
+// from, i.e. the first CD-ROM drive that is backed by an ISO image, or the
+// first CD-ROM drive if there is none.
+func getBootCDROMKey(o *mo.VirtualMachine) (int32, bool) {
+	var key int32
+	found := false
+
+	for _, dev := range o.Config.Hardware.Device {
+		cd, ok := dev.(*types.VirtualCdrom)
+		if !ok {
+			continue
+		}
+		if _, ok := cd.Backing.(*types.VirtualCdromIsoBackingInfo); ok {
+			return cd.Key, true
+		}
+		if !found {
+			key, found = cd.Key, true
+		}
+	}
+
+	return key, found
+}
+
+// generateDiskInfos returns the disks of the VM in the order of their
+// devices, together with a map from the controller bus and unit number of
+// each disk to its position in the list.
+// The first disk in the boot order of the VM is marked as boot disk. If the
+// boot order does not contain a disk, the first disk is used.
+func generateDiskInfos(o *mo.VirtualMachine, defaultDiskBusType kubevirt.DiskBus) ([]migration.DiskInfo, map[diskKey]int) {
+	dis := make([]migration.DiskInfo, 0)
+	diskByBusUnit := map[diskKey]int{}
+
+	if o.Config == nil {
+		return dis, diskByBusUnit
+	}
+
+	bootOrder := getBootOrder(o)
+	bootDiskIndex := -1
+
+	// it's rare, but we cannot ensure the controller will always be before the disk in the list of devices
+	// so the controllers are collected first
+	controllers := getControllers(o)
+	for _, dev := range o.Config.Hardware.Device {
+		d, ok := dev.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+
+		key, deviceID, ok := getDeviceAddress(controllers, dev)
+		if !ok {
+			continue
+		}
+
+		di := migration.DiskInfo{
+			DiskSize:  d.CapacityInKB * 1024,
+			BusType:   detectDiskBusType(deviceID, defaultDiskBusType),
+			Index:     int32(len(dis)), // nolint:gosec
+			DeviceID:  deviceID,
+			BootOrder: bootOrder[d.Key],
+		}
+		if di.BootOrder > 0 && (bootDiskIndex < 0 || di.BootOrder < dis[bootDiskIndex].BootOrder) {
+			bootDiskIndex = len(dis)
+		}
+		if dsPath, ok := getDiskDatastorePath(d); ok {
+			di.Datastore = dsPath.Datastore
+			di.FileName = path.Base(dsPath.Path)
+		}
+
+		diskByBusUnit[key] = len(dis)
+		dis = append(dis, di)
+	}
+
+	if len(dis) > 0 {
+		dis[max(bootDiskIndex, 0)].BootDisk = true
+	}
+
+	return dis, diskByBusUnit
+}
+
+// generateCDROMInfos returns the CD-ROM drives of the VM in the order of
+// their devices, together with a map from the controller bus and unit
+// number of each drive to its position in the list. The file name is set
+// for drives that are backed by an ISO image, the boot order for the drive
+// the VM boots from.
+func generateCDROMInfos(o *mo.VirtualMachine) ([]migration.DiskInfo, map[diskKey]int) {
+	cds := make([]migration.DiskInfo, 0)
+	cdromByBusUnit := map[diskKey]int{}
+
+	if o.Config == nil {
+		return cds, cdromByBusUnit
+	}
+
+	bootOrder := getBootOrder(o)
+	controllers := getControllers(o)
+	for _, dev := range o.Config.Hardware.Device {
+		cd, ok := dev.(*types.VirtualCdrom)
+		if !ok {
+			continue
+		}
+
+		key, deviceID, ok := getDeviceAddress(controllers, dev)
+		if !ok {
+			continue
+		}
+
+		di := migration.DiskInfo{
+			BusType:   util.CDROMBusType(detectDiskBusType(deviceID, kubevirt.DiskBusSATA)),
+			Index:     int32(len(cds)), // nolint:gosec
+			DeviceID:  deviceID,
+			CDROM:     true,
+			BootOrder: bootOrder[cd.Key],
+		}
+		if backing, ok := cd.Backing.(*types.VirtualCdromIsoBackingInfo); ok {
+			var dsPath object.DatastorePath
+			if dsPath.FromString(backing.FileName) {
+				di.Datastore = dsPath.Datastore
+				di.FileName = path.Base(dsPath.Path)
+			}
+		}
+
+		cdromByBusUnit[key] = len(cds)
+		cds = append(cds, di)
+	}
+
+	return cds, cdromByBusUnit
+}
+
 // detectDiskBusType tries to identify the disk bus type from VMware to attempt and
 // set correct bus types in KubeVirt. Defaults to the specified bus type in `def`
 // if auto-detection fails.
@@ -694,26 +1134,154 @@ func identifyNetworkName(networkMap map[string]string, device types.VirtualDevic
 	return summary
 }
 
-func (c *Client) ListNetworks() error {
-	mgr := view.NewManager(c.Client.Client)
-
-	v, err := mgr.CreateContainerView(c.ctx, c.ServiceContent.RootFolder, []string{"Network"}, true)
+// ListNetworks returns the networks of the datacenter, i.e. standard port
+// groups, distributed port groups and NSX segments. Uplink port groups are
+// skipped because VMs cannot be connected to them.
+func (c *Client) ListNetworks() ([]migration.SourceNetwork, error) {
+	f := find.NewFinder(c.Client.Client, true)
+	dc := c.dc
+	if !strings.HasPrefix(c.dc, "/") {
+		dc = fmt.Sprintf("/%s", c.dc)
+	}
+	dcObj, err := f.Datacenter(c.ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := dcObj.Folders(c.ctx)
 	if err != nil {
-		return fmt.Errorf("error creating view %v", err)
+		return nil, fmt.Errorf("error fetching datacenter folders: %v", err)
 	}
 
+	mgr := view.NewManager(c.Client.Client)
+	v, err := mgr.CreateContainerView(c.ctx, folders.NetworkFolder.Reference(), []string{"Network"}, true)
+	if err != nil {
+		return nil, fmt.Errorf("error creating view %v", err)
+	}
 	defer v.Destroy(c.ctx) //nolint:errcheck
+
 	var networks []mo.Network
-	err = v.Retrieve(c.ctx, []string{"Network"}, nil, &networks)
+	if err := v.Retrieve(c.ctx, []string{"Network"}, []string{"name"}, &networks); err != nil {
+		return nil, fmt.Errorf("error fetching networks: %v", err)
+	}
+
+	var portgroups []mo.DistributedVirtualPortgroup
+	if err := v.Retrieve(c.ctx, []string{"DistributedVirtualPortgroup"}, []string{"config"}, &portgroups); err != nil {
+		return nil, fmt.Errorf("error fetching distributed port groups: %v", err)
+	}
+	portgroupConfigs := make(map[string]types.DVPortgroupConfigInfo, len(portgroups))
+	for _, pg := range portgroups {
+		portgroupConfigs[pg.Reference().Value] = pg.Config
+	}
+
+	hv, err := mgr.CreateContainerView(c.ctx, folders.HostFolder.Reference(), []string{"HostSystem"}, true)
 	if err != nil {
-		return fmt.Errorf("error fetching networks: %v", err)
+		return nil, fmt.Errorf("error creating view %v", err)
 	}
+	defer hv.Destroy(c.ctx) //nolint:errcheck
 
-	for _, net := range networks {
-		fmt.Printf("%s: %s\n", net.Name, net.Reference())
+	var hosts []mo.HostSystem
+	if err := hv.Retrieve(c.ctx, []string{"HostSystem"}, []string{"config.network.portgroup"}, &hosts); err != nil {
+		return nil, fmt.Errorf("error fetching host port groups: %v", err)
 	}
+	hostPortGroupVlans := generateHostPortGroupVlans(hosts)
+
+	result := make([]migration.SourceNetwork, 0, len(networks))
+	for _, n := range networks {
+		sn := migration.SourceNetwork{
+			Name: n.Name,
+			ID:   n.Reference().Value,
+			Type: n.Reference().Type,
+		}
 
-	return nil
+		switch sn.Type {
+		case "DistributedVirtualPortgroup":
+			cfg := portgroupConfigs[sn.ID]
+			if ptr.Deref(cfg.Uplink, false) {
+				continue
+			}
+			var vlan types.BaseVmwareDistributedVirtualSwitchVlanSpec
+			if setting, ok := cfg.DefaultPortConfig.(*types.VMwareDVSPortSetting); ok {
+				vlan = setting.Vlan
+			}
+			sn.VlanID, sn.VlanType = distributedPortGroupVlan(vlan)
+		case "Network":
+			if vlanID, ok := hostPortGroupVlans[sn.Name]; ok {
+				sn.VlanID, sn.VlanType = hostPortGroupVlan(vlanID)
+			} else {
+				sn.VlanType = migration.VlanTypeUnknown
+			}
+		}
+
+		if sn.VlanType == migration.VlanTypeTrunk || sn.VlanType == migration.VlanTypeUnknown {
+			logrus.WithFields(logrus.Fields{
+				"network":  sn.Name,
+				"id":       sn.ID,
+				"vlanType": sn.VlanType,
+			}).Info("The VLAN ID of the network cannot be determined")
+		}
+
+		result = append(result, sn)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// vlanIDTrunk is the VLAN ID of a standard port group that passes all VLANs
+// to the guest (virtual guest tagging).
+const vlanIDTrunk = 4095
+
+// generateHostPortGroupVlans returns the VLAN IDs of the standard port groups
+// of the given hosts by name. A port group whose VLAN ID differs between the
+// hosts is reported with the VLAN ID -1.
+func generateHostPortGroupVlans(hosts []mo.HostSystem) map[string]int32 {
+	result := make(map[string]int32)
+	for _, h := range hosts {
+		if h.Config == nil || h.Config.Network == nil {
+			continue
+		}
+		for _, pg := range h.Config.Network.Portgroup {
+			if vlanID, ok := result[pg.Spec.Name]; ok && vlanID != pg.Spec.VlanId {
+				result[pg.Spec.Name] = -1
+				continue
+			}
+			result[pg.Spec.Name] = pg.Spec.VlanId
+		}
+	}
+	return result
+}
+
+// hostPortGroupVlan returns the VLAN ID and the VLAN type of a standard port
+// group with the given VLAN ID.
+func hostPortGroupVlan(vlanID int32) (int32, string) {
+	switch {
+	case vlanID == 0:
+		return 0, migration.VlanTypeNone
+	case vlanID == vlanIDTrunk:
+		return 0, migration.VlanTypeTrunk
+	case vlanID > 0 && vlanID < vlanIDTrunk:
+		return vlanID, migration.VlanTypeVlan
+	default:
+		return 0, migration.VlanTypeUnknown
+	}
+}
+
+// distributedPortGroupVlan returns the VLAN ID and the VLAN type of a
+// distributed port group with the given VLAN specification.
+func distributedPortGroupVlan(spec types.BaseVmwareDistributedVirtualSwitchVlanSpec) (int32, string) {
+	switch v := spec.(type) {
+	case *types.VmwareDistributedVirtualSwitchVlanIdSpec:
+		return hostPortGroupVlan(v.VlanId)
+	case *types.VmwareDistributedVirtualSwitchTrunkVlanSpec:
+		return 0, migration.VlanTypeTrunk
+	case *types.VmwareDistributedVirtualSwitchPvlanSpec:
+		return 0, migration.VlanTypePrivate
+	default:
+		return 0, migration.VlanTypeUnknown
+	}
 }
 
 // isPoweredOff checks if the given VM is powered off.
@@ -737,6 +1305,196 @@ func getFirmwareSettings(o *mo.VirtualMachine) *source.Firmware {
 	return fw
 }
 
+// cpuIDFeatures maps the CPUID level, register and bit of a CPU feature to
+// its name in libvirt. Only features that are commonly masked in vSphere,
+// e.g. for EVC or nested virtualization, are mapped.
+var cpuIDFeatures = map[uint32]map[string]map[int]string{
+	0x1: {
+		"ecx": {0: "pni", 3: "monitor", 5: "vmx", 9: "ssse3", 12: "fma", 13: "cx16", 19: "sse4.1", 20: "sse4.2",
+			22: "movbe", 23: "popcnt", 25: "aes", 26: "xsave", 28: "avx", 29: "f16c", 30: "rdrand", 31: "hypervisor"},
+		"edx": {25: "sse", 26: "sse2"},
+	},
+	0x7: {
+		"ebx": {3: "bmi1", 5: "avx2", 8: "bmi2", 9: "erms", 16: "avx512f", 18: "rdseed", 19: "adx", 29: "sha-ni"},
+	},
+	0x80000001: {
+		"ecx": {2: "svm", 5: "abm", 6: "sse4a"},
+		"edx": {20: "nx", 27: "rdtscp"},
+	},
+}
+
+// getCPUFeatures returns the CPU features that are hidden or forced by the
+// CPUID masks of the VM. A mask consists of 32 characters for the bits 31 to
+// 0 of a register, optionally grouped by colons. A `0` hides the feature of
+// the bit and a `1` forces it, all other characters leave it to the host.
+func getCPUFeatures(masks []types.HostCpuIdInfo) []kubevirt.CPUFeature {
+	var features []kubevirt.CPUFeature
+
+	for _, m := range masks {
+		registers, ok := cpuIDFeatures[uint32(m.Level)] // nolint:gosec
+		if !ok {
+			continue
+		}
+		for _, r := range []struct {
+			name string
+			mask string
+		}{{"eax", m.Eax}, {"ebx", m.Ebx}, {"ecx", m.Ecx}, {"edx", m.Edx}} {
+			mask := strings.ReplaceAll(r.mask, ":", "")
+			if len(mask) != 32 {
+				continue
+			}
+			for bit := 31; bit >= 0; bit-- {
+				name, ok := registers[r.name][bit]
+				if !ok {
+					continue
+				}
+				switch mask[31-bit] {
+				case '0':
+					features = append(features, kubevirt.CPUFeature{Name: name, Policy: "disable"})
+				case '1':
+					features = append(features, kubevirt.CPUFeature{Name: name, Policy: "force"})
+				}
+			}
+		}
+	}
+
+	return features
+}
+
+// getCPUSettings returns the CPU settings of the VM. The CPU reservation
+// and limit of the VM are given in MHz and converted to CPUs using the
+// clock rate of the host, memory reservations are given in MB. A limit of
+// -1 means unlimited.
+func getCPUSettings(o *mo.VirtualMachine, hostCPUMhz int32) source.CPUSettings {
+	cpu := source.CPUSettings{}
+
+	if o.Config == nil {
+		return cpu
+	}
+
+	cpu.Features = getCPUFeatures(o.Config.CpuFeatureMask)
+
+	if o.Config.LatencySensitivity != nil {
+		cpu.DedicatedCPUs = o.Config.LatencySensitivity.Level == types.LatencySensitivitySensitivityLevelHigh
+	}
+	if o.Config.NumaInfo != nil {
+		coresPerNode := ptr.Deref(o.Config.NumaInfo.CoresPerNumaNode, 0)
+		if coresPerNode > 0 && o.Config.Hardware.NumCPU > coresPerNode {
+			cpu.NUMANodes = uint32(o.Config.Hardware.NumCPU / coresPerNode) // nolint:gosec
+		}
+	}
+
+	if a := o.Config.CpuAllocation; a != nil && hostCPUMhz > 0 {
+		if r := ptr.Deref(a.Reservation, 0); r > 0 {
+			cpu.CPUReservation = resource.NewMilliQuantity(r*1000/int64(hostCPUMhz), resource.DecimalSI)
+		}
+		if l := ptr.Deref(a.Limit, -1); l > 0 {
+			cpu.CPULimit = resource.NewMilliQuantity(l*1000/int64(hostCPUMhz), resource.DecimalSI)
+		}
+	}
+	if a := o.Config.MemoryAllocation; a != nil {
+		if r := ptr.Deref(a.Reservation, 0); r > 0 {
+			cpu.MemoryReservation = ptr.To(resource.MustParse(fmt.Sprintf("%dM", r)))
+		}
+	}
+
+	return cpu
+}
+
+// getHostCPUMhz returns the clock rate of the CPUs of the host the VM is
+// running on, or 0 if it is not known.
+func (c *Client) getHostCPUMhz(o *mo.VirtualMachine) int32 {
+	if o.Runtime.Host == nil {
+		return 0
+	}
+
+	var h mo.HostSystem
+	err := property.DefaultCollector(c.Client.Client).RetrieveOne(c.ctx, *o.Runtime.Host, []string{"summary.hardware"}, &h)
+	if err != nil || h.Summary.Hardware == nil {
+		return 0
+	}
+
+	return h.Summary.Hardware.CpuMhz
+}
+
+// getTags returns the names of the tags that are attached to the given VM
+// by the name of their category. Tags are managed by the vSphere Automation
+// API, which requires a separate session.
+func (c *Client) getTags(ref types.ManagedObjectReference) (map[string][]string, error) {
+	rc := rest.NewClient(c.Client.Client)
+	if err := rc.Login(c.ctx, c.userinfo); err != nil {
+		return nil, fmt.Errorf("error during login to the vSphere Automation API: %w", err)
+	}
+	defer func() {
+		_ = rc.Logout(c.ctx)
+	}()
+
+	m := tags.NewManager(rc)
+	attached, err := m.GetAttachedTags(c.ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := map[string]string{}
+	result := map[string][]string{}
+	for _, t := range attached {
+		category, ok := categories[t.CategoryID]
+		if !ok {
+			cat, err := m.GetCategory(c.ctx, t.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			category = cat.Name
+			categories[t.CategoryID] = category
+		}
+		result[category] = append(result[category], t.Name)
+	}
+
+	return result, nil
+}
+
+// generateMetadata returns the notes, the custom attributes and the tags of
+// the VM. Multiple tags of the same category are joined by commas.
+func generateMetadata(o *mo.VirtualMachine, vmTags map[string][]string) source.Metadata {
+	md := source.Metadata{}
+
+	if o.Config != nil && o.Config.Annotation != "" {
+		md[source.MetadataKeyDescription] = o.Config.Annotation
+	}
+
+	fields := make(map[int32]string, len(o.AvailableField))
+	for _, f := range o.AvailableField {
+		fields[f.Key] = f.Name
+	}
+	for _, cv := range o.CustomValue {
+		v, ok := cv.(*types.CustomFieldStringValue)
+		if !ok {
+			continue
+		}
+		if name, ok := fields[v.Key]; ok {
+			md[source.MetadataPrefixAttribute+name] = v.Value
+		}
+	}
+
+	for category, names := range vmTags {
+		slices.Sort(names)
+		md[source.MetadataPrefixTag+category] = strings.Join(names, ",")
+	}
+
+	return md
+}
+
+// findCDROM returns the position of the CD-ROM drive with the given lease
+// device ID in the list of CD-ROM drives.
+func findCDROM(cdromByBusUnit map[diskKey]int, deviceId string) (int, bool) {
+	bus, unit, ok := parseDeviceId(deviceId)
+	if !ok {
+		return 0, false
+	}
+	idx, ok := cdromByBusUnit[diskKey{bus: bus, unit: unit}]
+	return idx, ok
+}
+
 // diskKey uniquely identifies a disk in the VM hardware topology
 // using the (controller bus, unit) address.
 type diskKey struct {
@@ -81,6 +81,143 @@ func Test_NewClient(t *testing.T) {
 	assert.NoError(err, "expected no error during creation of client")
 	err = c.Verify()
 	assert.NoError(err, "expected no error during verification of client")
+	assert.NotEmpty(c.Version(), "expected version of vcsim to be reported")
+}
+
+func Test_ListVirtualMachines(t *testing.T) {
+	ctx := context.TODO()
+	endpoint := fmt.Sprintf("https://localhost:%s/sdk", vcsimPort)
+	dc := "DC0"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte("pass"),
+		},
+	}
+
+	c, err := NewClient(ctx, endpoint, dc, secret)
+	assert := require.New(t)
+	assert.NoError(err, "expected no error during creation of client")
+
+	vms, err := c.ListVirtualMachines()
+	assert.NoError(err, "expected no error during listing of VMs")
+	assert.NotEmpty(vms, "expected VMs to be listed")
+
+	var found *migration.InventoryVirtualMachine
+	for i := range vms {
+		if vms[i].Name == "DC0_H0_VM0" {
+			found = &vms[i]
+		}
+	}
+	assert.NotNil(found, "expected VM DC0_H0_VM0 to be listed")
+	assert.NotEmpty(found.ID, "expected VM ID to be set")
+	assert.Equal("poweredOn", found.PowerState, "expected VM to be powered on")
+	assert.Equal(migration.FirmwareBIOS, found.Firmware, "expected BIOS firmware")
+	assert.NotEmpty(found.Disks, "expected disks to be listed")
+	assert.NotEmpty(found.NetworkInterfaces, "expected network interfaces to be listed")
+}
+
+func Test_ListNetworks(t *testing.T) {
+	ctx := context.TODO()
+	endpoint := fmt.Sprintf("https://localhost:%s/sdk", vcsimPort)
+	dc := "DC0"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte("pass"),
+		},
+	}
+
+	c, err := NewClient(ctx, endpoint, dc, secret)
+	assert := require.New(t)
+	assert.NoError(err, "expected no error during creation of client")
+
+	networks, err := c.ListNetworks()
+	assert.NoError(err, "expected no error during listing of networks")
+	networkTypes := make(map[string]string, len(networks))
+	for _, n := range networks {
+		assert.NotEmpty(n.ID, "expected network ID to be set")
+		networkTypes[n.Name] = n.Type
+	}
+	assert.Equal("Network", networkTypes["VM Network"], "expected standard port group to be listed")
+	assert.Equal("DistributedVirtualPortgroup", networkTypes["DC0_DVPG0"], "expected distributed port group to be listed")
+	for _, n := range networks {
+		assert.NotEmpty(n.VlanType, "expected VLAN type of network %s to be set", n.Name)
+	}
+}
+
+func Test_generateHostPortGroupVlans(t *testing.T) {
+	assert := require.New(t)
+	newHost := func(portgroups ...types.HostPortGroup) mo.HostSystem {
+		return mo.HostSystem{
+			Config: &types.HostConfigInfo{
+				Network: &types.HostNetworkInfo{Portgroup: portgroups},
+			},
+		}
+	}
+
+	vlans := generateHostPortGroupVlans([]mo.HostSystem{
+		newHost(
+			types.HostPortGroup{Spec: types.HostPortGroupSpec{Name: "VM Network"}},
+			types.HostPortGroup{Spec: types.HostPortGroupSpec{Name: "VLAN 100", VlanId: 100}},
+			types.HostPortGroup{Spec: types.HostPortGroupSpec{Name: "Mixed", VlanId: 10}},
+		),
+		newHost(
+			types.HostPortGroup{Spec: types.HostPortGroupSpec{Name: "VLAN 100", VlanId: 100}},
+			types.HostPortGroup{Spec: types.HostPortGroupSpec{Name: "Mixed", VlanId: 20}},
+		),
+		{},
+	})
+	assert.Equal(map[string]int32{"VM Network": 0, "VLAN 100": 100, "Mixed": -1}, vlans)
+}
+
+func Test_networkVlan(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc         string
+		vlanID       int32
+		vlanType     string
+		expectedID   int32
+		expectedType string
+	}{
+		{desc: "Untagged standard port group", vlanID: 0, expectedType: migration.VlanTypeNone},
+		{desc: "Standard port group with VLAN", vlanID: 100, expectedID: 100, expectedType: migration.VlanTypeVlan},
+		{desc: "Standard port group with guest tagging", vlanID: 4095, expectedType: migration.VlanTypeTrunk},
+		{desc: "Standard port group with conflicting VLANs", vlanID: -1, expectedType: migration.VlanTypeUnknown},
+	}
+
+	for _, tc := range testCases {
+		vlanID, vlanType := hostPortGroupVlan(tc.vlanID)
+		assert.Equal(tc.expectedID, vlanID, tc.desc)
+		assert.Equal(tc.expectedType, vlanType, tc.desc)
+	}
+
+	dvsTestCases := []struct {
+		desc         string
+		spec         types.BaseVmwareDistributedVirtualSwitchVlanSpec
+		expectedID   int32
+		expectedType string
+	}{
+		{desc: "VLAN ID", spec: &types.VmwareDistributedVirtualSwitchVlanIdSpec{VlanId: 200}, expectedID: 200, expectedType: migration.VlanTypeVlan},
+		{desc: "No VLAN", spec: &types.VmwareDistributedVirtualSwitchVlanIdSpec{}, expectedType: migration.VlanTypeNone},
+		{desc: "Trunk", spec: &types.VmwareDistributedVirtualSwitchTrunkVlanSpec{}, expectedType: migration.VlanTypeTrunk},
+		{desc: "Private VLAN", spec: &types.VmwareDistributedVirtualSwitchPvlanSpec{PvlanId: 10}, expectedType: migration.VlanTypePrivate},
+		{desc: "Not set", expectedType: migration.VlanTypeUnknown},
+	}
+
+	for _, tc := range dvsTestCases {
+		vlanID, vlanType := distributedPortGroupVlan(tc.spec)
+		assert.Equal(tc.expectedID, vlanID, tc.desc)
+		assert.Equal(tc.expectedType, vlanType, tc.desc)
+	}
 }
 
 func Test_PowerOff(t *testing.T) {
@@ -428,28 +565,36 @@ func Test_identifyNetworkCards(t *testing.T) {
 	err = vmObj.Properties(c.ctx, vmObj.Reference(), []string{}, &o)
 	assert.NoError(err, "expected no error looking up vmObj properties")
 
-	networkInfo := generateNetworkInfos(c.networkMapping, o.Config.Hardware.Device)
+	networkInfo := generateNetworkInfos(c.networkMapping, o.Config.Hardware.Device, getBootOrder(&o))
 	assert.Len(networkInfo, 1, "expected to find only 1 item in the networkInfo")
 	t.Log(networkInfo)
-	networkMapping := []migration.NetworkMapping{
-		{
-			SourceNetwork:      "dummyNetwork",
-			DestinationNetwork: "harvester1",
-		},
-		{
-			NetworkInterfaceModel: ptr.To(migration.NetworkInterfaceModelRtl8139),
-			SourceNetwork:         "DC0_DVPG0",
-			DestinationNetwork:    "pod-network",
+	vm := &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			Mapping: []migration.NetworkMapping{
+				{
+					SourceNetwork:      "dummyNetwork",
+					DestinationNetwork: "harvester1",
+				},
+				{
+					NetworkInterfaceModel: ptr.To(migration.NetworkInterfaceModelRtl8139),
+					SourceNetwork:         "DC0_DVPG0",
+					DestinationNetwork:    "pod-network",
+				},
+			},
 		},
 	}
 
-	mappedInfo := source.MapNetworks(networkInfo, networkMapping)
+	mappedInfo, err := source.MapNetworkInterfaces(networkInfo, vm)
+	assert.NoError(err, "expected no error during network mapping")
 	assert.Len(mappedInfo, 1, "expected to find only 1 item in the mapped networkinfo")
+	assert.Equal("pod-network", mappedInfo[0].MappedNetwork, "expected the NIC to be mapped")
 	assert.Equal(mappedInfo[0].Model, "rtl8139", "expected to have a NIC with rtl8139 model")
 
-	noNetworkMapping := []migration.NetworkMapping{}
-	noMappedInfo := source.MapNetworks(networkInfo, noNetworkMapping)
-	assert.Len(noMappedInfo, 0, "expected to find no item in the mapped networkinfo")
+	vm.Spec.Mapping = []migration.NetworkMapping{}
+	noMappedInfo, err := source.MapNetworkInterfaces(networkInfo, vm)
+	assert.NoError(err, "expected no error during network mapping")
+	assert.Len(noMappedInfo, 1, "expected the unmapped NIC to be part of the mapped networkinfo")
+	assert.Empty(noMappedInfo[0].MappedNetwork, "expected the unmapped NIC to be dropped")
 }
 
 func Test_detectDiskBusType(t *testing.T) {
@@ -601,3 +746,267 @@ func TestParseDeviceId(t *testing.T) {
 		})
 	}
 }
+
+func Test_generateDiskInfos(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc       string
+		controller types.BaseVirtualDevice
+		unit       int32
+		deviceID   string
+		busType    kubevirtv1.DiskBus
+	}{
+		{
+			desc:       "ParaVirtual SCSI controller",
+			controller: &types.ParaVirtualSCSIController{VirtualSCSIController: types.VirtualSCSIController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 1000}, BusNumber: 0}}},
+			unit:       1,
+			deviceID:   "ParaVirtualSCSIController0:1",
+			busType:    kubevirtv1.DiskBusSATA,
+		},
+		{
+			desc:       "LSI Logic controller",
+			controller: &types.VirtualLsiLogicController{VirtualSCSIController: types.VirtualSCSIController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 1000}, BusNumber: 0}}},
+			unit:       0,
+			deviceID:   "VirtualLsiLogicController0:0",
+			busType:    kubevirtv1.DiskBusSCSI,
+		},
+		{
+			desc:       "LSI Logic SAS controller",
+			controller: &types.VirtualLsiLogicSASController{VirtualSCSIController: types.VirtualSCSIController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 1001}, BusNumber: 1}}},
+			unit:       0,
+			deviceID:   "VirtualLsiLogicSASController1:0",
+			busType:    kubevirtv1.DiskBusSCSI,
+		},
+		{
+			desc:       "BusLogic controller",
+			controller: &types.VirtualBusLogicController{VirtualSCSIController: types.VirtualSCSIController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 1000}, BusNumber: 0}}},
+			unit:       2,
+			deviceID:   "VirtualBusLogicController0:2",
+			busType:    kubevirtv1.DiskBusSCSI,
+		},
+		{
+			desc:       "AHCI controller",
+			controller: &types.VirtualAHCIController{VirtualSATAController: types.VirtualSATAController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 15000}, BusNumber: 0}}},
+			unit:       1,
+			deviceID:   "VirtualAHCIController0:1",
+			busType:    kubevirtv1.DiskBusSATA,
+		},
+		{
+			desc:       "IDE controller",
+			controller: &types.VirtualIDEController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 201}, BusNumber: 1}},
+			unit:       0,
+			deviceID:   "VirtualIDEController1:0",
+			busType:    kubevirtv1.DiskBusSATA,
+		},
+		{
+			desc:       "NVMe controller",
+			controller: &types.VirtualNVMEController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 31000}, BusNumber: 0}},
+			unit:       0,
+			deviceID:   "VirtualNVMEController0:0",
+			busType:    kubevirtv1.DiskBusVirtio,
+		},
+	}
+
+	for _, tc := range testCases {
+		controllerKey := tc.controller.GetVirtualDevice().Key
+		o := &mo.VirtualMachine{
+			Config: &types.VirtualMachineConfigInfo{
+				Hardware: types.VirtualHardware{
+					Device: []types.BaseVirtualDevice{
+						&types.VirtualDisk{
+							VirtualDevice: types.VirtualDevice{
+								Key:           2000,
+								ControllerKey: controllerKey,
+								UnitNumber:    ptr.To(tc.unit),
+								Backing: &types.VirtualDiskFlatVer2BackingInfo{
+									VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{FileName: "[datastore1] vm/vm.vmdk"},
+								},
+							},
+							CapacityInKB: 1024,
+						},
+						tc.controller,
+					},
+				},
+			},
+		}
+
+		dis, diskByBusUnit := generateDiskInfos(o, kubevirtv1.DiskBusVirtio)
+		assert.Len(dis, 1, tc.desc)
+		assert.Equal(tc.deviceID, dis[0].DeviceID, tc.desc)
+		assert.Equal(tc.busType, dis[0].BusType, tc.desc)
+		assert.Equal(int64(1024*1024), dis[0].DiskSize, tc.desc)
+		assert.Equal("datastore1", dis[0].Datastore, tc.desc)
+		assert.Equal("vm.vmdk", dis[0].FileName, tc.desc)
+		assert.True(dis[0].BootDisk, tc.desc)
+
+		// The device ID in the export lease resolves to the same disk.
+		bus, unit, ok := parseDeviceId("/vm-42/" + tc.deviceID)
+		assert.True(ok, tc.desc)
+		assert.Equal(0, diskByBusUnit[diskKey{bus: bus, unit: unit}], tc.desc)
+	}
+}
+
+func Test_generateDiskInfos_bootDisk(t *testing.T) {
+	assert := require.New(t)
+
+	o := &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			Hardware: types.VirtualHardware{
+				Device: []types.BaseVirtualDevice{
+					&types.ParaVirtualSCSIController{VirtualSCSIController: types.VirtualSCSIController{VirtualController: types.VirtualController{VirtualDevice: types.VirtualDevice{Key: 1000}, BusNumber: 0}}},
+					&types.VirtualDisk{VirtualDevice: types.VirtualDevice{Key: 2000, ControllerKey: 1000, UnitNumber: ptr.To(int32(0))}},
+					&types.VirtualDisk{VirtualDevice: types.VirtualDevice{Key: 2001, ControllerKey: 1000, UnitNumber: ptr.To(int32(1))}},
+					// Disks of unknown controllers are skipped.
+					&types.VirtualDisk{VirtualDevice: types.VirtualDevice{Key: 2002, ControllerKey: 1001, UnitNumber: ptr.To(int32(0))}},
+				},
+			},
+			BootOptions: &types.VirtualMachineBootOptions{
+				BootOrder: []types.BaseVirtualMachineBootOptionsBootableDevice{
+					&types.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: 2001},
+				},
+			},
+		},
+	}
+
+	dis, diskByBusUnit := generateDiskInfos(o, kubevirtv1.DiskBusVirtio)
+	assert.Len(dis, 2)
+	assert.Equal([]string{"ParaVirtualSCSIController0:0", "ParaVirtualSCSIController0:1"}, []string{dis[0].DeviceID, dis[1].DeviceID})
+	assert.Equal([]int32{0, 1}, []int32{dis[0].Index, dis[1].Index})
+	assert.False(dis[0].BootDisk)
+	assert.True(dis[1].BootDisk)
+	assert.Equal(int32(1), dis[1].BootOrder)
+	assert.Equal(map[diskKey]int{{bus: 0, unit: 0}: 0, {bus: 0, unit: 1}: 1}, diskByBusUnit)
+}
+
+func Test_getBootOrder(t *testing.T) {
+	assert := require.New(t)
+
+	o := &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			Hardware: types.VirtualHardware{
+				Device: []types.BaseVirtualDevice{
+					&types.VirtualIDEController{
+						VirtualController: types.VirtualController{
+							VirtualDevice: types.VirtualDevice{Key: 200},
+							BusNumber:     0,
+						},
+					},
+					&types.VirtualCdrom{
+						VirtualDevice: types.VirtualDevice{
+							Key:           3000,
+							ControllerKey: 200,
+							UnitNumber:    ptr.To(int32(0)),
+							Backing:       &types.VirtualCdromRemotePassthroughBackingInfo{},
+						},
+					},
+					&types.VirtualCdrom{
+						VirtualDevice: types.VirtualDevice{
+							Key:           3001,
+							ControllerKey: 200,
+							UnitNumber:    ptr.To(int32(1)),
+							Backing: &types.VirtualCdromIsoBackingInfo{
+								VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{FileName: "[datastore1] iso/install.iso"},
+							},
+						},
+					},
+				},
+			},
+			BootOptions: &types.VirtualMachineBootOptions{
+				BootOrder: []types.BaseVirtualMachineBootOptionsBootableDevice{
+					&types.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: 2000},
+					&types.VirtualMachineBootOptionsBootableCdromDevice{},
+					&types.VirtualMachineBootOptionsBootableEthernetDevice{DeviceKey: 4000},
+				},
+			},
+		},
+	}
+
+	assert.Equal(map[int32]int32{2000: 1, 3001: 2, 4000: 3}, getBootOrder(o), "expected the CD-ROM with the ISO image to be booted from")
+
+	cds, _ := generateCDROMInfos(o)
+	assert.Len(cds, 2)
+	assert.Zero(cds[0].BootOrder, "expected the empty CD-ROM not to be booted from")
+	assert.Equal(int32(2), cds[1].BootOrder, "expected the CD-ROM with the ISO image to be booted from second")
+	assert.Equal("install.iso", cds[1].FileName)
+
+	// Without ISO image, the first CD-ROM drive is booted from.
+	o.Config.Hardware.Device = o.Config.Hardware.Device[:2]
+	assert.Equal(map[int32]int32{2000: 1, 3000: 2, 4000: 3}, getBootOrder(o))
+
+	assert.Empty(getBootOrder(&mo.VirtualMachine{}))
+}
+
+func Test_getCPUSettings(t *testing.T) {
+	assert := require.New(t)
+
+	o := &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			Hardware: types.VirtualHardware{NumCPU: 8},
+			CpuFeatureMask: []types.HostCpuIdInfo{
+				{Level: 1, Ecx: "1xxx:xxxx:xxxx:xxxx:xxxx:xxxx:xx0x:xxxx", Edx: "----:----"},
+				{Level: 0x40000000, Eax: "0000:0000:0000:0000:0000:0000:0000:0000"},
+			},
+			LatencySensitivity: &types.LatencySensitivity{Level: types.LatencySensitivitySensitivityLevelHigh},
+			NumaInfo:           &types.VirtualMachineVirtualNumaInfo{CoresPerNumaNode: ptr.To(int32(4))},
+			CpuAllocation:      &types.ResourceAllocationInfo{Reservation: ptr.To(int64(3000)), Limit: ptr.To(int64(-1))},
+			MemoryAllocation:   &types.ResourceAllocationInfo{Reservation: ptr.To(int64(2048))},
+		},
+	}
+
+	cpu := getCPUSettings(o, 2000)
+	assert.Equal([]kubevirtv1.CPUFeature{
+		{Name: "hypervisor", Policy: "force"},
+		{Name: "vmx", Policy: "disable"},
+	}, cpu.Features)
+	assert.True(cpu.DedicatedCPUs)
+	assert.Equal(uint32(2), cpu.NUMANodes)
+	assert.Equal("1500m", cpu.CPUReservation.String())
+	assert.Nil(cpu.CPULimit)
+	assert.Equal("2048M", cpu.MemoryReservation.String())
+
+	cpu = getCPUSettings(o, 0)
+	assert.Nil(cpu.CPUReservation, "expected no CPU reservation if the clock rate of the host is unknown")
+}
+
+func Test_generateMetadata(t *testing.T) {
+	assert := require.New(t)
+
+	o := &mo.VirtualMachine{
+		ManagedEntity: mo.ManagedEntity{
+			ExtensibleManagedObject: mo.ExtensibleManagedObject{
+				AvailableField: []types.CustomFieldDef{{Key: 101, Name: "Owner"}},
+			},
+			CustomValue: []types.BaseCustomFieldValue{
+				&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 101}, Value: "team-a"},
+				&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 102}, Value: "unknown"},
+			},
+		},
+		Config: &types.VirtualMachineConfigInfo{Annotation: "Web server"},
+	}
+
+	assert.Equal(source.Metadata{
+		source.MetadataKeyDescription:            "Web server",
+		source.MetadataPrefixAttribute + "Owner": "team-a",
+		source.MetadataPrefixTag + "Environment": "prod,staging",
+	}, generateMetadata(o, map[string][]string{"Environment": {"staging", "prod"}}))
+	assert.Empty(generateMetadata(&mo.VirtualMachine{}, nil))
+}
+
+func Test_generateIdentity(t *testing.T) {
+	assert := require.New(t)
+
+	ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"}
+	o := &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			Uuid:         "4212c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+			InstanceUuid: "5012c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+		},
+	}
+
+	assert.Equal(&migration.SourceVirtualMachineIdentity{
+		ID:        "5012c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+		UUID:      "4212c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+		Reference: "vm-42",
+	}, generateIdentity(o, ref))
+	assert.Equal(&migration.SourceVirtualMachineIdentity{Reference: "vm-42"}, generateIdentity(&mo.VirtualMachine{}, ref))
+}
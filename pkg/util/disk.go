@@ -0,0 +1,225 @@
+package util
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// ValidateDiskMapping checks that the given disk mapping has at least one
+// selector.
+func ValidateDiskMapping(dm migration.DiskMapping) error {
+	if dm.Index == nil && dm.DeviceID == "" && dm.Datastore == "" && dm.VolumeType == "" {
+		return fmt.Errorf("%w: disk mapping requires at least one of index, deviceID, datastore or volumeType", ErrInvalidDiskMapping)
+	}
+	return nil
+}
+
+// MatchDiskMapping reports whether the given disk is matched by all
+// selectors of the disk mapping. A disk mapping without selectors never
+// matches.
+func MatchDiskMapping(dm migration.DiskMapping, d migration.DiskInfo) bool {
+	if ValidateDiskMapping(dm) != nil {
+		return false
+	}
+	if dm.Index != nil && *dm.Index != d.Index {
+		return false
+	}
+	if dm.DeviceID != "" && dm.DeviceID != d.DeviceID {
+		return false
+	}
+	if dm.Datastore != "" && dm.Datastore != d.Datastore {
+		return false
+	}
+	if dm.VolumeType != "" && dm.VolumeType != d.VolumeType {
+		return false
+	}
+	return true
+}
+
+// GetDiskMapping returns the first disk mapping of the import that matches
+// the given disk, or nil if there is none. Disk mappings do not apply to
+// CD-ROM images.
+func GetDiskMapping(vm *migration.VirtualMachineImport, d migration.DiskInfo) *migration.DiskMapping {
+	if d.CDROM {
+		return nil
+	}
+	for i := range vm.Spec.DiskMapping {
+		if MatchDiskMapping(vm.Spec.DiskMapping[i], d) {
+			return &vm.Spec.DiskMapping[i]
+		}
+	}
+	return nil
+}
+
+// ValidateDiskSelector checks that the given disk selector has at least
+// one field set.
+func ValidateDiskSelector(ds migration.DiskSelector) error {
+	if ds.Index == nil && ds.DeviceID == "" && ds.FileName == "" && ds.VolumeID == "" {
+		return fmt.Errorf("%w: disk selector requires at least one of index, deviceID, fileName or volumeID", ErrInvalidDiskMapping)
+	}
+	return nil
+}
+
+// MatchDiskSelector reports whether the given disk is matched by all fields
+// of the disk selector. A disk selector without fields never matches.
+func MatchDiskSelector(ds migration.DiskSelector, d migration.DiskInfo) bool {
+	if ValidateDiskSelector(ds) != nil {
+		return false
+	}
+	if ds.Index != nil && *ds.Index != d.Index {
+		return false
+	}
+	if ds.DeviceID != "" && ds.DeviceID != d.DeviceID {
+		return false
+	}
+	if ds.FileName != "" && ds.FileName != d.FileName {
+		return false
+	}
+	if ds.VolumeID != "" && ds.VolumeID != d.VolumeID {
+		return false
+	}
+	return true
+}
+
+// IsDiskSelected reports whether the given disk is selected by the include
+// and exclude lists of the import.
+func IsDiskSelected(vm *migration.VirtualMachineImport, d migration.DiskInfo) bool {
+	for _, ds := range vm.Spec.ExcludeDisks {
+		if MatchDiskSelector(ds, d) {
+			return false
+		}
+	}
+	if len(vm.Spec.IncludeDisks) == 0 {
+		return true
+	}
+	for _, ds := range vm.Spec.IncludeDisks {
+		if MatchDiskSelector(ds, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDiskSkipped reports whether the given disk is excluded from the import,
+// either by the include and exclude lists or by a disk mapping.
+func IsDiskSkipped(vm *migration.VirtualMachineImport, d migration.DiskInfo) bool {
+	if !IsDiskSelected(vm, d) {
+		return true
+	}
+	dm := GetDiskMapping(vm, d)
+	return dm != nil && dm.Skip
+}
+
+// ValidateStorageClassMapping checks that the given storage class mapping
+// of a source has at least one selector and that its patterns are valid.
+func ValidateStorageClassMapping(m migration.StorageClassMapping) error {
+	if m.Datastore == "" && m.VolumeType == "" {
+		return fmt.Errorf("%w: storage class mapping requires a datastore or volumeType", ErrInvalidStorageClassMapping)
+	}
+	for _, pattern := range []string{m.Datastore, m.VolumeType} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: invalid glob pattern '%s': %v", ErrInvalidStorageClassMapping, pattern, err)
+		}
+	}
+	return nil
+}
+
+// MatchStorageClassMapping reports whether the given disk is matched by all
+// patterns of the storage class mapping. Invalid mappings never match.
+func MatchStorageClassMapping(m migration.StorageClassMapping, d migration.DiskInfo) bool {
+	if ValidateStorageClassMapping(m) != nil {
+		return false
+	}
+	if m.Datastore != "" {
+		if ok, _ := path.Match(m.Datastore, d.Datastore); !ok {
+			return false
+		}
+	}
+	if m.VolumeType != "" {
+		if ok, _ := path.Match(m.VolumeType, d.VolumeType); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// GetDiskStorageClass returns the storage class of the given disk. The
+// storage class of a matching disk mapping of the import takes precedence
+// over the first matching storage class mapping of the source, which in
+// turn takes precedence over the storage class of the import. An empty
+// string means the default storage class.
+func GetDiskStorageClass(vm *migration.VirtualMachineImport, d migration.DiskInfo, mappings []migration.StorageClassMapping) string {
+	if dm := GetDiskMapping(vm, d); dm != nil && dm.StorageClass != "" {
+		return dm.StorageClass
+	}
+	for _, m := range mappings {
+		if MatchStorageClassMapping(m, d) {
+			return m.StorageClass
+		}
+	}
+	return vm.Spec.StorageClass
+}
+
+// GetDiskVolumeMode returns the volume mode of the PVC of the given disk.
+func GetDiskVolumeMode(vm *migration.VirtualMachineImport, d migration.DiskInfo) corev1.PersistentVolumeMode {
+	if dm := GetDiskMapping(vm, d); dm != nil {
+		return ptr.Deref(dm.VolumeMode, corev1.PersistentVolumeBlock)
+	}
+	return corev1.PersistentVolumeBlock
+}
+
+// GetDiskAccessMode returns the access mode of the PVC of the given disk.
+func GetDiskAccessMode(vm *migration.VirtualMachineImport, d migration.DiskInfo) corev1.PersistentVolumeAccessMode {
+	if dm := GetDiskMapping(vm, d); dm != nil {
+		return ptr.Deref(dm.AccessMode, corev1.ReadWriteMany)
+	}
+	return corev1.ReadWriteMany
+}
+
+// CDROMBusType returns the bus type of a CD-ROM drive for the given bus type
+// of the source. KubeVirt only supports SATA and SCSI CD-ROM drives, so all
+// other bus types are mapped to SATA.
+func CDROMBusType(bus kubevirtv1.DiskBus) kubevirtv1.DiskBus {
+	if bus == kubevirtv1.DiskBusSCSI {
+		return bus
+	}
+	return kubevirtv1.DiskBusSATA
+}
+
+// DiskBootOrders returns the boot order of each of the given disks in the
+// imported VM, or 0 for disks that are not booted from. Disks and CD-ROM
+// images keep their boot order of the source VM. The remaining disks follow
+// after the last boot order of the source VM, starting with the boot disk.
+// maxBootOrder is the highest boot order of other devices, e.g. network
+// interfaces. CD-ROM images without boot order are not booted from.
+func DiskBootOrders(dis []migration.DiskInfo, maxBootOrder uint) []uint {
+	result := make([]uint, len(dis))
+
+	next := maxBootOrder
+	for i, d := range dis {
+		if d.BootOrder > 0 {
+			result[i] = uint(d.BootOrder)
+			next = max(next, result[i])
+		}
+	}
+
+	assign := func(bootDisk bool) {
+		for i, d := range dis {
+			if d.CDROM || d.BootOrder > 0 || d.BootDisk != bootDisk {
+				continue
+			}
+			next++
+			result[i] = next
+		}
+	}
+	assign(true)
+	assign(false)
+
+	return result
+}
@@ -0,0 +1,241 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_MatchDiskMapping(t *testing.T) {
+	assert := require.New(t)
+
+	disk := migration.DiskInfo{
+		Index:      1,
+		DeviceID:   "2001",
+		Datastore:  "datastore1",
+		VolumeType: "ssd",
+	}
+
+	testCases := []struct {
+		desc     string
+		dm       migration.DiskMapping
+		expected bool
+	}{
+		{
+			desc: "No selector never matches",
+			dm:   migration.DiskMapping{StorageClass: "fast"},
+		},
+		{
+			desc:     "Match by index",
+			dm:       migration.DiskMapping{Index: ptr.To(int32(1))},
+			expected: true,
+		},
+		{
+			desc: "Index mismatch",
+			dm:   migration.DiskMapping{Index: ptr.To(int32(0))},
+		},
+		{
+			desc:     "Match by device ID and datastore",
+			dm:       migration.DiskMapping{DeviceID: "2001", Datastore: "datastore1"},
+			expected: true,
+		},
+		{
+			desc: "All selectors must match",
+			dm:   migration.DiskMapping{DeviceID: "2001", Datastore: "datastore2"},
+		},
+		{
+			desc:     "Match by volume type",
+			dm:       migration.DiskMapping{VolumeType: "ssd"},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, MatchDiskMapping(tc.dm, disk), tc.desc)
+	}
+}
+
+func Test_GetDiskSettings(t *testing.T) {
+	assert := require.New(t)
+
+	vm := &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			StorageClass: "default",
+			DiskMapping: []migration.DiskMapping{
+				{
+					Index:        ptr.To(int32(0)),
+					StorageClass: "fast",
+					VolumeMode:   ptr.To(corev1.PersistentVolumeFilesystem),
+					AccessMode:   ptr.To(corev1.ReadWriteOnce),
+				},
+				{Datastore: "archive", Skip: true},
+				{Datastore: "datastore1", StorageClass: "slow"},
+			},
+		},
+	}
+
+	boot := migration.DiskInfo{Index: 0, Datastore: "datastore1"}
+	assert.Equal("fast", GetDiskStorageClass(vm, boot, nil), "expected the first matching mapping to win")
+	assert.Equal(corev1.PersistentVolumeFilesystem, GetDiskVolumeMode(vm, boot))
+	assert.Equal(corev1.ReadWriteOnce, GetDiskAccessMode(vm, boot))
+	assert.False(IsDiskSkipped(vm, boot))
+
+	data := migration.DiskInfo{Index: 1, Datastore: "datastore1"}
+	assert.Equal("slow", GetDiskStorageClass(vm, data, nil))
+	assert.Equal(corev1.PersistentVolumeBlock, GetDiskVolumeMode(vm, data))
+	assert.Equal(corev1.ReadWriteMany, GetDiskAccessMode(vm, data))
+
+	other := migration.DiskInfo{Index: 2, Datastore: "datastore2"}
+	assert.Equal("default", GetDiskStorageClass(vm, other, nil), "expected fallback to the VM-wide storage class")
+
+	assert.True(IsDiskSkipped(vm, migration.DiskInfo{Index: 3, Datastore: "archive"}))
+
+	cdrom := migration.DiskInfo{Index: 0, CDROM: true}
+	assert.Equal("default", GetDiskStorageClass(vm, cdrom, nil), "expected disk mappings to ignore CD-ROM images")
+}
+
+func Test_GetDiskStorageClassWithStorageClassMapping(t *testing.T) {
+	assert := require.New(t)
+
+	vm := &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			StorageClass: "default",
+			DiskMapping: []migration.DiskMapping{
+				{Index: ptr.To(int32(0)), StorageClass: "fast"},
+			},
+		},
+	}
+	mappings := []migration.StorageClassMapping{
+		{Datastore: "ssd-*", StorageClass: "longhorn-ssd"},
+		{VolumeType: "ceph-hdd", StorageClass: "longhorn-hdd"},
+		{Datastore: "[", StorageClass: "invalid"},
+	}
+
+	assert.Equal("fast", GetDiskStorageClass(vm, migration.DiskInfo{Index: 0, Datastore: "ssd-1"}, mappings),
+		"expected the disk mapping of the import to take precedence")
+	assert.Equal("longhorn-ssd", GetDiskStorageClass(vm, migration.DiskInfo{Index: 1, Datastore: "ssd-1"}, mappings))
+	assert.Equal("longhorn-hdd", GetDiskStorageClass(vm, migration.DiskInfo{Index: 1, VolumeType: "ceph-hdd"}, mappings))
+	assert.Equal("default", GetDiskStorageClass(vm, migration.DiskInfo{Index: 1, Datastore: "hdd-1"}, mappings),
+		"expected fallback to the VM-wide storage class")
+
+	assert.Error(ValidateStorageClassMapping(migration.StorageClassMapping{StorageClass: "longhorn"}))
+	assert.Error(ValidateStorageClassMapping(mappings[2]))
+	assert.NoError(ValidateStorageClassMapping(mappings[0]))
+}
+
+func Test_IsDiskSkipped(t *testing.T) {
+	assert := require.New(t)
+
+	disks := []migration.DiskInfo{
+		{Index: 0, DeviceID: "VirtualLsiLogicController0:0", FileName: "vm.vmdk"},
+		{Index: 1, DeviceID: "VirtualLsiLogicController0:1", FileName: "vm_1.vmdk"},
+		{Index: 2, DeviceID: "/dev/vdc", VolumeID: "8f2c4e9a"},
+	}
+
+	testCases := []struct {
+		desc     string
+		spec     migration.VirtualMachineImportSpec
+		expected []bool
+	}{
+		{
+			desc:     "All disks are imported by default",
+			expected: []bool{false, false, false},
+		},
+		{
+			desc: "Only included disks are imported",
+			spec: migration.VirtualMachineImportSpec{
+				IncludeDisks: []migration.DiskSelector{{Index: ptr.To(int32(0))}, {FileName: "vm_1.vmdk"}},
+			},
+			expected: []bool{false, false, true},
+		},
+		{
+			desc: "Excluded disks are not imported",
+			spec: migration.VirtualMachineImportSpec{
+				ExcludeDisks: []migration.DiskSelector{{VolumeID: "8f2c4e9a"}},
+			},
+			expected: []bool{false, false, true},
+		},
+		{
+			desc: "Exclusion takes precedence over inclusion",
+			spec: migration.VirtualMachineImportSpec{
+				IncludeDisks: []migration.DiskSelector{{DeviceID: "VirtualLsiLogicController0:1"}},
+				ExcludeDisks: []migration.DiskSelector{{FileName: "vm_1.vmdk"}},
+			},
+			expected: []bool{true, true, true},
+		},
+		{
+			desc: "Disk mappings can skip disks",
+			spec: migration.VirtualMachineImportSpec{
+				DiskMapping: []migration.DiskMapping{{Index: ptr.To(int32(1)), Skip: true}},
+			},
+			expected: []bool{false, true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		vm := &migration.VirtualMachineImport{Spec: tc.spec}
+		for i, d := range disks {
+			assert.Equal(tc.expected[i], IsDiskSkipped(vm, d), "%s: disk %d", tc.desc, i)
+		}
+	}
+}
+
+func Test_CDROMBusType(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(kubevirtv1.DiskBusSCSI, CDROMBusType(kubevirtv1.DiskBusSCSI))
+	assert.Equal(kubevirtv1.DiskBusSATA, CDROMBusType(kubevirtv1.DiskBusSATA))
+	assert.Equal(kubevirtv1.DiskBusSATA, CDROMBusType(kubevirtv1.DiskBusVirtio))
+	assert.Equal(kubevirtv1.DiskBusSATA, CDROMBusType(kubevirtv1.DiskBusUSB))
+}
+
+func Test_DiskBootOrders(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc         string
+		disks        []migration.DiskInfo
+		maxBootOrder uint
+		expected     []uint
+	}{
+		{
+			desc:     "Positional order without boot order of the source",
+			disks:    []migration.DiskInfo{{BootDisk: true}, {}, {}},
+			expected: []uint{1, 2, 3},
+		},
+		{
+			desc:     "Boot disk first without boot order of the source",
+			disks:    []migration.DiskInfo{{}, {BootDisk: true}, {}},
+			expected: []uint{2, 1, 3},
+		},
+		{
+			desc:     "Boot order of the source is kept",
+			disks:    []migration.DiskInfo{{BootOrder: 2}, {BootOrder: 1, BootDisk: true}, {}},
+			expected: []uint{2, 1, 3},
+		},
+		{
+			desc:         "Disks without boot order follow the network interfaces",
+			disks:        []migration.DiskInfo{{}, {BootOrder: 2, BootDisk: true}},
+			maxBootOrder: 3,
+			expected:     []uint{4, 2},
+		},
+		{
+			desc:     "CD-ROM images without boot order are not booted from",
+			disks:    []migration.DiskInfo{{BootDisk: true}, {CDROM: true}, {}},
+			expected: []uint{1, 0, 2},
+		},
+		{
+			desc:     "CD-ROM images keep the boot order of the source",
+			disks:    []migration.DiskInfo{{BootOrder: 2, BootDisk: true}, {CDROM: true, BootOrder: 1}, {}},
+			expected: []uint{2, 1, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, DiskBootOrders(tc.disks, tc.maxBootOrder), tc.desc)
+	}
+}
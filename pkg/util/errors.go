@@ -7,4 +7,23 @@ import (
 var (
 	ErrClusterNotReady         = errors.New("source cluster not ready yet")
 	ErrGenerateSourceInterface = errors.New("failed to generate source interface")
+	ErrScheduleWindowClosed    = errors.New("import schedule window is closed")
+
+	ErrInvalidStorageClassMapping = errors.New("invalid storage class mapping")
+	ErrInvalidResourcePolicy      = errors.New("invalid resource policy")
+
+	// The following errors are used to map a failed import to the reason
+	// that is reported in the status of the `VirtualMachineImport`.
+	ErrInvalidSourceCluster       = errors.New("invalid source cluster")
+	ErrSourceNetworkNotFound      = errors.New("source network not found")
+	ErrDestinationNetworkNotFound = errors.New("destination network not found")
+	ErrInvalidNetworkMapping      = errors.New("invalid network mapping")
+	ErrStorageClassNotFound       = errors.New("storage class not found")
+	ErrVirtualMachineNotFound     = errors.New("source virtual machine not found")
+	ErrToolsNotRunning            = errors.New("VMware Tools not running")
+	ErrInvalidSchedule            = errors.New("invalid import schedule")
+	ErrNetworkCreationFailed      = errors.New("network creation failed")
+	ErrInvalidDiskMapping         = errors.New("invalid disk mapping")
+	ErrBootDiskExcluded           = errors.New("boot disk excluded")
+	ErrFeatureGateDisabled        = errors.New("KubeVirt feature gate disabled")
 )
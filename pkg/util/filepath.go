@@ -1,6 +1,9 @@
 package util
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -10,3 +13,32 @@ func BaseName(path string) string {
 	ext := filepath.Ext(filename)
 	return strings.TrimSuffix(filename, ext)
 }
+
+// MoveFile moves the file at src to dst. The file is copied if it cannot be
+// renamed, e.g. because src and dst are on different file systems.
+func MoveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", src, err)
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %q to %q: %w", src, dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close %q: %w", dst, err)
+	}
+
+	return os.Remove(src)
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -34,3 +36,20 @@ func Test_BaseName(t *testing.T) {
 		assert.Equal(result, tc.expected, tc.desc)
 	}
 }
+
+func Test_MoveFile(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "config.iso")
+	dst := filepath.Join(dir, "vm-config.iso")
+	assert.NoError(os.WriteFile(src, []byte("iso"), 0600))
+
+	assert.NoError(MoveFile(src, dst))
+	assert.NoFileExists(src)
+	data, err := os.ReadFile(dst)
+	assert.NoError(err)
+	assert.Equal("iso", string(data))
+
+	assert.Error(MoveFile(src, dst), "expected moving a missing file to fail")
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// invalidLabelChars matches the characters that are not allowed in label
+// values and the names of label and annotation keys.
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ValidateMetadataMapping checks that the source key of the given metadata
+// mapping is a valid pattern.
+func ValidateMetadataMapping(mm migration.MetadataMapping) error {
+	if _, err := path.Match(mm.SourceKey, ""); err != nil {
+		return fmt.Errorf("invalid metadata mapping: invalid glob pattern '%s': %v", mm.SourceKey, err)
+	}
+	return nil
+}
+
+// GetMetadataTarget returns how the metadata of the source VM with the
+// given key is added to the imported VM. The first matching metadata
+// mapping is used. Keys that are not matched are dropped, unless there are
+// no metadata mappings at all.
+func GetMetadataTarget(mappings []migration.MetadataMapping, key string) string {
+	if len(mappings) == 0 {
+		return migration.MetadataTargetAnnotation
+	}
+	for _, mm := range mappings {
+		if ok, err := path.Match(mm.SourceKey, key); err == nil && ok {
+			return mm.GetTarget()
+		}
+	}
+	return migration.MetadataTargetDrop
+}
+
+// SanitizeLabelValue returns the given string in the syntax of a label
+// value: Invalid characters are replaced by `-`, the result is truncated to
+// 63 characters and starts and ends with an alphanumeric character. The
+// result is empty if nothing remains.
+func SanitizeLabelValue(s string) string {
+	s = invalidLabelChars.ReplaceAllString(s, "-")
+	if len(s) > validation.LabelValueMaxLength {
+		s = s[:validation.LabelValueMaxLength]
+	}
+	return strings.TrimFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+}
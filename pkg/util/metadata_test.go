@@ -0,0 +1,37 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_GetMetadataTarget(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(migration.MetadataTargetAnnotation, GetMetadataTarget(nil, "tag/env"))
+
+	mappings := []migration.MetadataMapping{
+		{SourceKey: "tag/secret", Target: ptr.To(migration.MetadataTargetDrop)},
+		{SourceKey: "tag/*", Target: ptr.To(migration.MetadataTargetLabel)},
+		{SourceKey: "description"},
+	}
+	assert.Equal(migration.MetadataTargetDrop, GetMetadataTarget(mappings, "tag/secret"))
+	assert.Equal(migration.MetadataTargetLabel, GetMetadataTarget(mappings, "tag/env"))
+	assert.Equal(migration.MetadataTargetAnnotation, GetMetadataTarget(mappings, "description"))
+	assert.Equal(migration.MetadataTargetDrop, GetMetadataTarget(mappings, "metadata/owner"))
+}
+
+func Test_SanitizeLabelValue(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal("prod", SanitizeLabelValue("prod"))
+	assert.Equal("Web-Server_1.0", SanitizeLabelValue("Web Server_1.0"))
+	assert.Equal("team-a-b", SanitizeLabelValue("(team a/b)"))
+	assert.Empty(SanitizeLabelValue("äöü"))
+	assert.Len(SanitizeLabelValue(strings.Repeat("a", 100)), 63)
+}
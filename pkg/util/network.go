@@ -0,0 +1,136 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+
+	cniv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// The labels and values used by the Harvester network controller to
+// identify VLAN networks.
+const (
+	LabelClusterNetwork = "network.harvesterhci.io/clusternetwork"
+	LabelNetworkType    = "network.harvesterhci.io/type"
+	LabelVlanID         = "network.harvesterhci.io/vlan-id"
+	NetworkTypeL2Vlan   = "L2VlanNetwork"
+)
+
+// vlanNetworkConfig is the CNI configuration of a Harvester VLAN network.
+type vlanNetworkConfig struct {
+	CNIVersion  string   `json:"cniVersion"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Bridge      string   `json:"bridge"`
+	PromiscMode bool     `json:"promiscMode"`
+	Vlan        int32    `json:"vlan"`
+	IPAM        struct{} `json:"ipam"`
+}
+
+// VlanNetworkName returns the name of the VLAN network that is created for
+// the given cluster network and VLAN ID, e.g. `mgmt-vlan100`.
+func VlanNetworkName(clusterNetwork string, vlanID int32) string {
+	return fmt.Sprintf("%s-vlan%d", clusterNetwork, vlanID)
+}
+
+// NewVlanNetworkAttachmentDefinition returns a Harvester VLAN network for the
+// given cluster network and VLAN ID.
+func NewVlanNetworkAttachmentDefinition(namespace, clusterNetwork string, vlanID int32) (*cniv1.NetworkAttachmentDefinition, error) {
+	name := VlanNetworkName(clusterNetwork, vlanID)
+	config, err := json.Marshal(vlanNetworkConfig{
+		CNIVersion:  "0.3.1",
+		Name:        name,
+		Type:        "bridge",
+		Bridge:      fmt.Sprintf("%s-br", clusterNetwork),
+		PromiscMode: true,
+		Vlan:        vlanID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate network config: %w", err)
+	}
+
+	return &cniv1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				LabelClusterNetwork: clusterNetwork,
+				LabelNetworkType:    NetworkTypeL2Vlan,
+				LabelVlanID:         strconv.Itoa(int(vlanID)),
+			},
+		},
+		Spec: cniv1.NetworkAttachmentDefinitionSpec{
+			Config: string(config),
+		},
+	}, nil
+}
+
+// CheckVlanNetworkConfig checks that the given network is the Harvester VLAN
+// network for the given cluster network and VLAN ID, so that a network that
+// has been created by hand is not attached by mistake.
+func CheckVlanNetworkConfig(nad *cniv1.NetworkAttachmentDefinition, clusterNetwork string, vlanID int32) error {
+	var config vlanNetworkConfig
+	if err := json.Unmarshal([]byte(nad.Spec.Config), &config); err != nil {
+		return fmt.Errorf("failed to parse network config: %w", err)
+	}
+
+	bridge := fmt.Sprintf("%s-br", clusterNetwork)
+	if config.Type != "bridge" || config.Bridge != bridge || config.Vlan != vlanID {
+		return fmt.Errorf("network config with type '%s', bridge '%s' and VLAN ID %d does not match bridge '%s' and VLAN ID %d",
+			config.Type, config.Bridge, config.Vlan, bridge, vlanID)
+	}
+	return nil
+}
+
+// ValidateSourceNetworkPattern checks that the source network of the given
+// network mapping is a valid pattern for its match type.
+func ValidateSourceNetworkPattern(nm migration.NetworkMapping) error {
+	switch nm.GetMatchType() {
+	case migration.NetworkMatchTypeExact:
+	case migration.NetworkMatchTypeGlob:
+		if _, err := path.Match(nm.SourceNetwork, ""); err != nil {
+			return fmt.Errorf("%w: invalid glob pattern '%s': %v", ErrInvalidNetworkMapping, nm.SourceNetwork, err)
+		}
+	case migration.NetworkMatchTypeRegex:
+		if _, err := regexp.Compile(nm.SourceNetwork); err != nil {
+			return fmt.Errorf("%w: invalid regular expression '%s': %v", ErrInvalidNetworkMapping, nm.SourceNetwork, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported match type '%s'", ErrInvalidNetworkMapping, nm.GetMatchType())
+	}
+	return nil
+}
+
+// SourceNetworkMatcher reports whether a network name of the source VM is
+// matched by a network mapping.
+type SourceNetworkMatcher func(name string) bool
+
+// NewSourceNetworkMatcher compiles the source network pattern of the given
+// network mapping once, so that it can be matched against all network
+// interfaces of a VM. Invalid patterns never match.
+func NewSourceNetworkMatcher(nm migration.NetworkMapping) SourceNetworkMatcher {
+	switch nm.GetMatchType() {
+	case migration.NetworkMatchTypeGlob:
+		if _, err := path.Match(nm.SourceNetwork, ""); err != nil {
+			return func(string) bool { return false }
+		}
+		return func(name string) bool {
+			ok, _ := path.Match(nm.SourceNetwork, name)
+			return ok
+		}
+	case migration.NetworkMatchTypeRegex:
+		re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", nm.SourceNetwork))
+		if err != nil {
+			return func(string) bool { return false }
+		}
+		return re.MatchString
+	default:
+		return func(name string) bool { return nm.SourceNetwork == name }
+	}
+}
@@ -0,0 +1,128 @@
+package util
+
+import (
+	"testing"
+
+	cniv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_NewVlanNetworkAttachmentDefinition(t *testing.T) {
+	assert := require.New(t)
+
+	nad, err := NewVlanNetworkAttachmentDefinition("default", "mgmt", 100)
+	assert.NoError(err)
+	assert.Equal("mgmt-vlan100", nad.Name)
+	assert.Equal("default", nad.Namespace)
+	assert.Equal(map[string]string{
+		LabelClusterNetwork: "mgmt",
+		LabelNetworkType:    NetworkTypeL2Vlan,
+		LabelVlanID:         "100",
+	}, nad.Labels)
+	assert.JSONEq(`{"cniVersion":"0.3.1","name":"mgmt-vlan100","type":"bridge","bridge":"mgmt-br","promiscMode":true,"vlan":100,"ipam":{}}`, nad.Spec.Config)
+}
+
+func Test_CheckVlanNetworkConfig(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc        string
+		config      string
+		expectedErr bool
+	}{
+		{
+			desc:   "Generated network",
+			config: `{"cniVersion":"0.3.1","name":"mgmt-vlan100","type":"bridge","bridge":"mgmt-br","promiscMode":true,"vlan":100,"ipam":{}}`,
+		},
+		{
+			desc:        "Different VLAN ID",
+			config:      `{"cniVersion":"0.3.1","name":"mgmt-vlan100","type":"bridge","bridge":"mgmt-br","vlan":200}`,
+			expectedErr: true,
+		},
+		{
+			desc:        "Different bridge",
+			config:      `{"cniVersion":"0.3.1","name":"mgmt-vlan100","type":"bridge","bridge":"other-br","vlan":100}`,
+			expectedErr: true,
+		},
+		{
+			desc:        "Different type",
+			config:      `{"cniVersion":"0.3.1","name":"mgmt-vlan100","type":"macvlan","master":"eth0"}`,
+			expectedErr: true,
+		},
+		{
+			desc:        "Invalid config",
+			config:      `not json`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		nad := &cniv1.NetworkAttachmentDefinition{Spec: cniv1.NetworkAttachmentDefinitionSpec{Config: tc.config}}
+		err := CheckVlanNetworkConfig(nad, "mgmt", 100)
+		if tc.expectedErr {
+			assert.Error(err, tc.desc)
+		} else {
+			assert.NoError(err, tc.desc)
+		}
+	}
+}
+
+func Test_NewSourceNetworkMatcher(t *testing.T) {
+	assert := require.New(t)
+	testCases := []struct {
+		desc     string
+		nm       migration.NetworkMapping
+		name     string
+		expected bool
+	}{
+		{
+			desc:     "Exact match",
+			nm:       migration.NetworkMapping{SourceNetwork: "VM Network"},
+			name:     "VM Network",
+			expected: true,
+		},
+		{
+			desc: "Exact mismatch",
+			nm:   migration.NetworkMapping{SourceNetwork: "VM Network"},
+			name: "VM Network 2",
+		},
+		{
+			desc:     "Glob match",
+			nm:       migration.NetworkMapping{SourceNetwork: "vlan-*", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+			name:     "vlan-100",
+			expected: true,
+		},
+		{
+			desc: "Glob mismatch",
+			nm:   migration.NetworkMapping{SourceNetwork: "vlan-*", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+			name: "dvs-100",
+		},
+		{
+			desc:     "Regex match",
+			nm:       migration.NetworkMapping{SourceNetwork: "dvs-(10|20)", MatchType: ptr.To(migration.NetworkMatchTypeRegex)},
+			name:     "dvs-20",
+			expected: true,
+		},
+		{
+			desc: "Regex must match the whole name",
+			nm:   migration.NetworkMapping{SourceNetwork: "dvs-(10|20)", MatchType: ptr.To(migration.NetworkMatchTypeRegex)},
+			name: "dvs-200",
+		},
+		{
+			desc: "Invalid regex never matches",
+			nm:   migration.NetworkMapping{SourceNetwork: "dvs-(10", MatchType: ptr.To(migration.NetworkMatchTypeRegex)},
+			name: "dvs-(10",
+		},
+		{
+			desc: "Invalid glob never matches",
+			nm:   migration.NetworkMapping{SourceNetwork: "vlan-[", MatchType: ptr.To(migration.NetworkMatchTypeGlob)},
+			name: "vlan-[",
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, NewSourceNetworkMatcher(tc.nm)(tc.name), tc.desc)
+	}
+}
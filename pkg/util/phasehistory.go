@@ -0,0 +1,117 @@
+package util
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// maxPhaseHistory is the maximum number of entries in the phase history of
+// a `VirtualMachineImport`. Older entries are dropped, so that an import
+// that keeps switching phases does not grow its status without bound.
+const maxPhaseHistory = 20
+
+// RecordPhaseTransition updates the phase history of the given
+// `VirtualMachineImport` if its status or the step within that status
+// differs from the last recorded entry.
+// Only the last `maxPhaseHistory` entries are kept.
+// It returns true if the status has been modified.
+func RecordPhaseTransition(vm *migration.VirtualMachineImport, now time.Time) bool {
+	status := &vm.Status
+	nowStr := now.UTC().Format(time.RFC3339)
+	step := importStep(status)
+
+	if len(status.PhaseHistory) > 0 {
+		last := &status.PhaseHistory[len(status.PhaseHistory)-1]
+		if last.Phase == status.Status && last.Step == step {
+			return false
+		}
+		last.ExitedTime = nowStr
+	} else if status.Status == "" {
+		// Nothing to record until the preflight checks are finished.
+		return false
+	} else if status.Status == migration.VirtualMachineImportValid || status.Status == migration.VirtualMachineImportInvalid {
+		// The preflight checks start as soon as the object is created.
+		status.PhaseHistory = append(status.PhaseHistory, migration.PhaseTiming{
+			Step:        migration.ImportStepPreflight,
+			EnteredTime: vm.CreationTimestamp.UTC().Format(time.RFC3339),
+			ExitedTime:  nowStr,
+		})
+	}
+
+	status.PhaseHistory = append(status.PhaseHistory, migration.PhaseTiming{
+		Phase:       status.Status,
+		Step:        step,
+		EnteredTime: nowStr,
+	})
+
+	// The elapsed time is measured from the creation of the import, as
+	// the first recorded phase might have been dropped from the history.
+	started := vm.CreationTimestamp.Time
+	if started.IsZero() {
+		if entered, err := time.Parse(time.RFC3339, status.PhaseHistory[0].EnteredTime); err == nil {
+			started = entered
+		}
+	}
+	if !started.IsZero() {
+		status.ElapsedTime = &metav1.Duration{Duration: now.Sub(started).Round(time.Second)}
+	}
+
+	if n := len(status.PhaseHistory); n > maxPhaseHistory {
+		status.PhaseHistory = slices.Clone(status.PhaseHistory[n-maxPhaseHistory:])
+	}
+
+	if status.Status == migration.VirtualMachineRunning {
+		if shutdownTime, ok := sourceShutdownTime(status.ImportConditions); ok {
+			status.SourceDowntime = &metav1.Duration{Duration: now.Sub(shutdownTime).Round(time.Second)}
+		}
+	}
+
+	return true
+}
+
+// importStep returns the step of the import within its current status. The
+// `sourceReady` status covers the shutdown of the source VM and the export
+// of its disks, which are told apart by the import conditions.
+func importStep(status *migration.VirtualMachineImportStatus) string {
+	if status.Status != migration.SourceReady {
+		return ""
+	}
+
+	conditions := status.ImportConditions
+	switch {
+	case ConditionExists(conditions, migration.VirtualMachinePoweredOff, corev1.ConditionTrue):
+		return migration.ImportStepExport
+	case ConditionExists(conditions, migration.VirtualMachineShutdownGuest, corev1.ConditionTrue),
+		ConditionExists(conditions, migration.VirtualMachinePoweringOff, corev1.ConditionTrue):
+		return migration.ImportStepShutdownWait
+	default:
+		return ""
+	}
+}
+
+// sourceShutdownTime returns the time when the shutdown of the source VM
+// has been triggered.
+func sourceShutdownTime(conditions []common.Condition) (time.Time, bool) {
+	var result time.Time
+	for _, c := range []condition.Cond{migration.VirtualMachineShutdownGuest, migration.VirtualMachinePoweringOff} {
+		cond := GetCondition(conditions, c, corev1.ConditionTrue)
+		if cond == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, cond.LastTransitionTime)
+		if err != nil {
+			continue
+		}
+		if result.IsZero() || t.Before(result) {
+			result = t
+		}
+	}
+	return result, !result.IsZero()
+}
@@ -0,0 +1,124 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_RecordPhaseTransition(t *testing.T) {
+	assert := require.New(t)
+	created := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	vm := &migration.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+	}
+
+	assert.False(RecordPhaseTransition(vm, created), "expected no transition for new import")
+
+	vm.Status.Status = migration.VirtualMachineImportValid
+	assert.True(RecordPhaseTransition(vm, created.Add(time.Minute)))
+	assert.Len(vm.Status.PhaseHistory, 2)
+	assert.Equal(migration.ImportStatus(""), vm.Status.PhaseHistory[0].Phase)
+	assert.Equal(migration.ImportStepPreflight, vm.Status.PhaseHistory[0].Step)
+	assert.Equal("2025-03-15T22:00:00Z", vm.Status.PhaseHistory[0].EnteredTime)
+	assert.Equal("2025-03-15T22:01:00Z", vm.Status.PhaseHistory[0].ExitedTime)
+	assert.Empty(vm.Status.PhaseHistory[1].ExitedTime)
+
+	assert.False(RecordPhaseTransition(vm, created.Add(2*time.Minute)), "expected no transition for unchanged status")
+
+	vm.Status.Status = migration.SourceReady
+	assert.True(RecordPhaseTransition(vm, created.Add(3*time.Minute)))
+	vm.Status.ImportConditions = []common.Condition{
+		{
+			Type:               migration.VirtualMachineShutdownGuest,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: created.Add(4 * time.Minute).Format(time.RFC3339),
+		},
+	}
+
+	vm.Status.Status = migration.VirtualMachineRunning
+	assert.True(RecordPhaseTransition(vm, created.Add(time.Hour)))
+	assert.Len(vm.Status.PhaseHistory, 4)
+	assert.Equal("2025-03-15T23:00:00Z", vm.Status.PhaseHistory[2].ExitedTime)
+	assert.Equal(time.Hour, vm.Status.ElapsedTime.Duration)
+	assert.Equal(56*time.Minute, vm.Status.SourceDowntime.Duration)
+}
+
+func Test_RecordPhaseTransition_steps(t *testing.T) {
+	assert := require.New(t)
+	created := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	vm := &migration.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+		Status: migration.VirtualMachineImportStatus{
+			Status: migration.SourceReady,
+			PhaseHistory: []migration.PhaseTiming{
+				{Step: migration.ImportStepPreflight, EnteredTime: "2025-03-15T22:00:00Z", ExitedTime: "2025-03-15T22:01:00Z"},
+				{Phase: migration.VirtualMachineImportValid, EnteredTime: "2025-03-15T22:01:00Z", ExitedTime: "2025-03-15T22:02:00Z"},
+				{Phase: migration.SourceReady, EnteredTime: "2025-03-15T22:02:00Z"},
+			},
+		},
+	}
+
+	// The shutdown of the source VM has been triggered.
+	vm.Status.ImportConditions = []common.Condition{
+		{Type: migration.VirtualMachineShutdownGuest, Status: corev1.ConditionTrue},
+	}
+	assert.True(RecordPhaseTransition(vm, created.Add(3*time.Minute)))
+	assert.False(RecordPhaseTransition(vm, created.Add(4*time.Minute)), "expected no transition while waiting for the shutdown")
+
+	// The source VM is powered off and its disks are exported.
+	vm.Status.ImportConditions = append(vm.Status.ImportConditions, common.Condition{
+		Type: migration.VirtualMachinePoweredOff, Status: corev1.ConditionTrue,
+	})
+	assert.True(RecordPhaseTransition(vm, created.Add(5*time.Minute)))
+
+	vm.Status.Status = migration.DisksExported
+	assert.True(RecordPhaseTransition(vm, created.Add(30*time.Minute)))
+
+	assert.Equal([]migration.PhaseTiming{
+		{Step: migration.ImportStepPreflight, EnteredTime: "2025-03-15T22:00:00Z", ExitedTime: "2025-03-15T22:01:00Z"},
+		{Phase: migration.VirtualMachineImportValid, EnteredTime: "2025-03-15T22:01:00Z", ExitedTime: "2025-03-15T22:02:00Z"},
+		{Phase: migration.SourceReady, EnteredTime: "2025-03-15T22:02:00Z", ExitedTime: "2025-03-15T22:03:00Z"},
+		{Phase: migration.SourceReady, Step: migration.ImportStepShutdownWait, EnteredTime: "2025-03-15T22:03:00Z", ExitedTime: "2025-03-15T22:05:00Z"},
+		{Phase: migration.SourceReady, Step: migration.ImportStepExport, EnteredTime: "2025-03-15T22:05:00Z", ExitedTime: "2025-03-15T22:30:00Z"},
+		{Phase: migration.DisksExported, EnteredTime: "2025-03-15T22:30:00Z"},
+	}, vm.Status.PhaseHistory)
+}
+
+func Test_RecordPhaseTransition_maxPhaseHistory(t *testing.T) {
+	assert := require.New(t)
+	created := time.Date(2025, 3, 15, 22, 0, 0, 0, time.UTC)
+	vm := &migration.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+	}
+
+	// An import that keeps switching between two phases.
+	phases := []migration.ImportStatus{migration.DiskImagesSubmitted, migration.DiskImagesFailed}
+	vm.Status.Status = migration.VirtualMachineImportValid
+	assert.True(RecordPhaseTransition(vm, created.Add(time.Minute)))
+	for i := range 2 * maxPhaseHistory {
+		vm.Status.Status = phases[i%2]
+		assert.True(RecordPhaseTransition(vm, created.Add(time.Duration(i+2)*time.Minute)))
+		assert.LessOrEqual(len(vm.Status.PhaseHistory), maxPhaseHistory)
+	}
+
+	assert.Len(vm.Status.PhaseHistory, maxPhaseHistory)
+	last := vm.Status.PhaseHistory[maxPhaseHistory-1]
+	assert.Equal(migration.DiskImagesFailed, last.Phase)
+	assert.Equal(created.Add(time.Duration(2*maxPhaseHistory+1)*time.Minute).Format(time.RFC3339), last.EnteredTime)
+	assert.Empty(last.ExitedTime)
+	assert.Equal(last.EnteredTime, vm.Status.PhaseHistory[maxPhaseHistory-2].ExitedTime)
+	assert.Equal(time.Duration(2*maxPhaseHistory+1)*time.Minute, vm.Status.ElapsedTime.Duration, "expected the elapsed time to be measured from the creation")
+}
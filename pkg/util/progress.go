@@ -0,0 +1,39 @@
+package util
+
+import (
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// importProgress maps the statuses of an import to its estimated progress in
+// percent. The export and the download of the disk images take most of the
+// time. Failed imports keep the progress they had reached.
+var importProgress = map[migration.ImportStatus]int32{
+	"":                                      0,
+	migration.VirtualMachineImportValid:     5,
+	migration.VirtualMachineImportScheduled: 5,
+	migration.SourceReady:                   10,
+	migration.DisksExported:                 50,
+	migration.DiskImagesSubmitted:           60,
+	migration.DiskImagesReady:               90,
+	migration.VirtualMachineCreated:         95,
+	migration.VirtualMachineRunning:         100,
+}
+
+// UpdateImportProgress updates the disk count and the progress of the given
+// `VirtualMachineImport`. It returns true if the status has been modified.
+func UpdateImportProgress(vm *migration.VirtualMachineImport) bool {
+	status := &vm.Status
+	changed := false
+
+	if diskCount := int32(len(status.DiskImportStatus)); status.DiskCount != diskCount { // nolint:gosec
+		status.DiskCount = diskCount
+		changed = true
+	}
+
+	if progress, ok := importProgress[status.Status]; ok && status.Progress != progress {
+		status.Progress = progress
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_UpdateImportProgress(t *testing.T) {
+	assert := require.New(t)
+	vm := &migration.VirtualMachineImport{}
+
+	assert.False(UpdateImportProgress(vm), "expected no change for new import")
+
+	vm.Status.Status = migration.DisksExported
+	vm.Status.DiskImportStatus = []migration.DiskInfo{{Name: "disk-1"}, {Name: "disk-2"}}
+	assert.True(UpdateImportProgress(vm))
+	assert.Equal(int32(2), vm.Status.DiskCount)
+	assert.Equal(int32(50), vm.Status.Progress)
+
+	assert.False(UpdateImportProgress(vm), "expected no change for unchanged status")
+
+	vm.Status.Status = migration.VirtualMachineMigrationFailed
+	assert.False(UpdateImportProgress(vm), "expected failed import to keep its progress")
+	assert.Equal(int32(50), vm.Status.Progress)
+
+	vm.Status.Status = migration.VirtualMachineRunning
+	assert.True(UpdateImportProgress(vm))
+	assert.Equal(int32(100), vm.Status.Progress)
+}
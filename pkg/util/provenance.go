@@ -0,0 +1,123 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// The annotations that record the provenance of the VMs, images and PVCs
+// created by an import.
+const (
+	AnnotationSourceKind         = "migration.harvesterhci.io/source-kind"
+	AnnotationSource             = "migration.harvesterhci.io/source"
+	AnnotationImport             = "migration.harvesterhci.io/import"
+	AnnotationImportTime         = "migration.harvesterhci.io/import-time"
+	AnnotationSourceVMName       = "migration.harvesterhci.io/source-vm-name"
+	AnnotationSourceVMID         = "migration.harvesterhci.io/source-vm-id"
+	AnnotationSourceVMUUID       = "migration.harvesterhci.io/source-vm-uuid"
+	AnnotationSourceVMReference  = "migration.harvesterhci.io/source-vm-moref"
+	AnnotationSourceMACAddresses = "migration.harvesterhci.io/source-mac-addresses"
+	AnnotationSourceDisks        = "migration.harvesterhci.io/source-disks"
+	AnnotationSourceDisk         = "migration.harvesterhci.io/source-disk"
+)
+
+// DiskIdentity contains the identifiers of a disk of the source VM. It is
+// stored as JSON in the provenance annotations.
+type DiskIdentity struct {
+	Index    int32  `json:"index"`
+	DeviceID string `json:"deviceID,omitempty"`
+	FileName string `json:"fileName,omitempty"`
+	VolumeID string `json:"volumeID,omitempty"`
+}
+
+// NewDiskIdentity returns the identifiers of the given disk.
+func NewDiskIdentity(d migration.DiskInfo) DiskIdentity {
+	return DiskIdentity{
+		Index:    d.Index,
+		DeviceID: d.DeviceID,
+		FileName: d.FileName,
+		VolumeID: d.VolumeID,
+	}
+}
+
+// ProvenanceAnnotations returns the annotations that record the source of
+// the objects created by the given import. All objects of an import carry
+// the import time that is recorded in its status. Annotations without a
+// value are omitted.
+func ProvenanceAnnotations(vm *migration.VirtualMachineImport) map[string]string {
+	result := map[string]string{
+		AnnotationSourceKind: vm.Spec.SourceCluster.Kind,
+		AnnotationSource:     fmt.Sprintf("%s/%s", vm.Spec.SourceCluster.Namespace, vm.Spec.SourceCluster.Name),
+		AnnotationImport:     fmt.Sprintf("%s/%s", vm.Namespace, vm.Name),
+		AnnotationImportTime: vm.Status.ImportTime,
+		// The name of the VM as given in the import. This may also be a
+		// server ID in case of OpenStack.
+		AnnotationSourceVMName: vm.Spec.VirtualMachineName,
+	}
+
+	if id := vm.Status.SourceVirtualMachine; id != nil {
+		result[AnnotationSourceVMID] = id.ID
+		result[AnnotationSourceVMUUID] = id.UUID
+		result[AnnotationSourceVMReference] = id.Reference
+	}
+
+	for k, v := range result {
+		if v == "" {
+			delete(result, k)
+		}
+	}
+
+	return result
+}
+
+// VirtualMachineProvenanceAnnotations returns the provenance annotations of
+// the VM created by the given import. In addition to the annotations
+// returned by `ProvenanceAnnotations`, they contain the MAC addresses of the
+// network interfaces and the identifiers of the imported disks of the
+// source VM.
+func VirtualMachineProvenanceAnnotations(vm *migration.VirtualMachineImport) (map[string]string, error) {
+	result := ProvenanceAnnotations(vm)
+
+	macs := make([]string, 0, len(vm.Status.NetworkInterfaces))
+	for _, ni := range vm.Status.NetworkInterfaces {
+		if ni.MACAddress != "" {
+			macs = append(macs, ni.MACAddress)
+		}
+	}
+	if len(macs) > 0 {
+		result[AnnotationSourceMACAddresses] = strings.Join(macs, ",")
+	}
+
+	if len(vm.Status.DiskImportStatus) > 0 {
+		disks := make([]DiskIdentity, 0, len(vm.Status.DiskImportStatus))
+		for _, d := range vm.Status.DiskImportStatus {
+			disks = append(disks, NewDiskIdentity(d))
+		}
+		data, err := json.Marshal(disks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the disk identifiers: %w", err)
+		}
+		result[AnnotationSourceDisks] = string(data)
+	}
+
+	return result, nil
+}
+
+// DiskProvenanceAnnotations returns the provenance annotations of the image
+// and the PVC created for the given disk of the import. In addition to the
+// annotations returned by `ProvenanceAnnotations`, they contain the
+// identifiers of the disk in the source VM.
+func DiskProvenanceAnnotations(vm *migration.VirtualMachineImport, d migration.DiskInfo) (map[string]string, error) {
+	result := ProvenanceAnnotations(vm)
+
+	data, err := json.Marshal(NewDiskIdentity(d))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the disk identifiers: %w", err)
+	}
+	result[AnnotationSourceDisk] = string(data)
+
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_ProvenanceAnnotations(t *testing.T) {
+	assert := require.New(t)
+
+	vm := &migration.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: migration.VirtualMachineImportSpec{
+			SourceCluster:      corev1.ObjectReference{Kind: "VmwareSource", Name: "vcsim", Namespace: "default"},
+			VirtualMachineName: "Web Server",
+		},
+		Status: migration.VirtualMachineImportStatus{
+			NetworkInterfaces: []migration.NetworkInterfaceMapping{
+				{MACAddress: "00:50:56:00:00:01", SourceNetwork: "VM Network"},
+				{SourceNetwork: "backup"},
+				{MACAddress: "00:50:56:00:00:03", SourceNetwork: "storage"},
+			},
+			DiskImportStatus: []migration.DiskInfo{
+				{Index: 0, DeviceID: "SCSI0:0", FileName: "[datastore1] web/web.vmdk"},
+				{Index: 2, DeviceID: "SCSI0:2", FileName: "[datastore1] web/web_1.vmdk"},
+			},
+		},
+	}
+
+	common := map[string]string{
+		AnnotationSourceKind:   "VmwareSource",
+		AnnotationSource:       "default/vcsim",
+		AnnotationImport:       "default/web",
+		AnnotationSourceVMName: "Web Server",
+	}
+	assert.Equal(common, ProvenanceAnnotations(vm), "expected no identifiers and import time before the preflight checks")
+
+	vm.Status.ImportTime = "2025-03-15T22:00:00Z"
+
+	vm.Status.SourceVirtualMachine = &migration.SourceVirtualMachineIdentity{
+		ID:        "5012c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+		Reference: "vm-42",
+	}
+	annotations := ProvenanceAnnotations(vm)
+	assert.Equal("2025-03-15T22:00:00Z", annotations[AnnotationImportTime])
+	assert.Equal("5012c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f", annotations[AnnotationSourceVMID])
+	assert.Equal("vm-42", annotations[AnnotationSourceVMReference])
+	assert.NotContains(annotations, AnnotationSourceVMUUID, "expected empty identifiers to be omitted")
+
+	annotations, err := VirtualMachineProvenanceAnnotations(vm)
+	assert.NoError(err)
+	assert.Equal("00:50:56:00:00:01,00:50:56:00:00:03", annotations[AnnotationSourceMACAddresses])
+	assert.JSONEq(`[{"index":0,"deviceID":"SCSI0:0","fileName":"[datastore1] web/web.vmdk"},{"index":2,"deviceID":"SCSI0:2","fileName":"[datastore1] web/web_1.vmdk"}]`,
+		annotations[AnnotationSourceDisks])
+	assert.NotContains(annotations, AnnotationSourceDisk)
+
+	annotations, err = DiskProvenanceAnnotations(vm, migration.DiskInfo{Index: 1, DeviceID: "/dev/vdb", VolumeID: "0b1f3c5e-0000-4000-8000-000000000001"})
+	assert.NoError(err)
+	assert.Equal("default/web", annotations[AnnotationImport])
+	assert.Equal("2025-03-15T22:00:00Z", annotations[AnnotationImportTime], "expected the same import time for all objects")
+	assert.JSONEq(`{"index":1,"deviceID":"/dev/vdb","volumeID":"0b1f3c5e-0000-4000-8000-000000000001"}`, annotations[AnnotationSourceDisk])
+	assert.NotContains(annotations, AnnotationSourceMACAddresses)
+}
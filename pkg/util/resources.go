@@ -0,0 +1,152 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/harvester/harvester/pkg/settings"
+	harvesterutil "github.com/harvester/harvester/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// ParseOvercommit parses the value of the `overcommit-config` setting of
+// Harvester. An empty value means that the setting is not configured.
+func ParseOvercommit(value string) (*settings.Overcommit, error) {
+	if value == "" {
+		return nil, nil
+	}
+	overcommit := &settings.Overcommit{}
+	if err := json.Unmarshal([]byte(value), overcommit); err != nil {
+		return nil, fmt.Errorf("failed to parse setting %s: %w", settings.OvercommitConfigSettingName, err)
+	}
+	return overcommit, nil
+}
+
+// ValidateResourcePolicy checks that the reserved memory of the given
+// resource policy is a valid quantity.
+func ValidateResourcePolicy(rp *migration.ResourcePolicy) error {
+	if rp == nil || rp.ReservedMemory == "" {
+		return nil
+	}
+	q, err := resource.ParseQuantity(rp.ReservedMemory)
+	if err != nil {
+		return fmt.Errorf("%w: invalid reserved memory '%s': %v", ErrInvalidResourcePolicy, rp.ReservedMemory, err)
+	}
+	if q.Sign() < 0 {
+		return fmt.Errorf("%w: reserved memory '%s' must not be negative", ErrInvalidResourcePolicy, rp.ReservedMemory)
+	}
+	return nil
+}
+
+// MergeResourcePolicies returns the resource policy of an import. Fields of
+// the resource policy of the import take precedence over the fields of the
+// resource policy of the source. Both policies may be nil.
+func MergeResourcePolicies(vmPolicy, sourcePolicy *migration.ResourcePolicy) migration.ResourcePolicy {
+	var result migration.ResourcePolicy
+	for _, rp := range []*migration.ResourcePolicy{sourcePolicy, vmPolicy} {
+		if rp == nil {
+			continue
+		}
+		if rp.CPURequestPercentage != nil {
+			result.CPURequestPercentage = rp.CPURequestPercentage
+		}
+		if rp.MemoryRequestPercentage != nil {
+			result.MemoryRequestPercentage = rp.MemoryRequestPercentage
+		}
+		if rp.ReservedMemory != "" {
+			result.ReservedMemory = rp.ReservedMemory
+		}
+		if rp.HugepageSize != nil {
+			result.HugepageSize = rp.HugepageSize
+		}
+	}
+	return result
+}
+
+// resourceRequest returns the request of a resource for the given limit.
+// The percentage of the resource policy takes precedence over the
+// overcommit ratio of Harvester, which is applied the same way Harvester
+// applies it to new VMs. 0 means that no request is set.
+func resourceRequest(limit int64, percentage *int32, ratio int) int64 {
+	if percentage != nil {
+		return limit * int64(min(max(*percentage, 1), 100)) / 100
+	}
+	if ratio <= 0 {
+		return 0
+	}
+	return min(limit*100/int64(ratio), limit)
+}
+
+// ApplyResourcePolicy sets the resource requests, the reserved memory and
+// the hugepages of the given VM according to the resource policy. Requests
+// that have already been set, e.g. from the reservations of the source VM,
+// are kept. Like Harvester, neither the CPU nor the memory request is
+// overcommitted for dedicated CPUs and the memory request is rounded down
+// to MiB. Memory that is backed by hugepages is not overcommitted. The
+// overcommit may be nil if the setting of Harvester is not configured.
+func ApplyResourcePolicy(vm *kubevirtv1.VirtualMachine, rp migration.ResourcePolicy, overcommit *settings.Overcommit) error {
+	if err := ValidateResourcePolicy(&rp); err != nil {
+		return err
+	}
+	if overcommit == nil {
+		overcommit = &settings.Overcommit{}
+	}
+
+	domain := &vm.Spec.Template.Spec.Domain
+	if domain.Resources.Limits == nil {
+		domain.Resources.Limits = corev1.ResourceList{}
+	}
+	limits := domain.Resources.Limits
+
+	if rp.ReservedMemory != "" {
+		reserved := resource.MustParse(rp.ReservedMemory)
+		if mem, ok := limits[corev1.ResourceMemory]; ok {
+			mem.Add(reserved)
+			limits[corev1.ResourceMemory] = mem
+		}
+		metav1.SetMetaDataAnnotation(&vm.ObjectMeta, harvesterutil.AnnotationReservedMemory, reserved.String())
+	}
+
+	if rp.HugepageSize != nil {
+		if domain.Memory == nil {
+			domain.Memory = &kubevirtv1.Memory{}
+		}
+		domain.Memory.Hugepages = &kubevirtv1.Hugepages{PageSize: *rp.HugepageSize}
+	}
+
+	requests := domain.Resources.Requests
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+
+	// Dedicated CPUs require the CPU request to match the limit, which
+	// KubeVirt takes care of.
+	dedicatedCPUs := domain.CPU != nil && domain.CPU.DedicatedCPUPlacement
+	if cpu, ok := limits[corev1.ResourceCPU]; ok && requests.Cpu().IsZero() && !dedicatedCPUs {
+		if request := resourceRequest(cpu.MilliValue(), rp.CPURequestPercentage, overcommit.CPU); request > 0 {
+			requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(request, cpu.Format)
+		}
+	}
+
+	// KubeVirt also requires the memory request to match the limit for
+	// dedicated CPUs, including any reserved memory that has been added.
+	hugepages := domain.Memory != nil && domain.Memory.Hugepages != nil
+	if mem, ok := limits[corev1.ResourceMemory]; ok && dedicatedCPUs {
+		requests[corev1.ResourceMemory] = mem.DeepCopy()
+	} else if ok && requests.Memory().IsZero() && !hugepages {
+		if request := resourceRequest(mem.Value(), rp.MemoryRequestPercentage, overcommit.Memory) / (1 << 20) * (1 << 20); request > 0 {
+			requests[corev1.ResourceMemory] = *resource.NewQuantity(request, resource.BinarySI)
+		}
+	}
+
+	if len(requests) > 0 {
+		domain.Resources.Requests = requests
+	}
+
+	return nil
+}
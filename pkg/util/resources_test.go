@@ -0,0 +1,137 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/harvester/harvester/pkg/settings"
+	harvesterutil "github.com/harvester/harvester/pkg/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_ParseOvercommit(t *testing.T) {
+	assert := require.New(t)
+
+	overcommit, err := ParseOvercommit(`{"cpu":1000,"memory":150,"storage":100}`)
+	assert.NoError(err)
+	assert.Equal(&settings.Overcommit{CPU: 1000, Memory: 150, Storage: 100}, overcommit)
+
+	overcommit, err = ParseOvercommit("")
+	assert.NoError(err)
+	assert.Nil(overcommit)
+
+	_, err = ParseOvercommit("{")
+	assert.Error(err)
+}
+
+func Test_MergeResourcePolicies(t *testing.T) {
+	assert := require.New(t)
+
+	vmPolicy := &migration.ResourcePolicy{CPURequestPercentage: ptr.To(int32(50))}
+	sourcePolicy := &migration.ResourcePolicy{
+		CPURequestPercentage: ptr.To(int32(25)),
+		ReservedMemory:       "256Mi",
+	}
+
+	assert.Equal(migration.ResourcePolicy{
+		CPURequestPercentage: ptr.To(int32(50)),
+		ReservedMemory:       "256Mi",
+	}, MergeResourcePolicies(vmPolicy, sourcePolicy))
+	assert.Equal(*sourcePolicy, MergeResourcePolicies(nil, sourcePolicy))
+	assert.Equal(migration.ResourcePolicy{}, MergeResourcePolicies(nil, nil))
+}
+
+func Test_ApplyResourcePolicy(t *testing.T) {
+	assert := require.New(t)
+	newVM := func() *kubevirtv1.VirtualMachine {
+		return &kubevirtv1.VirtualMachine{
+			Spec: kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+					Spec: kubevirtv1.VirtualMachineInstanceSpec{
+						Domain: kubevirtv1.DomainSpec{
+							CPU: &kubevirtv1.CPU{Cores: 1, Sockets: 4, Threads: 1},
+							Memory: &kubevirtv1.Memory{
+								Guest: ptr.To(resource.MustParse("4096M")),
+							},
+							Resources: kubevirtv1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("4Gi"),
+									corev1.ResourceCPU:    resource.MustParse("4"),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	overcommit := &settings.Overcommit{CPU: 1000, Memory: 150, Storage: 100}
+
+	// The overcommit setting of Harvester is honored by default.
+	vm := newVM()
+	assert.NoError(ApplyResourcePolicy(vm, migration.ResourcePolicy{}, overcommit))
+	requests := vm.Spec.Template.Spec.Domain.Resources.Requests
+	assert.Equal("400m", requests.Cpu().String())
+	assert.Equal("2730Mi", requests.Memory().String())
+	assert.Empty(vm.Annotations)
+
+	// Without overcommit setting and percentages no requests are set.
+	vm = newVM()
+	assert.NoError(ApplyResourcePolicy(vm, migration.ResourcePolicy{}, nil))
+	assert.Empty(vm.Spec.Template.Spec.Domain.Resources.Requests)
+
+	// The percentages of the policy take precedence and reserved memory is
+	// added to the limit.
+	vm = newVM()
+	assert.NoError(ApplyResourcePolicy(vm, migration.ResourcePolicy{
+		CPURequestPercentage:    ptr.To(int32(50)),
+		MemoryRequestPercentage: ptr.To(int32(100)),
+		ReservedMemory:          "256Mi",
+	}, overcommit))
+	domain := vm.Spec.Template.Spec.Domain
+	assert.Equal("2", domain.Resources.Requests.Cpu().String())
+	assert.Equal("4352Mi", domain.Resources.Limits.Memory().String())
+	assert.Equal("4352Mi", domain.Resources.Requests.Memory().String())
+	assert.Equal("4096M", domain.Memory.Guest.String())
+	assert.Equal("256Mi", vm.Annotations[harvesterutil.AnnotationReservedMemory])
+
+	// Hugepages and existing requests are not overcommitted.
+	vm = newVM()
+	vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	assert.NoError(ApplyResourcePolicy(vm, migration.ResourcePolicy{HugepageSize: ptr.To("1Gi")}, overcommit))
+	domain = vm.Spec.Template.Spec.Domain
+	assert.Equal("1Gi", domain.Memory.Hugepages.PageSize)
+	assert.Equal("400m", domain.Resources.Requests.Cpu().String())
+	assert.Equal("1Gi", domain.Resources.Requests.Memory().String())
+
+	// Dedicated CPUs are not overcommitted and the memory request matches
+	// the limit, as required by KubeVirt.
+	vm = newVM()
+	vm.Spec.Template.Spec.Domain.CPU.DedicatedCPUPlacement = true
+	assert.NoError(ApplyResourcePolicy(vm, migration.ResourcePolicy{ReservedMemory: "256Mi"}, overcommit))
+	domain = vm.Spec.Template.Spec.Domain
+	assert.Nil(domain.Memory.Hugepages)
+	assert.NotContains(domain.Resources.Requests, corev1.ResourceCPU)
+	assert.Equal("4352Mi", domain.Resources.Requests.Memory().String())
+	assert.Equal(domain.Resources.Limits.Memory().Value(), domain.Resources.Requests.Memory().Value())
+
+	// An existing memory request is aligned with the limit for dedicated CPUs.
+	vm = newVM()
+	vm.Spec.Template.Spec.Domain.CPU.DedicatedCPUPlacement = true
+	vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	assert.NoError(ApplyResourcePolicy(vm, migration.ResourcePolicy{HugepageSize: ptr.To("1Gi")}, overcommit))
+	domain = vm.Spec.Template.Spec.Domain
+	assert.Equal("4Gi", domain.Resources.Requests.Memory().String())
+
+	vm = newVM()
+	assert.ErrorIs(ApplyResourcePolicy(vm, migration.ResourcePolicy{ReservedMemory: "foo"}, overcommit), ErrInvalidResourcePolicy)
+}
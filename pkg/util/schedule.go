@@ -0,0 +1,76 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+// ValidateImportSchedule checks that the given schedule can be evaluated.
+func ValidateImportSchedule(schedule *migration.ImportSchedule) error {
+	if schedule == nil || schedule.Window == nil {
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(schedule.Window.Cron); err != nil {
+		return fmt.Errorf("%w: invalid cron expression '%s': %v", ErrInvalidSchedule, schedule.Window.Cron, err)
+	}
+
+	if schedule.Window.Duration.Duration <= 0 {
+		return fmt.Errorf("%w: window duration must be greater than zero", ErrInvalidSchedule)
+	}
+
+	return nil
+}
+
+// EvaluateImportSchedule checks whether the given schedule allows an import
+// to start at the specified point in time. If not, the duration to wait until
+// the next opportunity is returned as well. The window is evaluated from
+// `startAfter` if that is still in the future, so the returned duration
+// points to the first point in time allowed by both.
+// An empty schedule is always open.
+func EvaluateImportSchedule(schedule *migration.ImportSchedule, now time.Time) (bool, time.Duration, error) {
+	if schedule == nil {
+		return true, 0, nil
+	}
+
+	// The earliest point in time the import may start.
+	earliest := now
+	if schedule.StartAfter != nil && now.Before(schedule.StartAfter.Time) {
+		earliest = schedule.StartAfter.Time
+	}
+
+	if schedule.Window == nil {
+		if earliest.After(now) {
+			return false, earliest.Sub(now), nil
+		}
+		return true, 0, nil
+	}
+
+	if err := ValidateImportSchedule(schedule); err != nil {
+		return false, 0, err
+	}
+
+	sched, _ := cron.ParseStandard(schedule.Window.Cron)
+	earliest = earliest.UTC()
+
+	// The window that might contain `earliest` must have been opened after
+	// `earliest - duration`. If that opening is not after `earliest`, the
+	// window is open at that point in time; otherwise it is the next window
+	// to wait for.
+	opensAt := sched.Next(earliest.Add(-schedule.Window.Duration.Duration))
+	if opensAt.IsZero() {
+		return false, 0, fmt.Errorf("cron expression '%s' never opens a schedule window", schedule.Window.Cron)
+	}
+	if !opensAt.After(earliest) {
+		opensAt = earliest
+	}
+
+	if wait := opensAt.Sub(now); wait > 0 {
+		return false, wait, nil
+	}
+	return true, 0, nil
+}
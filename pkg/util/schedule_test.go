@@ -0,0 +1,137 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_EvaluateImportSchedule(t *testing.T) {
+	assert := require.New(t)
+	// Saturday, 2025-03-15 23:00 UTC
+	now := time.Date(2025, 3, 15, 23, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc         string
+		schedule     *migration.ImportSchedule
+		expectedOpen bool
+		expectedWait time.Duration
+	}{
+		{
+			desc:         "No schedule",
+			schedule:     nil,
+			expectedOpen: true,
+		},
+		{
+			desc: "Start time in the future",
+			schedule: &migration.ImportSchedule{
+				StartAfter: &metav1.Time{Time: now.Add(2 * time.Hour)},
+			},
+			expectedOpen: false,
+			expectedWait: 2 * time.Hour,
+		},
+		{
+			desc: "Start time in the past",
+			schedule: &migration.ImportSchedule{
+				StartAfter: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+			},
+			expectedOpen: true,
+		},
+		{
+			desc: "Inside the window",
+			schedule: &migration.ImportSchedule{
+				Window: &migration.MaintenanceWindow{
+					Cron:     "0 22 * * SAT",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+				},
+			},
+			expectedOpen: true,
+		},
+		{
+			desc: "Window already closed",
+			schedule: &migration.ImportSchedule{
+				Window: &migration.MaintenanceWindow{
+					Cron:     "0 22 * * SAT",
+					Duration: metav1.Duration{Duration: 30 * time.Minute},
+				},
+			},
+			expectedOpen: false,
+			expectedWait: 7*24*time.Hour - time.Hour,
+		},
+		{
+			desc: "Window opens later",
+			schedule: &migration.ImportSchedule{
+				Window: &migration.MaintenanceWindow{
+					Cron:     "30 23 * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+				},
+			},
+			expectedOpen: false,
+			expectedWait: 30 * time.Minute,
+		},
+		{
+			desc: "Window is open when the start time passes",
+			schedule: &migration.ImportSchedule{
+				StartAfter: &metav1.Time{Time: now.Add(30 * time.Minute)},
+				Window: &migration.MaintenanceWindow{
+					Cron:     "0 22 * * SAT",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+				},
+			},
+			expectedOpen: false,
+			expectedWait: 30 * time.Minute,
+		},
+		{
+			desc: "Window is closed when the start time passes",
+			schedule: &migration.ImportSchedule{
+				StartAfter: &metav1.Time{Time: now.Add(2 * time.Hour)},
+				Window: &migration.MaintenanceWindow{
+					Cron:     "0 22 * * SAT",
+					Duration: metav1.Duration{Duration: 90 * time.Minute},
+				},
+			},
+			expectedOpen: false,
+			expectedWait: 7*24*time.Hour - time.Hour,
+		},
+		{
+			desc: "Start time in the past inside the window",
+			schedule: &migration.ImportSchedule{
+				StartAfter: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+				Window: &migration.MaintenanceWindow{
+					Cron:     "0 22 * * SAT",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+				},
+			},
+			expectedOpen: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		open, wait, err := EvaluateImportSchedule(tc.schedule, now)
+		assert.NoError(err, tc.desc)
+		assert.Equal(tc.expectedOpen, open, tc.desc)
+		assert.Equal(tc.expectedWait, wait, tc.desc)
+	}
+}
+
+func Test_ValidateImportSchedule(t *testing.T) {
+	assert := require.New(t)
+
+	err := ValidateImportSchedule(&migration.ImportSchedule{
+		Window: &migration.MaintenanceWindow{
+			Cron:     "not a cron",
+			Duration: metav1.Duration{Duration: time.Hour},
+		},
+	})
+	assert.Error(err, "expected invalid cron expression to fail")
+
+	err = ValidateImportSchedule(&migration.ImportSchedule{
+		Window: &migration.MaintenanceWindow{
+			Cron: "0 22 * * SAT",
+		},
+	})
+	assert.Error(err, "expected empty window duration to fail")
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+)
+
+const (
+	// certSecretName is the name of the secret in the namespace of the
+	// controller that contains the certificate of the webhook server.
+	certSecretName = "harvester-vm-import-controller-webhook-tls"
+
+	// certRenewBefore is the time before the expiry of the certificate at
+	// which a new certificate is generated.
+	certRenewBefore = 30 * 24 * time.Hour
+
+	// certCheckInterval is the interval in which the certificate is checked
+	// for renewal while the webhook server is running.
+	certCheckInterval = 12 * time.Hour
+)
+
+// ensureCertificate returns the certificate and key of the webhook server.
+// The self-signed certificate is shared by all replicas of the controller
+// through a secret, so that every replica is trusted by the CA bundle that
+// is registered at the API server. A new certificate is only generated if
+// the secret does not exist yet or the certificate is about to expire.
+func ensureCertificate(ctx context.Context, client kubernetes.Interface, now time.Time) ([]byte, []byte, error) {
+	secrets := client.CoreV1().Secrets(namespace())
+	secret, err := secrets.Get(ctx, certSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secret, err = newCertificateSecret()
+		if err != nil {
+			return nil, nil, err
+		}
+		secret, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Another replica has created the secret in the meantime.
+			secret, err = secrets.Get(ctx, certSecretName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !certificateValid(secret, now) {
+		renewed, err := newCertificateSecret()
+		if err != nil {
+			return nil, nil, err
+		}
+		secretCopy := secret.DeepCopy()
+		secretCopy.Data = renewed.Data
+		secret, err = secrets.Update(ctx, secretCopy, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			// Another replica has renewed the certificate in the meantime.
+			secret, err = secrets.Get(ctx, certSecretName, metav1.GetOptions{})
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], nil
+}
+
+// renewCertificatePeriodically renews the certificate before it expires
+// until the context is cancelled. The renewed certificate is picked up by the
+// webhook server and the CA bundles through the shared secret.
+func renewCertificatePeriodically(ctx context.Context, client kubernetes.Interface, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := ensureCertificate(ctx, client, time.Now()); err != nil {
+				logrus.Errorf("Failed to renew webhook certificate: %v", err)
+			}
+		}
+	}
+}
+
+func newCertificateSecret() (*corev1.Secret, error) {
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(serviceHost(), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook certificate: %w", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      certSecretName,
+			Namespace: namespace(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}, nil
+}
+
+// certificateValid checks that the secret contains a certificate and key
+// that do not expire within the renewal period.
+func certificateValid(secret *corev1.Secret, now time.Time) bool {
+	if _, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		return false
+	}
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil || len(certs) == 0 {
+		return false
+	}
+	return now.Add(certRenewBefore).Before(certs[0].NotAfter)
+}
+
+// certificateLoader serves the certificate from the shared secret, so that a
+// certificate that has been renewed by another replica is picked up without
+// a restart.
+type certificateLoader struct {
+	secrets ctlcorev1.SecretCache
+
+	mu              sync.Mutex
+	resourceVersion string
+	keyPair         *tls.Certificate
+}
+
+func (l *certificateLoader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	secret, err := l.secrets.Get(namespace(), certSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook certificate: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.keyPair != nil && l.resourceVersion == secret.ResourceVersion {
+		return l.keyPair, nil
+	}
+
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook certificate: %w", err)
+	}
+
+	l.resourceVersion = secret.ResourceVersion
+	l.keyPair = &keyPair
+	return l.keyPair, nil
+}
+
+// webhookCABundleInjector updates the CA bundle of the admission webhook
+// configurations once the certificate has been renewed.
+type webhookCABundleInjector struct {
+	ctx    context.Context
+	client kubernetes.Interface
+
+	mu       sync.Mutex
+	caBundle []byte
+}
+
+func (h *webhookCABundleInjector) OnSecretChange(_ string, secret *corev1.Secret) (*corev1.Secret, error) {
+	if secret == nil || secret.Namespace != namespace() || secret.Name != certSecretName {
+		return secret, nil
+	}
+	return secret, h.inject(secret.Data[corev1.TLSCertKey])
+}
+
+// inject registers the admission webhooks with the given CA bundle unless
+// they have been registered with it already.
+func (h *webhookCABundleInjector) inject(caBundle []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(caBundle) == 0 || bytes.Equal(h.caBundle, caBundle) {
+		return nil
+	}
+
+	if err := ensureValidatingWebhookConfiguration(h.ctx, h.client, caBundle); err != nil {
+		return fmt.Errorf("failed to register validating webhook: %w", err)
+	}
+	if err := ensureMutatingWebhookConfiguration(h.ctx, h.client, caBundle); err != nil {
+		return fmt.Errorf("failed to register mutating webhook: %w", err)
+	}
+
+	h.caBundle = caBundle
+	return nil
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ensureCertificate(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+
+	// The certificate is generated once and shared by all replicas.
+	certPEM, keyPEM, err := ensureCertificate(ctx, client, now)
+	assert.NoError(err)
+	assert.NotEmpty(certPEM)
+	assert.NotEmpty(keyPEM)
+
+	certPEM2, keyPEM2, err := ensureCertificate(ctx, client, now)
+	assert.NoError(err)
+	assert.Equal(certPEM, certPEM2)
+	assert.Equal(keyPEM, keyPEM2)
+
+	secret, err := client.CoreV1().Secrets(namespace()).Get(ctx, certSecretName, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal(corev1.SecretTypeTLS, secret.Type)
+	assert.True(certificateValid(secret, now))
+
+	// A certificate that is about to expire is renewed.
+	assert.False(certificateValid(secret, now.Add(365*24*time.Hour)))
+	certPEM3, _, err := ensureCertificate(ctx, client, now.Add(365*24*time.Hour))
+	assert.NoError(err)
+	assert.NotEqual(certPEM, certPEM3)
+
+	assert.False(certificateValid(&corev1.Secret{}, now), "expected a secret without certificate to be invalid")
+}
+
+func Test_webhookCABundleInjector(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	h := &webhookCABundleInjector{ctx: ctx, client: client}
+
+	caBundle := func() ([]byte, []byte) {
+		validating, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, validatingWebhookName, metav1.GetOptions{})
+		assert.NoError(err)
+		mutating, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, mutatingWebhookName, metav1.GetOptions{})
+		assert.NoError(err)
+		return validating.Webhooks[0].ClientConfig.CABundle, mutating.Webhooks[0].ClientConfig.CABundle
+	}
+
+	// The webhooks are registered with the certificate at startup.
+	assert.NoError(h.inject([]byte("ca-1")))
+	validating, mutating := caBundle()
+	assert.Equal([]byte("ca-1"), validating)
+	assert.Equal([]byte("ca-1"), mutating)
+
+	// Other secrets are ignored.
+	_, err := h.OnSecretChange("", &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: namespace()},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("ca-other")},
+	})
+	assert.NoError(err)
+	validating, _ = caBundle()
+	assert.Equal([]byte("ca-1"), validating)
+
+	// A renewed certificate is injected into the webhook configurations.
+	_, err = h.OnSecretChange("", &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: certSecretName, Namespace: namespace()},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("ca-2")},
+	})
+	assert.NoError(err)
+	validating, mutating = caBundle()
+	assert.Equal([]byte("ca-2"), validating)
+	assert.Equal([]byte("ca-2"), mutating)
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	ctlapiextv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/apiextensions.k8s.io/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	migrationv1beta2 "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta2"
+)
+
+const (
+	conversionPath = "/v1/webhook/conversion"
+
+	virtualMachineImportCRDName = "virtualmachineimports.migration.harvesterhci.io"
+)
+
+// ConversionClientConfig returns the client configuration of the conversion
+// webhook that is used in the CRDs. The CA bundle is injected by the webhook
+// server once its certificate has been generated, and is preserved when the
+// CRDs are re-applied.
+func ConversionClientConfig() *apiextv1.WebhookClientConfig {
+	return &apiextv1.WebhookClientConfig{
+		Service: &apiextv1.ServiceReference{
+			Namespace: namespace(),
+			Name:      serviceName(),
+			Path:      ptr.To(conversionPath),
+			Port:      ptr.To[int32](defaultPort),
+		},
+	}
+}
+
+// conversionHandler serves the conversion requests of the API server.
+type conversionHandler struct{}
+
+func (h *conversionHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	review := &apiextv1.ConversionReview{}
+	if err := json.NewDecoder(req.Body).Decode(review); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if review.Request == nil {
+		http.Error(rw, "request is not set", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = convertObjects(review.Request)
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(review); err != nil {
+		logrus.Errorf("Failed to write conversion response: %v", err)
+	}
+}
+
+func convertObjects(req *apiextv1.ConversionRequest) *apiextv1.ConversionResponse {
+	resp := &apiextv1.ConversionResponse{
+		UID: req.UID,
+		Result: metav1.Status{
+			Status: metav1.StatusSuccess,
+		},
+	}
+
+	for _, obj := range req.Objects {
+		converted, err := convertObject(obj.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			logrus.Errorf("Failed to convert object to %s: %v", req.DesiredAPIVersion, err)
+			resp.ConvertedObjects = nil
+			resp.Result = metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+			}
+			return resp
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	return resp
+}
+
+// convertObject converts the given `VirtualMachineImport` into the desired
+// API version.
+func convertObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, err
+	}
+
+	if typeMeta.Kind != "VirtualMachineImport" {
+		return nil, fmt.Errorf("unsupported kind %q", typeMeta.Kind)
+	}
+
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	switch {
+	case typeMeta.APIVersion == migration.SchemeGroupVersion.String() && desiredAPIVersion == migrationv1beta2.SchemeGroupVersion.String():
+		in := &migration.VirtualMachineImport{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return json.Marshal(migrationv1beta2.ConvertFromV1beta1(in))
+	case typeMeta.APIVersion == migrationv1beta2.SchemeGroupVersion.String() && desiredAPIVersion == migration.SchemeGroupVersion.String():
+		in := &migrationv1beta2.VirtualMachineImport{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return json.Marshal(migrationv1beta2.ConvertToV1beta1(in))
+	default:
+		return nil, fmt.Errorf("unsupported conversion from %q to %q", typeMeta.APIVersion, desiredAPIVersion)
+	}
+}
+
+// caBundleInjector keeps the CA bundle of the conversion webhook in the CRDs
+// up to date. The CA bundle is read from the certificate secret that is
+// shared by all replicas, so that the replicas never overwrite each other.
+type caBundleInjector struct {
+	crds    ctlapiextv1.CustomResourceDefinitionController
+	secrets ctlcorev1.SecretCache
+}
+
+func (h *caBundleInjector) OnCRDChange(_ string, crd *apiextv1.CustomResourceDefinition) (*apiextv1.CustomResourceDefinition, error) {
+	if crd == nil || crd.DeletionTimestamp != nil || crd.Name != virtualMachineImportCRDName {
+		return crd, nil
+	}
+
+	conversion := crd.Spec.Conversion
+	if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+		return crd, nil
+	}
+
+	secret, err := h.secrets.Get(namespace(), certSecretName)
+	if err != nil {
+		return crd, fmt.Errorf("failed to get webhook certificate: %w", err)
+	}
+
+	caBundle := secret.Data[corev1.TLSCertKey]
+	if len(caBundle) == 0 || bytes.Equal(conversion.Webhook.ClientConfig.CABundle, caBundle) {
+		return crd, nil
+	}
+
+	crdCopy := crd.DeepCopy()
+	crdCopy.Spec.Conversion.Webhook.ClientConfig.CABundle = caBundle
+	return h.crds.Update(crdCopy)
+}
+
+// OnSecretChange updates the CA bundle of the CRD once the certificate has
+// been renewed.
+func (h *caBundleInjector) OnSecretChange(_ string, secret *corev1.Secret) (*corev1.Secret, error) {
+	if secret == nil || secret.Namespace != namespace() || secret.Name != certSecretName {
+		return secret, nil
+	}
+	h.crds.Enqueue(virtualMachineImportCRDName)
+	return secret, nil
+}
+
+func registerCABundleInjector(ctx context.Context, crds ctlapiextv1.CustomResourceDefinitionController, secrets ctlcorev1.SecretController) {
+	h := &caBundleInjector{
+		crds:    crds,
+		secrets: secrets.Cache(),
+	}
+	crds.OnChange(ctx, "conversion-webhook-ca-bundle", h.OnCRDChange)
+	secrets.OnChange(ctx, "conversion-webhook-certificate", h.OnSecretChange)
+}
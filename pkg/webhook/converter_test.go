@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/harvester/vm-import-controller/pkg/apis/common"
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	migrationv1beta2 "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta2"
+)
+
+func newTestImport() *migration.VirtualMachineImport {
+	return &migration.VirtualMachineImport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: migration.SchemeGroupVersion.String(),
+			Kind:       "VirtualMachineImport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alpine-export-test",
+			Namespace: "default",
+		},
+		Spec: migration.VirtualMachineImportSpec{
+			SourceCluster: corev1.ObjectReference{
+				APIVersion: migration.SchemeGroupVersion.String(),
+				Kind:       "VmwareSource",
+				Name:       "vcsim",
+				Namespace:  "default",
+			},
+			VirtualMachineName: "alpine-export-test",
+			Folder:             "/DC0/vm/imports",
+			Mapping: []migration.NetworkMapping{
+				{SourceNetwork: "dvSwitch 1", DestinationNetwork: "default/vlan1"},
+			},
+			StorageClass:                   "longhorn",
+			DefaultDiskBusType:             ptr.To(kubevirtv1.DiskBusSATA),
+			ForcePowerOff:                  ptr.To(true),
+			GracefulShutdownTimeoutSeconds: 120,
+			SkipPreflightChecks:            ptr.To(false),
+			CDROMImport:                    ptr.To(migration.CDROMImportImage),
+			CPU: &migration.CPUOptions{
+				Model:             "host-passthrough",
+				PreservePlacement: ptr.To(true),
+			},
+			MetadataMapping: []migration.MetadataMapping{
+				{SourceKey: "tag/*", Target: ptr.To(migration.MetadataTargetLabel)},
+			},
+			ResourcePolicy: &migration.ResourcePolicy{
+				CPURequestPercentage: ptr.To(int32(25)),
+				ReservedMemory:       "256Mi",
+			},
+		},
+		Status: migration.VirtualMachineImportStatus{
+			Status: migration.DiskImagesSubmitted,
+			DiskImportStatus: []migration.DiskInfo{
+				{
+					Name:                "disk-0.img",
+					DiskSize:            1024,
+					VirtualMachineImage: "image-abcde",
+					BusType:             kubevirtv1.DiskBusSATA,
+					BootDisk:            true,
+					BootOrder:           1,
+					StorageClass:        "longhorn",
+				},
+				{
+					Name:                "alpine-export-test-config.iso",
+					DiskSize:            512,
+					VirtualMachineImage: "image-fghij",
+					BusType:             kubevirtv1.DiskBusSATA,
+					CDROM:               true,
+				},
+			},
+			EmptyCDROMDrives: []migration.CDROMDrive{
+				{DeviceID: "VirtualIDEController1:0", BusType: kubevirtv1.DiskBusSATA},
+			},
+			SourceVirtualMachine: &migration.SourceVirtualMachineIdentity{
+				ID:        "5012c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+				UUID:      "4212c4d3-8a6b-4f1a-9b6c-6a1b2c3d4e5f",
+				Reference: "vm-42",
+			},
+			ImportTime: "2025-03-15T22:00:00Z",
+			StorageClassMapping: []migration.StorageClassMapping{
+				{Datastore: "datastore*", StorageClass: "longhorn"},
+			},
+			ImportConditions: []common.Condition{
+				{Type: migration.VirtualMachineExported, Status: corev1.ConditionTrue},
+			},
+			ImportedVirtualMachineName: "alpine-export-test",
+			PhaseHistory: []migration.PhaseTiming{
+				{Step: migration.ImportStepPreflight, EnteredTime: "2025-03-15T22:00:00Z", ExitedTime: "2025-03-15T22:01:00Z"},
+				{Phase: migration.DiskImagesSubmitted, EnteredTime: "2025-03-15T22:01:00Z"},
+			},
+		},
+	}
+}
+
+func Test_convertObject(t *testing.T) {
+	assert := require.New(t)
+	vm := newTestImport()
+
+	raw, err := json.Marshal(vm)
+	assert.NoError(err)
+
+	converted, err := convertObject(raw, migrationv1beta2.SchemeGroupVersion.String())
+	assert.NoError(err)
+
+	v1beta2VM := &migrationv1beta2.VirtualMachineImport{}
+	assert.NoError(json.Unmarshal(converted, v1beta2VM))
+	assert.Equal(migrationv1beta2.SchemeGroupVersion.String(), v1beta2VM.APIVersion)
+	assert.Equal(migrationv1beta2.ImportPhaseDiskImagesSubmitted, v1beta2VM.Status.Phase)
+	assert.Equal(migrationv1beta2.ImportPhasePending, v1beta2VM.Status.PhaseHistory[0].Phase)
+	assert.Equal("/DC0/vm/imports", v1beta2VM.Spec.Vmware.Folder)
+	assert.Equal(int32(120), v1beta2VM.Spec.Vmware.GracefulShutdownTimeoutSeconds)
+	assert.Equal("image-abcde", v1beta2VM.Status.Disks[0].VirtualMachineImage)
+	assert.Contains(string(converted), `"virtualMachineImage":"image-abcde"`)
+
+	// Converting back must not lose any information.
+	roundTrip, err := convertObject(converted, migration.SchemeGroupVersion.String())
+	assert.NoError(err)
+
+	v1beta1VM := &migration.VirtualMachineImport{}
+	assert.NoError(json.Unmarshal(roundTrip, v1beta1VM))
+	assert.Equal(vm, v1beta1VM)
+
+	_, err = convertObject(raw, "migration.harvesterhci.io/v1")
+	assert.Error(err, "expected conversion to unknown version to fail")
+}
+
+func Test_convertObjects(t *testing.T) {
+	assert := require.New(t)
+
+	raw, err := json.Marshal(newTestImport())
+	assert.NoError(err)
+
+	resp := convertObjects(&apiextv1.ConversionRequest{
+		UID:               "1234",
+		DesiredAPIVersion: migrationv1beta2.SchemeGroupVersion.String(),
+		Objects:           []runtime.RawExtension{{Raw: raw}},
+	})
+	assert.Equal(metav1.StatusSuccess, resp.Result.Status)
+	assert.Len(resp.ConvertedObjects, 1)
+
+	resp = convertObjects(&apiextv1.ConversionRequest{
+		UID:               "1234",
+		DesiredAPIVersion: migrationv1beta2.SchemeGroupVersion.String(),
+		Objects:           []runtime.RawExtension{{Raw: []byte(`{"apiVersion":"v1","kind":"Pod"}`)}},
+	})
+	assert.Equal(metav1.StatusFailure, resp.Result.Status)
+	assert.Empty(resp.ConvertedObjects)
+}
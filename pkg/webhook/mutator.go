@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/webhook"
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+const (
+	// defaultsConfigMapName is the name of the ConfigMap in the namespace of
+	// the controller that contains the cluster-level defaults for imports.
+	defaultsConfigMapName = "vm-import-controller-defaults"
+
+	configKeyStorageClass          = "defaultStorageClass"
+	configKeyDiskBusType           = "defaultDiskBusType"
+	configKeyNetworkInterfaceModel = "defaultNetworkInterfaceModel"
+	configKeyNetworkMapping        = "defaultNetworkMapping"
+)
+
+var (
+	// supportedDiskBusTypes and supportedNetworkInterfaceModels are the
+	// values that are allowed by the CRD of VirtualMachineImport.
+	supportedDiskBusTypes = []kubevirtv1.DiskBus{
+		kubevirtv1.DiskBusVirtio,
+		kubevirtv1.DiskBusSATA,
+		kubevirtv1.DiskBusSCSI,
+		kubevirtv1.DiskBusUSB,
+	}
+	supportedNetworkInterfaceModels = []string{
+		migration.NetworkInterfaceModelE1000,
+		migration.NetworkInterfaceModelE1000e,
+		migration.NetworkInterfaceModelNe2kPci,
+		migration.NetworkInterfaceModelPcnet,
+		migration.NetworkInterfaceModelRtl8139,
+		migration.NetworkInterfaceModelVirtio,
+	}
+)
+
+// clusterDefaults contains the defaults that are configured for all imports
+// of the cluster.
+type clusterDefaults struct {
+	StorageClass          string
+	DiskBusType           kubevirtv1.DiskBus
+	NetworkInterfaceModel string
+	NetworkMapping        []migration.NetworkMapping
+}
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type mutator struct {
+	configMapCache ctlcorev1.ConfigMapCache
+
+	// mu guards the defaults that were parsed from the ConfigMap with the
+	// given resource version.
+	mu              sync.Mutex
+	resourceVersion string
+	defaults        *clusterDefaults
+}
+
+func newMutator(configMapCache ctlcorev1.ConfigMapCache) *mutator {
+	return &mutator{
+		configMapCache: configMapCache,
+	}
+}
+
+// register adds the mutation handlers to the given router.
+func (m *mutator) register(router *webhook.Router) {
+	router.Kind("VirtualMachineImport").Group(migration.SchemeGroupVersion.Group).Operation(admissionv1.Create).
+		Type(&migration.VirtualMachineImport{}).HandleFunc(m.admitVirtualMachineImport)
+}
+
+func (m *mutator) admitVirtualMachineImport(resp *webhook.Response, req *webhook.Request) error {
+	obj, err := req.DecodeObject()
+	if err != nil {
+		return err
+	}
+	vm := obj.(*migration.VirtualMachineImport)
+
+	defaults, err := m.clusterDefaults()
+	if err != nil {
+		return err
+	}
+
+	applyImportDefaults(vm, defaults)
+
+	patch, err := json.Marshal([]patchOperation{
+		{Op: "replace", Path: "/spec", Value: vm.Spec},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp.Patch = patch
+	resp.PatchType = ptr.To(admissionv1.PatchTypeJSONPatch)
+	resp.Allowed = true
+	return nil
+}
+
+// clusterDefaults reads the cluster-level defaults from the ConfigMap. The
+// ConfigMap is optional. It is only parsed again if it has changed, so that
+// invalid values are logged once per change.
+func (m *mutator) clusterDefaults() (*clusterDefaults, error) {
+	cm, err := m.configMapCache.Get(namespace(), defaultsConfigMapName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &clusterDefaults{}, nil
+		}
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.defaults == nil || m.resourceVersion != cm.ResourceVersion {
+		m.defaults = parseClusterDefaults(cm.Data)
+		m.resourceVersion = cm.ResourceVersion
+	}
+
+	return m.defaults, nil
+}
+
+// parseClusterDefaults parses the data of the ConfigMap. Invalid values are
+// logged and ignored, so that a misconfigured ConfigMap does not block the
+// creation of imports.
+func parseClusterDefaults(data map[string]string) *clusterDefaults {
+	defaults := &clusterDefaults{
+		StorageClass:          data[configKeyStorageClass],
+		DiskBusType:           kubevirtv1.DiskBus(data[configKeyDiskBusType]),
+		NetworkInterfaceModel: data[configKeyNetworkInterfaceModel],
+	}
+
+	if defaults.DiskBusType != "" && !slices.Contains(supportedDiskBusTypes, defaults.DiskBusType) {
+		logrus.Warnf("Ignoring unsupported %q %q in ConfigMap %s/%s",
+			configKeyDiskBusType, defaults.DiskBusType, namespace(), defaultsConfigMapName)
+		defaults.DiskBusType = ""
+	}
+
+	if defaults.NetworkInterfaceModel != "" && !slices.Contains(supportedNetworkInterfaceModels, defaults.NetworkInterfaceModel) {
+		logrus.Warnf("Ignoring unsupported %q %q in ConfigMap %s/%s",
+			configKeyNetworkInterfaceModel, defaults.NetworkInterfaceModel, namespace(), defaultsConfigMapName)
+		defaults.NetworkInterfaceModel = ""
+	}
+
+	if val, ok := data[configKeyNetworkMapping]; ok && val != "" {
+		var mapping []migration.NetworkMapping
+		err := yaml.Unmarshal([]byte(val), &mapping)
+		if err == nil {
+			err = validateNetworkMappings(mapping)
+		}
+		if err != nil {
+			logrus.Warnf("Ignoring invalid %q of ConfigMap %s/%s: %v",
+				configKeyNetworkMapping, namespace(), defaultsConfigMapName, err)
+		} else {
+			defaults.NetworkMapping = mapping
+		}
+	}
+
+	return defaults
+}
+
+// applyImportDefaults writes the effective defaults into the spec of the
+// given import. Values that are set explicitly are never overwritten.
+func applyImportDefaults(vm *migration.VirtualMachineImport, defaults *clusterDefaults) {
+	spec := &vm.Spec
+
+	if spec.StorageClass == "" {
+		spec.StorageClass = defaults.StorageClass
+	}
+
+	if len(spec.Mapping) == 0 && len(defaults.NetworkMapping) > 0 {
+		spec.Mapping = append([]migration.NetworkMapping{}, defaults.NetworkMapping...)
+	}
+
+	if spec.DefaultDiskBusType == nil && defaults.DiskBusType != "" {
+		spec.DefaultDiskBusType = ptr.To(defaults.DiskBusType)
+	}
+	spec.DefaultDiskBusType = ptr.To(vm.GetDefaultDiskBusType())
+
+	if spec.DefaultNetworkInterfaceModel == nil && defaults.NetworkInterfaceModel != "" {
+		spec.DefaultNetworkInterfaceModel = ptr.To(defaults.NetworkInterfaceModel)
+	}
+	spec.DefaultNetworkInterfaceModel = ptr.To(vm.GetDefaultNetworkInterfaceModel())
+
+	// Powering off the source VM is only supported for VMware.
+	if strings.ToLower(spec.SourceCluster.Kind) == migration.KindVmwareSource {
+		spec.ForcePowerOff = ptr.To(vm.GetForcePowerOff())
+		spec.GracefulShutdownTimeoutSeconds = vm.GetGracefulShutdownTimeoutSeconds()
+	}
+	spec.SkipPreflightChecks = ptr.To(vm.SkipPreflightChecks())
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"testing"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_parseClusterDefaults(t *testing.T) {
+	assert := require.New(t)
+
+	defaults := parseClusterDefaults(map[string]string{
+		configKeyStorageClass: "longhorn",
+		configKeyDiskBusType:  "sata",
+		configKeyNetworkMapping: `
+- sourceNetwork: VM Network
+  destinationNetwork: default/vlan1
+`,
+	})
+	assert.Equal("longhorn", defaults.StorageClass)
+	assert.Equal(kubevirtv1.DiskBusSATA, defaults.DiskBusType)
+	assert.Empty(defaults.NetworkInterfaceModel)
+	assert.Equal([]migration.NetworkMapping{
+		{SourceNetwork: "VM Network", DestinationNetwork: "default/vlan1"},
+	}, defaults.NetworkMapping)
+
+	// Invalid values are ignored, the other values are still used.
+	testCases := []struct {
+		desc string
+		data map[string]string
+	}{
+		{
+			desc: "Unparsable network mapping",
+			data: map[string]string{configKeyNetworkMapping: "not a list"},
+		},
+		{
+			desc: "Invalid destination network",
+			data: map[string]string{configKeyNetworkMapping: `
+- sourceNetwork: VM Network
+  destinationNetwork: default/vlan1
+- sourceNetwork: Other Network
+  destinationNetwork: a/b/c
+`},
+		},
+		{
+			desc: "Invalid source network pattern",
+			data: map[string]string{configKeyNetworkMapping: `
+- sourceNetwork: "VM [Network"
+  destinationNetwork: default/vlan1
+  matchType: glob
+`},
+		},
+		{
+			desc: "Duplicate source network",
+			data: map[string]string{configKeyNetworkMapping: `
+- sourceNetwork: VM Network
+  destinationNetwork: default/vlan1
+- sourceNetwork: VM Network
+  destinationNetwork: default/vlan2
+`},
+		},
+		{
+			desc: "Unsupported disk bus type",
+			data: map[string]string{configKeyDiskBusType: "ide"},
+		},
+		{
+			desc: "Unsupported network interface model",
+			data: map[string]string{configKeyNetworkInterfaceModel: "vmxnet3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.data[configKeyStorageClass] = "longhorn"
+		defaults := parseClusterDefaults(tc.data)
+		assert.Equal(&clusterDefaults{StorageClass: "longhorn"}, defaults, tc.desc)
+	}
+}
+
+// fakeConfigMapCache returns the given ConfigMap. All other methods are not
+// implemented.
+type fakeConfigMapCache struct {
+	ctlcorev1.ConfigMapCache
+
+	cm *corev1.ConfigMap
+}
+
+func (c *fakeConfigMapCache) Get(namespace, name string) (*corev1.ConfigMap, error) {
+	if c.cm == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+	}
+	return c.cm, nil
+}
+
+func Test_clusterDefaults(t *testing.T) {
+	assert := require.New(t)
+	cache := &fakeConfigMapCache{}
+	m := newMutator(cache)
+
+	defaults, err := m.clusterDefaults()
+	assert.NoError(err)
+	assert.Equal(&clusterDefaults{}, defaults)
+
+	// An invalid ConfigMap does not fail the admission.
+	cache.cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultsConfigMapName, ResourceVersion: "1"},
+		Data: map[string]string{
+			configKeyStorageClass: "longhorn",
+			configKeyDiskBusType:  "ide",
+		},
+	}
+	defaults, err = m.clusterDefaults()
+	assert.NoError(err)
+	assert.Equal(&clusterDefaults{StorageClass: "longhorn"}, defaults)
+
+	// The ConfigMap is parsed again once it has changed.
+	cache.cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultsConfigMapName, ResourceVersion: "2"},
+		Data: map[string]string{
+			configKeyStorageClass: "longhorn",
+			configKeyDiskBusType:  "sata",
+		},
+	}
+	defaults, err = m.clusterDefaults()
+	assert.NoError(err)
+	assert.Equal(kubevirtv1.DiskBusSATA, defaults.DiskBusType)
+}
+
+func Test_applyImportDefaults(t *testing.T) {
+	assert := require.New(t)
+	defaults := &clusterDefaults{
+		StorageClass: "longhorn",
+		DiskBusType:  kubevirtv1.DiskBusSATA,
+		NetworkMapping: []migration.NetworkMapping{
+			{SourceNetwork: "VM Network", DestinationNetwork: "default/vlan1"},
+		},
+	}
+
+	vm := &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			SourceCluster: corev1.ObjectReference{Kind: "VmwareSource"},
+		},
+	}
+	applyImportDefaults(vm, defaults)
+	assert.Equal("longhorn", vm.Spec.StorageClass)
+	assert.Equal(defaults.NetworkMapping, vm.Spec.Mapping)
+	assert.Equal(kubevirtv1.DiskBusSATA, *vm.Spec.DefaultDiskBusType)
+	assert.Equal(migration.NetworkInterfaceModelVirtio, *vm.Spec.DefaultNetworkInterfaceModel)
+	assert.False(*vm.Spec.ForcePowerOff)
+	assert.Equal(int32(migration.DefaultGracefulShutdownTimeoutSeconds), vm.Spec.GracefulShutdownTimeoutSeconds)
+	assert.False(*vm.Spec.SkipPreflightChecks)
+
+	// VMware specific defaults are not set for other sources.
+	vm = &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			SourceCluster: corev1.ObjectReference{Kind: "OpenstackSource"},
+		},
+	}
+	applyImportDefaults(vm, defaults)
+	assert.Nil(vm.Spec.ForcePowerOff)
+	assert.Zero(vm.Spec.GracefulShutdownTimeoutSeconds)
+	assert.False(*vm.Spec.SkipPreflightChecks)
+
+	// Explicit values are never overwritten.
+	vm = &migration.VirtualMachineImport{
+		Spec: migration.VirtualMachineImportSpec{
+			SourceCluster:      corev1.ObjectReference{Kind: "VmwareSource"},
+			StorageClass:       "harvester-longhorn",
+			DefaultDiskBusType: ptr.To(kubevirtv1.DiskBusSCSI),
+			Mapping: []migration.NetworkMapping{
+				{SourceNetwork: "Other Network", DestinationNetwork: "vlan2"},
+			},
+			GracefulShutdownTimeoutSeconds: 300,
+		},
+	}
+	applyImportDefaults(vm, defaults)
+	assert.Equal("harvester-longhorn", vm.Spec.StorageClass)
+	assert.Equal("Other Network", vm.Spec.Mapping[0].SourceNetwork)
+	assert.Len(vm.Spec.Mapping, 1)
+	assert.Equal(kubevirtv1.DiskBusSCSI, *vm.Spec.DefaultDiskBusType)
+	assert.Equal(int32(300), vm.Spec.GracefulShutdownTimeoutSeconds)
+}
@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/generated/controllers/apiextensions.k8s.io"
+	"github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
+	"github.com/rancher/wrangler/v3/pkg/generated/controllers/storage"
+	"github.com/rancher/wrangler/v3/pkg/start"
+	"github.com/rancher/wrangler/v3/pkg/webhook"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+const (
+	defaultPort        = 8443
+	defaultServiceName = "harvester-vm-import-controller"
+	defaultNamespace   = "harvester-system"
+
+	validationPath        = "/v1/webhook/validation"
+	mutationPath          = "/v1/webhook/mutation"
+	validatingWebhookName = "vm-import-controller-validator"
+	mutatingWebhookName   = "vm-import-controller-mutator"
+)
+
+// Disabled checks whether the webhooks are disabled for local testing by
+// setting the env variable DISABLE_WEBHOOK to `true`.
+func Disabled() bool {
+	val := os.Getenv("DISABLE_WEBHOOK")
+	return val == "true" || val == "TRUE"
+}
+
+// Start serves the admission webhooks of the controller and registers them
+// at the API server, unless the webhooks are disabled.
+func Start(ctx context.Context, restConfig *rest.Config) error {
+	if Disabled() {
+		logrus.Info("Admission webhooks are disabled")
+		return nil
+	}
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	coreFactory, err := core.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	storageFactory, err := storage.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	// The cluster defaults are only read from the namespace of the controller.
+	configFactory, err := core.NewFactoryFromConfigWithOptions(restConfig, &core.FactoryOptions{
+		Namespace: namespace(),
+	})
+	if err != nil {
+		return err
+	}
+
+	apiextFactory, err := apiextensions.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	validationRouter := webhook.NewRouter()
+	newValidator(coreFactory.Core().V1().Secret().Cache(), storageFactory.Storage().V1().StorageClass().Cache()).register(validationRouter)
+
+	mutationRouter := webhook.NewRouter()
+	newMutator(configFactory.Core().V1().ConfigMap().Cache()).register(mutationRouter)
+
+	certPEM, _, err := ensureCertificate(ctx, client, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to ensure webhook certificate: %w", err)
+	}
+
+	secrets := configFactory.Core().V1().Secret()
+	registerCABundleInjector(ctx, apiextFactory.Apiextensions().V1().CustomResourceDefinition(), secrets)
+
+	// The webhook configurations are registered with the current
+	// certificate and updated whenever the certificate is renewed.
+	webhookInjector := &webhookCABundleInjector{ctx: ctx, client: client}
+	secrets.OnChange(ctx, "admission-webhook-certificate", webhookInjector.OnSecretChange)
+
+	if err := start.All(ctx, 1, coreFactory, storageFactory, configFactory, apiextFactory); err != nil {
+		return err
+	}
+
+	if err := webhookInjector.inject(certPEM); err != nil {
+		return err
+	}
+
+	go renewCertificatePeriodically(ctx, client, certCheckInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle(validationPath, validationRouter)
+	mux.Handle(mutationPath, mutationRouter)
+	mux.Handle(conversionPath, &conversionHandler{})
+
+	srv := http.Server{
+		Addr:              fmt.Sprintf(":%d", defaultPort),
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler:           mux,
+		TLSConfig: &tls.Config{
+			GetCertificate: (&certificateLoader{secrets: secrets.Cache()}).GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		},
+	}
+
+	eg, _ := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return srv.ListenAndServeTLS("", "")
+	})
+
+	eg.Go(func() error {
+		<-ctx.Done()
+		return srv.Shutdown(ctx)
+	})
+
+	return eg.Wait()
+}
+
+// ensureValidatingWebhookConfiguration creates or updates the webhook
+// configuration that routes the admission requests to this controller.
+func ensureValidatingWebhookConfiguration(ctx context.Context, client kubernetes.Interface, caBundle []byte) error {
+	rules := webhookRules([]string{"virtualmachineimports", "vmwaresources", "ovasources", "openstacksources"},
+		admissionregistrationv1.Create, admissionregistrationv1.Update)
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: validatingWebhookName,
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name:                    "validator.migration.harvesterhci.io",
+				ClientConfig:            webhookClientConfig(validationPath, caBundle),
+				Rules:                   rules,
+				FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+				SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+				AdmissionReviewVersions: []string{"v1"},
+				TimeoutSeconds:          ptr.To[int32](10),
+			},
+		},
+	}
+
+	configs := client.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	existing, err := configs.Get(ctx, validatingWebhookName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configs.Create(ctx, config, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	existing.Webhooks = config.Webhooks
+	_, err = configs.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// ensureMutatingWebhookConfiguration creates or updates the webhook
+// configuration that routes the defaulting requests to this controller.
+func ensureMutatingWebhookConfiguration(ctx context.Context, client kubernetes.Interface, caBundle []byte) error {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: mutatingWebhookName,
+		},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				Name:                    "mutator.migration.harvesterhci.io",
+				ClientConfig:            webhookClientConfig(mutationPath, caBundle),
+				Rules:                   webhookRules([]string{"virtualmachineimports"}, admissionregistrationv1.Create),
+				FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+				SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+				AdmissionReviewVersions: []string{"v1"},
+				TimeoutSeconds:          ptr.To[int32](10),
+			},
+		},
+	}
+
+	configs := client.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	existing, err := configs.Get(ctx, mutatingWebhookName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configs.Create(ctx, config, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	existing.Webhooks = config.Webhooks
+	_, err = configs.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+func webhookClientConfig(path string, caBundle []byte) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: namespace(),
+			Name:      serviceName(),
+			Path:      ptr.To(path),
+			Port:      ptr.To[int32](defaultPort),
+		},
+		CABundle: caBundle,
+	}
+}
+
+func webhookRules(resources []string, operations ...admissionregistrationv1.OperationType) []admissionregistrationv1.RuleWithOperations {
+	return []admissionregistrationv1.RuleWithOperations{
+		{
+			Operations: operations,
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{migration.SchemeGroupVersion.Group},
+				APIVersions: []string{migration.SchemeGroupVersion.Version},
+				Resources:   resources,
+				Scope:       ptr.To(admissionregistrationv1.NamespacedScope),
+			},
+		},
+	}
+}
+
+// serviceName returns the name of the service that exposes the webhooks.
+// It can be set with the env variable WEBHOOK_SERVICE_NAME.
+func serviceName() string {
+	if val := os.Getenv("WEBHOOK_SERVICE_NAME"); val != "" {
+		return val
+	}
+	return defaultServiceName
+}
+
+// namespace returns the namespace the controller is running in. It can be
+// set with the env variable NAMESPACE.
+func namespace() string {
+	if val := os.Getenv("NAMESPACE"); val != "" {
+		return val
+	}
+	return defaultNamespace
+}
+
+func serviceHost() string {
+	return fmt.Sprintf("%s.%s.svc", serviceName(), namespace())
+}
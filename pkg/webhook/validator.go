@@ -0,0 +1,369 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	ctlstoragev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/storage/v1"
+	"github.com/rancher/wrangler/v3/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+	"github.com/harvester/vm-import-controller/pkg/util"
+)
+
+// The keys that must exist in the `Secret` referenced by a source.
+var (
+	vmwareSecretKeys    = []string{"username", "password"}
+	openstackSecretKeys = []string{"username", "password", "project_name", "domain_name"}
+)
+
+type validator struct {
+	secretCache ctlcorev1.SecretCache
+	scCache     ctlstoragev1.StorageClassCache
+}
+
+func newValidator(secretCache ctlcorev1.SecretCache, scCache ctlstoragev1.StorageClassCache) *validator {
+	return &validator{
+		secretCache: secretCache,
+		scCache:     scCache,
+	}
+}
+
+// register adds the validation handlers to the given router.
+func (v *validator) register(router *webhook.Router) {
+	router.Kind("VirtualMachineImport").Group(migration.SchemeGroupVersion.Group).
+		Type(&migration.VirtualMachineImport{}).HandleFunc(v.admitVirtualMachineImport)
+	router.Kind("VmwareSource").Group(migration.SchemeGroupVersion.Group).
+		Type(&migration.VmwareSource{}).HandleFunc(v.admitVmwareSource)
+	router.Kind("OvaSource").Group(migration.SchemeGroupVersion.Group).
+		Type(&migration.OvaSource{}).HandleFunc(v.admitOvaSource)
+	router.Kind("OpenstackSource").Group(migration.SchemeGroupVersion.Group).
+		Type(&migration.OpenstackSource{}).HandleFunc(v.admitOpenstackSource)
+}
+
+func (v *validator) admitVirtualMachineImport(resp *webhook.Response, req *webhook.Request) error {
+	obj, err := req.DecodeObject()
+	if err != nil {
+		return err
+	}
+	vm := obj.(*migration.VirtualMachineImport)
+
+	if req.Operation == admissionv1.Update {
+		oldObj, err := req.DecodeOldObject()
+		if err != nil {
+			return err
+		}
+		oldVM := oldObj.(*migration.VirtualMachineImport)
+
+		// Metadata changes, e.g. the removal of finalizers, are always allowed.
+		if vm.DeletionTimestamp != nil || reflect.DeepEqual(oldVM.Spec, vm.Spec) {
+			resp.Allowed = true
+			return nil
+		}
+
+		if err := validateImportSpecUpdate(oldVM); err != nil {
+			deny(resp, err)
+			return nil
+		}
+	}
+
+	if err := validateVirtualMachineImport(vm); err != nil {
+		deny(resp, err)
+		return nil
+	}
+
+	if vm.Spec.StorageClass != "" {
+		if _, err := v.scCache.Get(vm.Spec.StorageClass); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			deny(resp, fmt.Errorf("%w: '%s'", util.ErrStorageClassNotFound, vm.Spec.StorageClass))
+			return nil
+		}
+	}
+
+	resp.Allowed = true
+	return nil
+}
+
+func (v *validator) admitVmwareSource(resp *webhook.Response, req *webhook.Request) error {
+	obj, err := req.DecodeObject()
+	if err != nil {
+		return err
+	}
+	s := obj.(*migration.VmwareSource)
+
+	if s.DeletionTimestamp == nil {
+		if err := validateEndpointURL(s.Spec.EndpointAddress); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if err := v.validateSecret(s.SecretReference(), vmwareSecretKeys); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if err := validateStorageClassMapping(s.Spec.StorageClassMapping, migration.KindVmwareSource); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if err := util.ValidateResourcePolicy(s.Spec.ResourcePolicy); err != nil {
+			deny(resp, err)
+			return nil
+		}
+	}
+
+	resp.Allowed = true
+	return nil
+}
+
+func (v *validator) admitOvaSource(resp *webhook.Response, req *webhook.Request) error {
+	obj, err := req.DecodeObject()
+	if err != nil {
+		return err
+	}
+	s := obj.(*migration.OvaSource)
+
+	if s.DeletionTimestamp == nil {
+		if err := validateEndpointURL(s.Spec.Url); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if s.HasSecret() {
+			if err := v.validateSecret(s.SecretReference(), nil); err != nil {
+				deny(resp, err)
+				return nil
+			}
+		}
+		if err := util.ValidateResourcePolicy(s.Spec.ResourcePolicy); err != nil {
+			deny(resp, err)
+			return nil
+		}
+	}
+
+	resp.Allowed = true
+	return nil
+}
+
+func (v *validator) admitOpenstackSource(resp *webhook.Response, req *webhook.Request) error {
+	obj, err := req.DecodeObject()
+	if err != nil {
+		return err
+	}
+	s := obj.(*migration.OpenstackSource)
+
+	if s.DeletionTimestamp == nil {
+		if err := validateEndpointURL(s.Spec.EndpointAddress); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if err := v.validateSecret(s.SecretReference(), openstackSecretKeys); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if err := validateStorageClassMapping(s.Spec.StorageClassMapping, migration.KindOpenstackSource); err != nil {
+			deny(resp, err)
+			return nil
+		}
+		if err := util.ValidateResourcePolicy(s.Spec.ResourcePolicy); err != nil {
+			deny(resp, err)
+			return nil
+		}
+	}
+
+	resp.Allowed = true
+	return nil
+}
+
+// validateSecret checks that the referenced secret contains the given keys.
+// A secret that does not exist yet is accepted, because it might be created
+// after the source. The source controller reports it in that case.
+func (v *validator) validateSecret(ref *corev1.SecretReference, keys []string) error {
+	if ref == nil || ref.Name == "" {
+		return fmt.Errorf("no credentials secret specified")
+	}
+
+	secret, err := v.secretCache.Get(ref.Namespace, ref.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, key := range keys {
+		if _, ok := secret.Data[key]; !ok {
+			return fmt.Errorf("no key %q found in secret %s/%s", key, ref.Namespace, ref.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateVirtualMachineImport performs the checks of the spec that do not
+// depend on other objects.
+func validateVirtualMachineImport(vm *migration.VirtualMachineImport) error {
+	if vm.Spec.SourceCluster.APIVersion != migration.SchemeGroupVersion.String() {
+		return fmt.Errorf("%w: expected migration cluster apiversion to be '%s' but got '%s'",
+			util.ErrInvalidSourceCluster, migration.SchemeGroupVersion.String(), vm.Spec.SourceCluster.APIVersion)
+	}
+
+	switch strings.ToLower(vm.Spec.SourceCluster.Kind) {
+	case migration.KindVmwareSource, migration.KindOvaSource, migration.KindOpenstackSource:
+	default:
+		return fmt.Errorf("%w: unsupported source kind %q", util.ErrInvalidSourceCluster, vm.Spec.SourceCluster.Kind)
+	}
+
+	if vm.Spec.SourceCluster.Name == "" {
+		return fmt.Errorf("%w: source cluster name must not be empty", util.ErrInvalidSourceCluster)
+	}
+
+	if vm.Spec.VirtualMachineName == "" {
+		return fmt.Errorf("virtual machine name must not be empty")
+	}
+
+	if err := validateNetworkMappings(vm.Spec.Mapping); err != nil {
+		return err
+	}
+
+	if vm.Spec.DefaultDestinationNetwork != "" {
+		if err := validateDestinationNetwork(vm.Spec.DefaultDestinationNetwork); err != nil {
+			return err
+		}
+	}
+
+	for _, dm := range vm.Spec.DiskMapping {
+		if err := util.ValidateDiskMapping(dm); err != nil {
+			return err
+		}
+	}
+
+	for _, ds := range slices.Concat(vm.Spec.IncludeDisks, vm.Spec.ExcludeDisks) {
+		if err := util.ValidateDiskSelector(ds); err != nil {
+			return err
+		}
+	}
+
+	for _, mm := range vm.Spec.MetadataMapping {
+		if err := util.ValidateMetadataMapping(mm); err != nil {
+			return err
+		}
+	}
+
+	if err := util.ValidateResourcePolicy(vm.Spec.ResourcePolicy); err != nil {
+		return err
+	}
+
+	return util.ValidateImportSchedule(vm.Spec.Schedule)
+}
+
+// validateNetworkMappings checks that every source network is mapped at most
+// once and that the patterns and destination networks are valid.
+func validateNetworkMappings(mappings []migration.NetworkMapping) error {
+	sourceNetworks := make(map[string]bool)
+	for _, nm := range mappings {
+		if sourceNetworks[nm.SourceNetwork] {
+			return fmt.Errorf("%w: source network %s appears multiple times in VirtualMachineImport spec",
+				util.ErrInvalidNetworkMapping, nm.SourceNetwork)
+		}
+		sourceNetworks[nm.SourceNetwork] = true
+
+		if err := util.ValidateSourceNetworkPattern(nm); err != nil {
+			return err
+		}
+
+		if err := validateDestinationNetwork(nm.DestinationNetwork); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDestinationNetwork checks that the given destination network has
+// the format `<networkName>` or `<namespace>/<networkName>`. The name of a
+// NetworkAttachmentDefinition is a DNS-1123 subdomain and may contain dots,
+// the namespace is a DNS-1123 label.
+func validateDestinationNetwork(network string) error {
+	parts := strings.Split(network, "/")
+	if len(parts) > 2 {
+		return fmt.Errorf("%w: invalid destination network '%s'", util.ErrInvalidNetworkMapping, network)
+	}
+
+	errs := validation.IsDNS1123Subdomain(parts[len(parts)-1])
+	if len(parts) == 2 {
+		errs = append(errs, validation.IsDNS1123Label(parts[0])...)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: invalid destination network '%s': %s",
+			util.ErrInvalidNetworkMapping, network, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// validateStorageClassMapping checks the storage class mapping of a source.
+// VMware disks can only be matched by their datastore and OpenStack disks
+// only by their volume type.
+func validateStorageClassMapping(mappings []migration.StorageClassMapping, kind string) error {
+	for _, m := range mappings {
+		if err := util.ValidateStorageClassMapping(m); err != nil {
+			return err
+		}
+		if kind == migration.KindVmwareSource && m.VolumeType != "" {
+			return fmt.Errorf("%w: volumeType is not supported by VMware sources", util.ErrInvalidStorageClassMapping)
+		}
+		if kind == migration.KindOpenstackSource && m.Datastore != "" {
+			return fmt.Errorf("%w: datastore is not supported by OpenStack sources", util.ErrInvalidStorageClassMapping)
+		}
+	}
+	return nil
+}
+
+// validateImportSpecUpdate checks whether the spec of the given import may
+// still be changed. This is only allowed until the import has been started.
+func validateImportSpecUpdate(oldVM *migration.VirtualMachineImport) error {
+	if oldVM.Status.Status != "" {
+		return fmt.Errorf("the spec cannot be changed after the import has been started (status: %s)", oldVM.Status.Status)
+	}
+	return nil
+}
+
+// validateEndpointURL checks that the given endpoint is an absolute HTTP(S)
+// URL.
+func validateEndpointURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint URL %q: %v", endpoint, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q: must be 'http' or 'https'", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid endpoint URL %q: missing host", endpoint)
+	}
+
+	return nil
+}
+
+// deny rejects the admission request with the given error.
+func deny(resp *webhook.Response, err error) {
+	resp.Allowed = false
+	resp.Result = &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+		Reason:  metav1.StatusReasonInvalid,
+		Code:    http.StatusUnprocessableEntity,
+	}
+}
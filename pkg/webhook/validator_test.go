@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	migration "github.com/harvester/vm-import-controller/pkg/apis/migration.harvesterhci.io/v1beta1"
+)
+
+func Test_validateVirtualMachineImport(t *testing.T) {
+	assert := require.New(t)
+	newImport := func(mutate func(*migration.VirtualMachineImport)) *migration.VirtualMachineImport {
+		vm := &migration.VirtualMachineImport{
+			Spec: migration.VirtualMachineImportSpec{
+				SourceCluster: corev1.ObjectReference{
+					APIVersion: "migration.harvesterhci.io/v1beta1",
+					Kind:       "VmwareSource",
+					Name:       "vcsim",
+				},
+				VirtualMachineName: "vm-1234",
+				Mapping: []migration.NetworkMapping{
+					{SourceNetwork: "VM Network", DestinationNetwork: "default/vlan1"},
+					{SourceNetwork: "Other Network", DestinationNetwork: "vlan2"},
+				},
+			},
+		}
+		if mutate != nil {
+			mutate(vm)
+		}
+		return vm
+	}
+
+	testCases := []struct {
+		desc        string
+		vm          *migration.VirtualMachineImport
+		expectError bool
+	}{
+		{
+			desc: "Valid import",
+			vm:   newImport(nil),
+		},
+		{
+			desc: "Wrong API version",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.SourceCluster.APIVersion = "migration.harvesterhci.io/v1"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Unsupported kind",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.SourceCluster.Kind = "HyperVSource"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Duplicate source network",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[1].SourceNetwork = "VM Network"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Malformed destination network",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].DestinationNetwork = "default/vlan1/foo"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Destination network with invalid characters",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].DestinationNetwork = "default/VLAN_1"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Destination network with dots in the name",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].DestinationNetwork = "default/vlan.100"
+				vm.Spec.Mapping[1].DestinationNetwork = "vlan.200"
+			}),
+		},
+		{
+			desc: "Destination network with dots in the namespace",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].DestinationNetwork = "default.ns/vlan1"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Valid glob and regex source networks",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].SourceNetwork = "VLAN-*"
+				vm.Spec.Mapping[0].MatchType = ptr.To(migration.NetworkMatchTypeGlob)
+				vm.Spec.Mapping[1].SourceNetwork = "dvs-(10|20)"
+				vm.Spec.Mapping[1].MatchType = ptr.To(migration.NetworkMatchTypeRegex)
+			}),
+		},
+		{
+			desc: "Invalid regex source network",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].SourceNetwork = "dvs-(10"
+				vm.Spec.Mapping[0].MatchType = ptr.To(migration.NetworkMatchTypeRegex)
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Invalid glob source network",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.Mapping[0].SourceNetwork = "VLAN-["
+				vm.Spec.Mapping[0].MatchType = ptr.To(migration.NetworkMatchTypeGlob)
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Malformed default destination network",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.DefaultDestinationNetwork = "default/vlan1/foo"
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Valid disk mapping",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.DiskMapping = []migration.DiskMapping{
+					{Index: ptr.To(int32(1)), StorageClass: "fast"},
+				}
+			}),
+		},
+		{
+			desc: "Valid disk selection",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.IncludeDisks = []migration.DiskSelector{{Index: ptr.To(int32(0))}, {FileName: "data.vmdk"}}
+				vm.Spec.ExcludeDisks = []migration.DiskSelector{{DeviceID: "VirtualLsiLogicController0:2"}}
+			}),
+		},
+		{
+			desc: "Empty disk selector",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.ExcludeDisks = []migration.DiskSelector{{}}
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Disk mapping without selector",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.DiskMapping = []migration.DiskMapping{
+					{StorageClass: "fast"},
+				}
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Valid resource policy",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.ResourcePolicy = &migration.ResourcePolicy{ReservedMemory: "256Mi"}
+			}),
+		},
+		{
+			desc: "Invalid metadata mapping",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.MetadataMapping = []migration.MetadataMapping{{SourceKey: "tag/["}}
+			}),
+			expectError: true,
+		},
+		{
+			desc: "Invalid reserved memory",
+			vm: newImport(func(vm *migration.VirtualMachineImport) {
+				vm.Spec.ResourcePolicy = &migration.ResourcePolicy{ReservedMemory: "256 MB"}
+			}),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := validateVirtualMachineImport(tc.vm)
+		if tc.expectError {
+			assert.Error(err, tc.desc)
+		} else {
+			assert.NoError(err, tc.desc)
+		}
+	}
+}
+
+func Test_validateImportSpecUpdate(t *testing.T) {
+	assert := require.New(t)
+
+	vm := &migration.VirtualMachineImport{}
+	assert.NoError(validateImportSpecUpdate(vm), "expected spec of new import to be mutable")
+
+	vm.Status.Status = migration.SourceReady
+	assert.Error(validateImportSpecUpdate(vm), "expected spec of started import to be immutable")
+}
+
+func Test_validateStorageClassMapping(t *testing.T) {
+	assert := require.New(t)
+
+	vmwareMapping := []migration.StorageClassMapping{
+		{Datastore: "ssd-*", StorageClass: "longhorn-ssd"},
+	}
+	openstackMapping := []migration.StorageClassMapping{
+		{VolumeType: "ceph-hdd", StorageClass: "longhorn-hdd"},
+	}
+
+	assert.NoError(validateStorageClassMapping(vmwareMapping, migration.KindVmwareSource))
+	assert.NoError(validateStorageClassMapping(openstackMapping, migration.KindOpenstackSource))
+	assert.Error(validateStorageClassMapping(openstackMapping, migration.KindVmwareSource), "expected volumeType to be rejected for VMware")
+	assert.Error(validateStorageClassMapping(vmwareMapping, migration.KindOpenstackSource), "expected datastore to be rejected for OpenStack")
+	assert.Error(validateStorageClassMapping([]migration.StorageClassMapping{
+		{Datastore: "ssd-[", StorageClass: "longhorn-ssd"},
+	}, migration.KindVmwareSource), "expected invalid pattern to fail")
+}
+
+func Test_validateEndpointURL(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(validateEndpointURL("https://vcenter.example.com/sdk"))
+	assert.NoError(validateEndpointURL("http://10.0.0.1:8080/images/vm.ova"))
+	assert.Error(validateEndpointURL("ftp://10.0.0.1/vm.ova"), "expected unsupported scheme to fail")
+	assert.Error(validateEndpointURL("vcenter.example.com"), "expected missing scheme to fail")
+	assert.Error(validateEndpointURL("https://"), "expected missing host to fail")
+}